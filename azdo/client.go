@@ -7,11 +7,16 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"unicode/utf8"
 )
 
 // Client is an HTTP client for the Azure DevOps REST API.
@@ -23,7 +28,61 @@ type Client struct {
 	Team         string
 	AreaPath     string
 	PAT          string
+	BaseURL      string // Azure DevOps Server URL, e.g. https://tfs.example.com/tfs (empty uses DefaultBaseURL)
 	httpClient   *http.Client
+	debugLog     *log.Logger      // logs every request/response when set
+	idCache      *idListCache     // ordered query results, used to page without re-querying
+	deprecation  string           // deprecation notice returned by the last TestConnection
+	warning      string           // other warning returned by the last TestConnection
+	teamAreas    *TeamFieldValues // the team's area paths, used when AreaPath is empty
+	fieldTypes   *fieldTypeCache  // the organization's field types, fetched once
+}
+
+// fieldTypeCache holds the field types returned by GetFieldTypes, which rarely change
+type fieldTypeCache struct {
+	mu    sync.Mutex
+	types map[string]string
+}
+
+func (c *fieldTypeCache) get() map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.types
+}
+
+func (c *fieldTypeCache) set(types map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.types = types
+}
+
+// idListCache holds the full ordered ID list returned by each WIQL query
+type idListCache struct {
+	mu    sync.Mutex
+	lists map[string][]int
+}
+
+func newIDListCache() *idListCache {
+	return &idListCache{lists: make(map[string][]int)}
+}
+
+func (c *idListCache) get(query string) ([]int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ids, ok := c.lists[query]
+	return ids, ok
+}
+
+func (c *idListCache) set(query string, ids []int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lists[query] = ids
+}
+
+func (c *idListCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lists = make(map[string][]int)
 }
 
 // WorkItem represents an Azure DevOps work item with its fields and relations.
@@ -35,6 +94,42 @@ type WorkItem struct {
 	Relations []WorkItemRelation `json:"relations,omitempty"`
 }
 
+// ParentID returns the ID of the work item's parent, or 0 when it has none.
+// Relations are only present when the item was fetched with them expanded.
+func (wi WorkItem) ParentID() int {
+	for _, rel := range wi.Relations {
+		if rel.Rel == "System.LinkTypes.Hierarchy-Reverse" {
+			return extractWorkItemIDFromURL(rel.URL)
+		}
+	}
+	return 0
+}
+
+// BlockedTag is the tag that marks a work item as blocked
+const BlockedTag = "Blocked"
+
+// IsBlocked reports whether the work item is blocked: flagged by the CMMI Blocked field,
+// tagged Blocked, or, when linkType is set, having a relation of that type
+// (e.g. "System.LinkTypes.Dependency-Reverse" for a predecessor).
+func (wi WorkItem) IsBlocked(linkType string) bool {
+	if strings.EqualFold(wi.Fields.Blocked, "Yes") {
+		return true
+	}
+	for _, tag := range strings.Split(wi.Fields.Tags, ";") {
+		if strings.EqualFold(strings.TrimSpace(tag), BlockedTag) {
+			return true
+		}
+	}
+	if linkType != "" {
+		for _, rel := range wi.Relations {
+			if rel.Rel == linkType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // WorkItemRelation represents a link between work items or to external resources.
 type WorkItemRelation struct {
 	Rel        string                 `json:"rel"`
@@ -58,16 +153,59 @@ type WorkItemFields struct {
 	Description   string       `json:"System.Description"`
 	AreaPath      string       `json:"System.AreaPath"`
 	IterationPath string       `json:"System.IterationPath"`
-	Priority      int          `json:"Microsoft.VSTS.Common.Priority"`
+	Priority      *int         `json:"Microsoft.VSTS.Common.Priority"` // nil when the field is absent (e.g. field-level security)
+	Activity      string       `json:"Microsoft.VSTS.Common.Activity"` // kind of work on a Task (e.g. Development, Testing)
 	Tags          string       `json:"System.Tags"`
+	Reason        string       `json:"System.Reason"`
 	CommentCount  int          `json:"System.CommentCount"`
 	ChangedDate   string       `json:"System.ChangedDate"`
+	CreatedBy     *IdentityRef `json:"System.CreatedBy"`
+	CreatedDate   string       `json:"System.CreatedDate"`
+	Blocked       string       `json:"Microsoft.VSTS.CMMI.Blocked"` // "Yes" when flagged blocked (CMMI process)
 	// Planning fields
 	StoryPoints      *float64 `json:"Microsoft.VSTS.Scheduling.StoryPoints,omitempty"`
 	OriginalEstimate *float64 `json:"Microsoft.VSTS.Scheduling.OriginalEstimate,omitempty"`
 	RemainingWork    *float64 `json:"Microsoft.VSTS.Scheduling.RemainingWork,omitempty"`
 	CompletedWork    *float64 `json:"Microsoft.VSTS.Scheduling.CompletedWork,omitempty"`
 	Effort           *float64 `json:"Microsoft.VSTS.Scheduling.Effort,omitempty"`
+	// Every numeric scheduling field by reference name, including ones without a field above
+	Scheduling map[string]float64 `json:"-"`
+}
+
+// UnmarshalJSON decodes the fields, collecting the numeric scheduling fields in Scheduling
+func (f *WorkItemFields) UnmarshalJSON(data []byte) error {
+	type fields WorkItemFields // without this method
+	if err := json.Unmarshal(data, (*fields)(f)); err != nil {
+		return err
+	}
+	var all map[string]any
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	for name, value := range all {
+		if n, ok := value.(float64); ok && strings.HasPrefix(name, schedulingFieldPrefix) {
+			if f.Scheduling == nil {
+				f.Scheduling = make(map[string]float64)
+			}
+			f.Scheduling[name] = n
+		}
+	}
+	return nil
+}
+
+// PlanningValue returns the value of a numeric scheduling field, or nil when it isn't set
+func (f WorkItemFields) PlanningValue(referenceName string) *float64 {
+	if v, ok := f.Scheduling[referenceName]; ok {
+		return &v
+	}
+	known := map[string]*float64{
+		"Microsoft.VSTS.Scheduling.StoryPoints":      f.StoryPoints,
+		"Microsoft.VSTS.Scheduling.OriginalEstimate": f.OriginalEstimate,
+		"Microsoft.VSTS.Scheduling.RemainingWork":    f.RemainingWork,
+		"Microsoft.VSTS.Scheduling.CompletedWork":    f.CompletedWork,
+		"Microsoft.VSTS.Scheduling.Effort":           f.Effort,
+	}
+	return known[referenceName]
 }
 
 // IdentityRef represents a user identity in Azure DevOps.
@@ -86,8 +224,10 @@ type Comment struct {
 
 // CommentsResponse is the API response when fetching work item comments.
 type CommentsResponse struct {
-	Count    int       `json:"count"`
-	Comments []Comment `json:"comments"`
+	Count             int       `json:"count"`
+	TotalCount        int       `json:"totalCount"`
+	Comments          []Comment `json:"comments"`
+	ContinuationToken string    `json:"continuationToken"` // Set when more comments are available
 }
 
 // WorkItemQueryResult is the API response from a WIQL query.
@@ -130,6 +270,33 @@ type WorkItemTypesResponse struct {
 	Value []WorkItemType `json:"value"`
 }
 
+// WorkItemTag is a tag defined in the project.
+type WorkItemTag struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// TagsResponse is the API response when fetching the project's tags.
+type TagsResponse struct {
+	Count int           `json:"count"`
+	Value []WorkItemTag `json:"value"`
+}
+
+// TeamFieldValue is one area path owned by a team
+type TeamFieldValue struct {
+	Value           string `json:"value"`
+	IncludeChildren bool   `json:"includeChildren"`
+}
+
+// TeamFieldValues is the API response describing which area paths belong to a team
+type TeamFieldValues struct {
+	Field struct {
+		ReferenceName string `json:"referenceName"`
+	} `json:"field"`
+	DefaultValue string           `json:"defaultValue"`
+	Values       []TeamFieldValue `json:"values"`
+}
+
 // Iteration represents a sprint or iteration in Azure DevOps.
 type Iteration struct {
 	ID         string               `json:"id"`
@@ -151,6 +318,15 @@ type IterationsResponse struct {
 	Value []Iteration `json:"value"`
 }
 
+// ClassificationNode represents a node in the area or iteration tree.
+type ClassificationNode struct {
+	ID          int                  `json:"id"`
+	Name        string               `json:"name"`
+	Path        string               `json:"path"`
+	HasChildren bool                 `json:"hasChildren"`
+	Children    []ClassificationNode `json:"children,omitempty"`
+}
+
 // WorkItemTypeField represents a field definition for a work item type.
 type WorkItemTypeField struct {
 	ReferenceName  string      `json:"referenceName"`
@@ -158,6 +334,54 @@ type WorkItemTypeField struct {
 	AlwaysRequired bool        `json:"alwaysRequired"`
 	DefaultValue   interface{} `json:"defaultValue"`
 	ReadOnly       bool        `json:"readOnly"`
+	AllowedValues  []string    `json:"allowedValues"` // Only returned when requested with $expand=allowedValues
+}
+
+// WorkItemState is a state of a work item type together with the states it can move to.
+type WorkItemState struct {
+	Name        string
+	Category    string   // e.g. Proposed, InProgress, Resolved, Completed, Removed
+	Transitions []string // States reachable from this one
+}
+
+// workItemTypeStatesResponse is the part of the work item type API response that describes its workflow.
+type workItemTypeStatesResponse struct {
+	States []struct {
+		Name     string `json:"name"`
+		Category string `json:"category"`
+	} `json:"states"`
+	Transitions map[string][]struct {
+		To string `json:"to"`
+	} `json:"transitions"`
+}
+
+// DeletedWorkItem represents a work item in the project's recycle bin.
+type DeletedWorkItem struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	DeletedBy   string `json:"deletedBy"`
+	DeletedDate string `json:"deletedDate"`
+}
+
+// RecycleBinResponse is the API response when listing the recycle bin.
+type RecycleBinResponse struct {
+	Count int               `json:"count"`
+	Value []DeletedWorkItem `json:"value"`
+}
+
+// FieldDefinition describes an organization-wide work item field.
+type FieldDefinition struct {
+	ReferenceName string `json:"referenceName"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	ReadOnly      bool   `json:"readOnly"`
+}
+
+// FieldsResponse is the API response when listing all fields.
+type FieldsResponse struct {
+	Count int               `json:"count"`
+	Value []FieldDefinition `json:"value"`
 }
 
 // WorkItemTypeFieldsResponse is the API response when fetching fields for a work item type.
@@ -166,6 +390,56 @@ type WorkItemTypeFieldsResponse struct {
 	Value []WorkItemTypeField `json:"value"`
 }
 
+// APIError is returned when Azure DevOps responds with an unexpected status code.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether err is an API error with a 404 status, such as
+// when a work item has been deleted.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// WorkItemFilter describes the criteria used to list work items on the board.
+// Empty fields are not applied to the query.
+type WorkItemFilter struct {
+	WorkItemType  string       // Only include items of this type
+	AssignedTo    string       // Only include items assigned to this user
+	ExcludeStates []string     // Exclude items in any of these states
+	IterationPath string       // Only include items in this iteration or its children
+	Search        string       // Only include items whose ID, title or tags match this text
+	Sort          WorkItemSort // Result order (defaults to most recently changed first)
+	// Only include items in the team's current iteration, using the @CurrentIteration macro
+	// so the board follows the sprint as it changes
+	CurrentIteration bool
+}
+
+// WorkItemSort is the server-side order of query results
+type WorkItemSort struct {
+	Field     string // Field reference name, e.g. System.CreatedDate (empty means System.ChangedDate)
+	Ascending bool
+}
+
+// orderBy returns the WIQL ORDER BY clause for the sort
+func (s WorkItemSort) orderBy() string {
+	field := s.Field
+	if field == "" {
+		field = "System.ChangedDate"
+	}
+	direction := "DESC"
+	if s.Ascending {
+		direction = "ASC"
+	}
+	return fmt.Sprintf(" ORDER BY [%s] %s", field, direction)
+}
+
 // PlanningField represents a planning field that can be displayed/edited
 type PlanningField struct {
 	ReferenceName string   // Azure DevOps field reference name
@@ -175,14 +449,87 @@ type PlanningField struct {
 
 // NewClient creates a new Azure DevOps API client with the given configuration.
 func NewClient(org, project, team, areaPath, pat string) *Client {
+	return NewClientWithHTTP(org, project, team, areaPath, pat, nil)
+}
+
+// NewClientWithHTTP creates a new Azure DevOps API client that sends requests
+// through hc, allowing custom transports (proxies, instrumentation, mocks).
+// A nil hc falls back to a default http.Client.
+func NewClientWithHTTP(org, project, team, areaPath, pat string, hc *http.Client) *Client {
+	if hc == nil {
+		hc = &http.Client{}
+	}
 	return &Client{
 		Organization: org,
 		Project:      project,
 		Team:         team,
 		AreaPath:     areaPath,
 		PAT:          pat,
-		httpClient:   &http.Client{},
+		httpClient:   hc,
+		idCache:      newIDListCache(),
+		fieldTypes:   &fieldTypeCache{},
+	}
+}
+
+// NewHTTPClient returns an http.Client for talking to Azure DevOps.
+// With an empty proxyURL the default transport is used, which already honours the
+// standard HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables. Otherwise
+// every request is sent through the given proxy.
+func NewHTTPClient(proxyURL string) (*http.Client, error) {
+	if proxyURL == "" {
+		return &http.Client{}, nil
+	}
+
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme and host are required", proxyURL)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(u)
+	return &http.Client{Transport: transport}, nil
+}
+
+// SetDebugLog enables logging of every request's method, URL, status and
+// truncated response body to w. Pass nil to disable logging.
+func (c *Client) SetDebugLog(w io.Writer) {
+	if w == nil {
+		c.debugLog = nil
+		return
 	}
+	c.debugLog = log.New(w, "", log.LstdFlags)
+}
+
+// debugBodyLimit is the maximum number of response body characters written to the debug log
+const debugBodyLimit = 500
+
+// doRequest sends req and, when debug logging is enabled, records the exchange
+func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
+	if c.debugLog == nil {
+		return c.httpClient.Do(req)
+	}
+
+	// Capture the target before sending, since transports may rewrite the request
+	method, target := req.Method, req.URL.String()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.debugLog.Printf("%s %s -> error: %v", method, target, err)
+		return resp, err
+	}
+
+	// Read the body for logging, then restore it for the caller
+	body, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr != nil {
+		c.debugLog.Printf("%s %s -> %d (body read error: %v)", method, target, resp.StatusCode, readErr)
+		return nil, readErr
+	}
+	c.debugLog.Printf("%s %s -> %d %s", method, target, resp.StatusCode, truncateError(string(body), debugBodyLimit))
+	return resp, nil
 }
 
 func (c *Client) authHeader() string {
@@ -190,17 +537,61 @@ func (c *Client) authHeader() string {
 	return "Basic " + auth
 }
 
+// DefaultBaseURL is the Azure DevOps Services host used when Client.BaseURL is empty
+const DefaultBaseURL = "https://dev.azure.com"
+
+// OrganizationURL returns the URL of the organization, or of the collection on
+// Azure DevOps Server, where the Organization field holds the collection name.
+func (c *Client) OrganizationURL() string {
+	host := DefaultBaseURL
+	if c.BaseURL != "" {
+		host = strings.TrimRight(c.BaseURL, "/")
+	}
+	return fmt.Sprintf("%s/%s", host, c.Organization)
+}
+
 func (c *Client) baseURL() string {
-	return fmt.Sprintf("https://dev.azure.com/%s/%s", c.Organization, c.Project)
+	return fmt.Sprintf("%s/%s", c.OrganizationURL(), c.Project)
 }
 
 func (c *Client) teamURL() string {
 	if c.Team != "" {
-		return fmt.Sprintf("https://dev.azure.com/%s/%s/%s", c.Organization, c.Project, c.Team)
+		return fmt.Sprintf("%s/%s/%s", c.OrganizationURL(), c.Project, c.Team)
 	}
 	return c.baseURL()
 }
 
+// WorkItemWebURL returns the browser URL for viewing a work item in Azure DevOps.
+func (c *Client) WorkItemWebURL(workItemID int) string {
+	return fmt.Sprintf("%s/_workitems/edit/%d", c.baseURL(), workItemID)
+}
+
+// BacklogWebURL returns the browser URL of the team's board for a backlog level such as
+// "Stories" or "Features". Without a team the project's default team, "{project} Team", is used.
+// An empty backlog opens the team's default board.
+func (c *Client) BacklogWebURL(backlog string) string {
+	boardURL := fmt.Sprintf("%s/_boards/board/t/%s", c.baseURL(), url.PathEscape(c.teamOrDefault()))
+	if backlog != "" {
+		boardURL += "/" + url.PathEscape(backlog)
+	}
+	return boardURL
+}
+
+// teamOrDefault returns the configured team, or the project's default team "{project} Team"
+func (c *Client) teamOrDefault() string {
+	if c.Team == "" {
+		return c.Project + " Team"
+	}
+	return c.Team
+}
+
+// currentIterationMacro returns the WIQL @CurrentIteration macro for the team, e.g.
+// @CurrentIteration('[Fabrikam]\Fabrikam Team'). Quotes in the names are escaped.
+func (c *Client) currentIterationMacro() string {
+	team := fmt.Sprintf("[%s]\\%s", c.Project, c.teamOrDefault())
+	return fmt.Sprintf("@CurrentIteration('%s')", wiqlEscape(team))
+}
+
 // GetWorkItems fetches work items of the specified type, limited to top results.
 func (c *Client) GetWorkItems(workItemType string, top int) ([]WorkItem, error) {
 	return c.GetWorkItemsFiltered(workItemType, "", top)
@@ -211,74 +602,252 @@ func (c *Client) GetWorkItemsFiltered(workItemType, assignedTo string, top int)
 	return c.GetWorkItemsPaged(workItemType, assignedTo, top, 0)
 }
 
+// GetMyWorkItems fetches the most recently changed work items assigned to the given user.
+func (c *Client) GetMyWorkItems(username string, top int) ([]WorkItem, error) {
+	if username == "" {
+		return nil, fmt.Errorf("username is required")
+	}
+	return c.QueryWorkItems(WorkItemFilter{AssignedTo: username}, top, 0)
+}
+
 // GetWorkItemsPaged fetches work items with pagination support
 func (c *Client) GetWorkItemsPaged(workItemType, assignedTo string, top int, skip int) ([]WorkItem, error) {
+	return c.QueryWorkItems(WorkItemFilter{WorkItemType: workItemType, AssignedTo: assignedTo}, top, skip)
+}
+
+// buildWorkItemQuery builds the WIQL query used to list work items for the given filter
+func (c *Client) buildWorkItemQuery(filter WorkItemFilter) string {
 	query := fmt.Sprintf("SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = '%s'", c.Project)
-	if workItemType != "" {
-		query += fmt.Sprintf(" AND [System.WorkItemType] = '%s'", workItemType)
+	if filter.WorkItemType != "" {
+		query += fmt.Sprintf(" AND [System.WorkItemType] = '%s'", filter.WorkItemType)
 	}
-	if assignedTo != "" {
-		query += fmt.Sprintf(" AND [System.AssignedTo] = '%s'", assignedTo)
+	if filter.AssignedTo != "" {
+		query += fmt.Sprintf(" AND [System.AssignedTo] = '%s'", filter.AssignedTo)
 	}
-	if c.AreaPath != "" {
-		query += fmt.Sprintf(" AND [System.AreaPath] UNDER '%s'", c.AreaPath)
+	if len(filter.ExcludeStates) > 0 {
+		quoted := make([]string, len(filter.ExcludeStates))
+		for i, state := range filter.ExcludeStates {
+			quoted[i] = fmt.Sprintf("'%s'", state)
+		}
+		query += fmt.Sprintf(" AND [System.State] NOT IN (%s)", strings.Join(quoted, ", "))
 	}
-	query += " ORDER BY [System.ChangedDate] DESC"
+	if filter.IterationPath != "" {
+		query += fmt.Sprintf(" AND [System.IterationPath] UNDER '%s'", wiqlEscape(filter.IterationPath))
+	}
+	if filter.CurrentIteration {
+		query += " AND [System.IterationPath] = " + c.currentIterationMacro()
+	}
+	query += searchClause(filter.Search)
+	query += c.areaClause()
+	query += filter.Sort.orderBy()
+	return query
+}
 
-	// Use team URL for WIQL queries when team is specified - the team context
-	// automatically scopes queries to the team's configured area paths
-	// Note: WIQL doesn't support $skip directly, so we fetch top+skip and slice
-	wiqlURL := fmt.Sprintf("%s/_apis/wit/wiql?api-version=7.0&$top=%d", c.teamURL(), top+skip)
+// wiqlEscape escapes s for use inside a single-quoted WIQL string
+func wiqlEscape(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
 
-	body := map[string]string{"query": query}
+// searchClause returns the WIQL condition matching text in an item's title or tags,
+// or its ID when text is a number (optionally written as #123)
+func searchClause(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	quoted := wiqlEscape(text)
+	conditions := []string{
+		fmt.Sprintf("[System.Title] CONTAINS '%s'", quoted),
+		fmt.Sprintf("[System.Tags] CONTAINS '%s'", quoted),
+	}
+	if id, err := strconv.Atoi(strings.TrimPrefix(text, "#")); err == nil && id > 0 {
+		conditions = append(conditions, fmt.Sprintf("[System.Id] = %d", id))
+	}
+	return fmt.Sprintf(" AND (%s)", strings.Join(conditions, " OR "))
+}
+
+// savedQueryColumns are the columns a board query is saved with, matching the board
+const savedQueryColumns = "[System.Id], [System.WorkItemType], [System.Title], [System.AssignedTo], [System.State], [System.Tags]"
+
+// myQueriesFolder is the query folder that holds the user's own saved queries
+const myQueriesFolder = "My Queries"
+
+// SavedQuery is a query stored in the project's query tree.
+type SavedQuery struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Wiql string `json:"wiql"`
+}
+
+// SavedQueryWIQL returns the board query for filter with the board's columns selected,
+// ready to be saved with CreateSavedQuery
+func (c *Client) SavedQueryWIQL(filter WorkItemFilter) string {
+	return strings.Replace(c.buildWorkItemQuery(filter), "SELECT [System.Id]", "SELECT "+savedQueryColumns, 1)
+}
+
+// CreateSavedQuery saves a WIQL query under name in My Queries
+func (c *Client) CreateSavedQuery(name, wiql string) (*SavedQuery, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("query name is required")
+	}
+	queryURL := fmt.Sprintf("%s/_apis/wit/queries/%s?api-version=7.0", c.baseURL(), url.PathEscape(myQueriesFolder))
+
+	body := map[string]string{"name": name, "wiql": wiql}
 	jsonBody, _ := json.Marshal(body)
 
-	req, err := http.NewRequest("POST", wiqlURL, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequest("POST", queryURL, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Authorization", c.authHeader())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
-	var queryResult WorkItemQueryResult
-	if err := json.NewDecoder(resp.Body).Decode(&queryResult); err != nil {
+	var query SavedQuery
+	if err := json.NewDecoder(resp.Body).Decode(&query); err != nil {
 		return nil, err
 	}
+	return &query, nil
+}
 
-	if len(queryResult.WorkItems) == 0 {
-		return []WorkItem{}, nil
+// QueryWorkItems fetches a page of work items matching the given filter
+func (c *Client) QueryWorkItems(filter WorkItemFilter, top int, skip int) ([]WorkItem, error) {
+	query := c.buildWorkItemQuery(filter)
+
+	// WIQL can't skip, so the full ordered ID list is fetched once per query and
+	// cached; later pages only fetch the work items in their window. The first
+	// page always re-runs the query so refreshes pick up new and changed items.
+	var ids []int
+	cached := false
+	if c.idCache != nil && skip > 0 {
+		ids, cached = c.idCache.get(query)
+	}
+	if !cached {
+		var err error
+		ids, err = c.queryWorkItemIDs(query)
+		if err != nil {
+			return nil, err
+		}
+		if c.idCache != nil {
+			c.idCache.set(query, ids)
+		}
 	}
 
-	// Skip items for pagination
-	workItemRefs := queryResult.WorkItems
-	if skip > 0 && skip < len(workItemRefs) {
-		workItemRefs = workItemRefs[skip:]
-	} else if skip >= len(workItemRefs) {
+	if skip >= len(ids) {
 		return []WorkItem{}, nil
 	}
+	window := ids[skip:]
+	if len(window) > top {
+		window = window[:top]
+	}
+
+	return c.GetWorkItemsByIDs(window)
+}
 
-	// Limit to top items
-	if len(workItemRefs) > top {
-		workItemRefs = workItemRefs[:top]
+// CountWorkItems returns how many work items match the given filter.
+// It reuses the cached ID list when the query has already been run.
+func (c *Client) CountWorkItems(filter WorkItemFilter) (int, error) {
+	query := c.buildWorkItemQuery(filter)
+	if c.idCache != nil {
+		if ids, ok := c.idCache.get(query); ok {
+			return len(ids), nil
+		}
+	}
+	ids, err := c.queryWorkItemIDs(query)
+	if err != nil {
+		return 0, err
+	}
+	if c.idCache != nil {
+		c.idCache.set(query, ids)
 	}
+	return len(ids), nil
+}
 
-	ids := make([]string, len(workItemRefs))
-	for i, wi := range workItemRefs {
-		ids[i] = fmt.Sprintf("%d", wi.ID)
+// GetWorkItemsByIDs fetches work items (with relations) in the order of ids
+func (c *Client) GetWorkItemsByIDs(ids []int) ([]WorkItem, error) {
+	// The work items endpoint accepts at most 200 IDs per request
+	items := make([]WorkItem, 0, len(ids))
+	for start := 0; start < len(ids); start += maxIDsPerRequest {
+		end := start + maxIDsPerRequest
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batch := make([]string, 0, end-start)
+		for _, id := range ids[start:end] {
+			batch = append(batch, fmt.Sprintf("%d", id))
+		}
+		batchItems, err := c.getWorkItemsByIDs(batch)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, batchItems...)
 	}
 
-	return c.getWorkItemsByIDs(ids)
+	return items, nil
+}
+
+// ClearQueryCache drops all cached query results so the next page fetch re-runs its query
+func (c *Client) ClearQueryCache() {
+	if c.idCache != nil {
+		c.idCache.clear()
+	}
+}
+
+const (
+	// wiqlMaxResults is the largest result set a WIQL query can return
+	wiqlMaxResults = 20000
+	// maxIDsPerRequest is the most work items that can be fetched by ID in one request
+	maxIDsPerRequest = 200
+)
+
+// queryWorkItemIDs runs a WIQL query and returns the ordered IDs of every match
+func (c *Client) queryWorkItemIDs(query string) ([]int, error) {
+	// Use team URL for WIQL queries when team is specified - the team context
+	// automatically scopes queries to the team's configured area paths
+	wiqlURL := fmt.Sprintf("%s/_apis/wit/wiql?api-version=7.0&$top=%d", c.teamURL(), wiqlMaxResults)
+
+	body := map[string]string{"query": query}
+	jsonBody, _ := json.Marshal(body)
+
+	req, err := http.NewRequest("POST", wiqlURL, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var queryResult WorkItemQueryResult
+	if err := json.NewDecoder(resp.Body).Decode(&queryResult); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(queryResult.WorkItems))
+	for i, ref := range queryResult.WorkItems {
+		ids[i] = ref.ID
+	}
+	return ids, nil
 }
 
 func (c *Client) getWorkItemsByIDs(ids []string) ([]WorkItem, error) {
@@ -302,7 +871,7 @@ func (c *Client) getWorkItemsByIDs(ids []string) ([]WorkItem, error) {
 	}
 	req.Header.Set("Authorization", c.authHeader())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -310,7 +879,7 @@ func (c *Client) getWorkItemsByIDs(ids []string) ([]WorkItem, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var result WorkItemListResponse
@@ -328,6 +897,12 @@ func (c *Client) CreateWorkItem(workItemType, title, description string, priorit
 
 // CreateWorkItemWithAssignee creates a new work item with an optional assignee.
 func (c *Client) CreateWorkItemWithAssignee(workItemType, title, description string, priority int, assignedTo string) (*WorkItem, error) {
+	return c.CreateWorkItemWithTags(workItemType, title, description, priority, assignedTo, "")
+}
+
+// CreateWorkItemWithTags creates a new work item with an optional assignee and tags,
+// setting every field in the one create request.
+func (c *Client) CreateWorkItemWithTags(workItemType, title, description string, priority int, assignedTo, tags string) (*WorkItem, error) {
 	createURL := fmt.Sprintf("%s/_apis/wit/workitems/$%s?api-version=7.0", c.baseURL(), url.PathEscape(workItemType))
 
 	ops := []CreateWorkItemOp{
@@ -339,12 +914,15 @@ func (c *Client) CreateWorkItemWithAssignee(workItemType, title, description str
 	if priority > 0 {
 		ops = append(ops, CreateWorkItemOp{Op: "add", Path: "/fields/Microsoft.VSTS.Common.Priority", Value: priority})
 	}
-	if c.AreaPath != "" {
-		ops = append(ops, CreateWorkItemOp{Op: "add", Path: "/fields/System.AreaPath", Value: c.AreaPath})
+	if areaPath := c.defaultAreaPath(); areaPath != "" {
+		ops = append(ops, CreateWorkItemOp{Op: "add", Path: "/fields/System.AreaPath", Value: areaPath})
 	}
 	if assignedTo != "" {
 		ops = append(ops, CreateWorkItemOp{Op: "add", Path: "/fields/System.AssignedTo", Value: assignedTo})
 	}
+	if tags != "" {
+		ops = append(ops, CreateWorkItemOp{Op: "add", Path: "/fields/System.Tags", Value: tags})
+	}
 
 	jsonBody, _ := json.Marshal(ops)
 
@@ -355,7 +933,7 @@ func (c *Client) CreateWorkItemWithAssignee(workItemType, title, description str
 	req.Header.Set("Authorization", c.authHeader())
 	req.Header.Set("Content-Type", "application/json-patch+json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -363,7 +941,7 @@ func (c *Client) CreateWorkItemWithAssignee(workItemType, title, description str
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var workItem WorkItem
@@ -392,8 +970,8 @@ func (c *Client) CreateWorkItemWithParentAndAssignee(workItemType, title, descri
 	if priority > 0 {
 		ops = append(ops, CreateWorkItemOp{Op: "add", Path: "/fields/Microsoft.VSTS.Common.Priority", Value: priority})
 	}
-	if c.AreaPath != "" {
-		ops = append(ops, CreateWorkItemOp{Op: "add", Path: "/fields/System.AreaPath", Value: c.AreaPath})
+	if areaPath := c.defaultAreaPath(); areaPath != "" {
+		ops = append(ops, CreateWorkItemOp{Op: "add", Path: "/fields/System.AreaPath", Value: areaPath})
 	}
 	if assignedTo != "" {
 		ops = append(ops, CreateWorkItemOp{Op: "add", Path: "/fields/System.AssignedTo", Value: assignedTo})
@@ -419,7 +997,7 @@ func (c *Client) CreateWorkItemWithParentAndAssignee(workItemType, title, descri
 	req.Header.Set("Authorization", c.authHeader())
 	req.Header.Set("Content-Type", "application/json-patch+json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -427,7 +1005,7 @@ func (c *Client) CreateWorkItemWithParentAndAssignee(workItemType, title, descri
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var workItem WorkItem
@@ -440,16 +1018,94 @@ func (c *Client) CreateWorkItemWithParentAndAssignee(workItemType, title, descri
 
 // AddChildLink adds a child link from parentID to childID
 func (c *Client) AddChildLink(parentID, childID int) error {
-	updateURL := fmt.Sprintf("%s/_apis/wit/workitems/%d?api-version=7.0", c.baseURL(), parentID)
+	return c.addHierarchyLink(parentID, childID, "System.LinkTypes.Hierarchy-Forward")
+}
+
+// AddParentLink adds a parent link from childID to parentID
+func (c *Client) AddParentLink(childID, parentID int) error {
+	return c.addHierarchyLink(childID, parentID, "System.LinkTypes.Hierarchy-Reverse")
+}
+
+// maxHierarchyDepth bounds the walk up a parent chain when looking for cycles
+const maxHierarchyDepth = 100
+
+// CheckHierarchyLink returns an error if making parentID the parent of childID would
+// duplicate an existing link, give childID a second parent, or create a cycle.
+// Azure DevOps rejects all of these, but without saying why.
+func (c *Client) CheckHierarchyLink(parentID, childID int) error {
+	if parentID == childID {
+		return fmt.Errorf("#%d can't be linked to itself", childID)
+	}
+	child, err := c.GetWorkItemWithRelations(childID)
+	if err != nil {
+		return err
+	}
+	if err := checkExistingParent(childID, child.ParentID(), parentID); err != nil {
+		return err
+	}
+	cycle, err := isAncestor(childID, parentID, c.parentOf)
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return fmt.Errorf("#%d is an ancestor of #%d, so linking it as a child would create a cycle", childID, parentID)
+	}
+	return nil
+}
+
+// checkExistingParent returns an error if a child whose current parent is currentParentID
+// can't be given parentID as its parent
+func checkExistingParent(childID, currentParentID, parentID int) error {
+	switch currentParentID {
+	case 0:
+		return nil
+	case parentID:
+		return fmt.Errorf("#%d is already a child of #%d", childID, parentID)
+	default:
+		return fmt.Errorf("#%d already has parent #%d; remove that link first", childID, currentParentID)
+	}
+}
+
+// isAncestor reports whether ancestorID is id or one of id's ancestors,
+// looking up each parent with parentOf
+func isAncestor(ancestorID, id int, parentOf func(int) (int, error)) (bool, error) {
+	for range maxHierarchyDepth {
+		if id == ancestorID {
+			return true, nil
+		}
+		if id == 0 {
+			return false, nil
+		}
+		parent, err := parentOf(id)
+		if err != nil {
+			return false, err
+		}
+		id = parent
+	}
+	return false, nil
+}
+
+// parentOf returns the ID of a work item's parent, or 0 when it has none
+func (c *Client) parentOf(id int) (int, error) {
+	wi, err := c.GetWorkItemWithRelations(id)
+	if err != nil {
+		return 0, err
+	}
+	return wi.ParentID(), nil
+}
+
+// addHierarchyLink adds a relation of the given hierarchy type from workItemID to targetID
+func (c *Client) addHierarchyLink(workItemID, targetID int, relType string) error {
+	updateURL := fmt.Sprintf("%s/_apis/wit/workitems/%d?api-version=7.0", c.baseURL(), workItemID)
 
-	childURL := fmt.Sprintf("%s/_apis/wit/workItems/%d", c.baseURL(), childID)
+	targetURL := fmt.Sprintf("%s/_apis/wit/workItems/%d", c.baseURL(), targetID)
 	ops := []CreateWorkItemOp{
 		{
 			Op:   "add",
 			Path: "/relations/-",
 			Value: map[string]interface{}{
-				"rel": "System.LinkTypes.Hierarchy-Forward",
-				"url": childURL,
+				"rel": relType,
+				"url": targetURL,
 			},
 		},
 	}
@@ -463,7 +1119,7 @@ func (c *Client) AddChildLink(parentID, childID int) error {
 	req.Header.Set("Authorization", c.authHeader())
 	req.Header.Set("Content-Type", "application/json-patch+json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return err
 	}
@@ -471,7 +1127,7 @@ func (c *Client) AddChildLink(parentID, childID int) error {
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	return nil
@@ -497,7 +1153,7 @@ func (c *Client) RemoveRelation(workItemID int, relationIndex int) error {
 	req.Header.Set("Authorization", c.authHeader())
 	req.Header.Set("Content-Type", "application/json-patch+json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return err
 	}
@@ -505,7 +1161,7 @@ func (c *Client) RemoveRelation(workItemID int, relationIndex int) error {
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	return nil
@@ -547,7 +1203,7 @@ func (c *Client) GetWorkItemTypes() ([]string, error) {
 	}
 	req.Header.Set("Authorization", c.authHeader())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -555,7 +1211,7 @@ func (c *Client) GetWorkItemTypes() ([]string, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var result WorkItemTypesResponse
@@ -575,17 +1231,17 @@ func (c *Client) GetWorkItemTypes() ([]string, error) {
 	return types, nil
 }
 
-// GetComments fetches all comments for a work item.
-func (c *Client) GetComments(workItemID int) ([]Comment, error) {
-	commentsURL := fmt.Sprintf("%s/_apis/wit/workitems/%d/comments?api-version=7.0-preview.3", c.baseURL(), workItemID)
+// GetTags fetches the names of all tags defined in the project.
+func (c *Client) GetTags() ([]string, error) {
+	tagsURL := fmt.Sprintf("%s/_apis/wit/tags?api-version=7.0-preview.1", c.baseURL())
 
-	req, err := http.NewRequest("GET", commentsURL, nil)
+	req, err := http.NewRequest("GET", tagsURL, nil)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Authorization", c.authHeader())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -593,15 +1249,84 @@ func (c *Client) GetComments(workItemID int) ([]Comment, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
-	var result CommentsResponse
+	var result TagsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
 
-	return result.Comments, nil
+	var tags []string
+	for _, tag := range result.Value {
+		if tag.Name != "" {
+			tags = append(tags, tag.Name)
+		}
+	}
+
+	return tags, nil
+}
+
+// GetComments fetches all comments for a work item, following continuation tokens across pages.
+func (c *Client) GetComments(workItemID int) ([]Comment, error) {
+	var all []Comment
+	token := ""
+	for {
+		comments, next, err := c.GetCommentsPage(workItemID, token)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, comments...)
+		if next == "" {
+			return all, nil
+		}
+		token = next
+	}
+}
+
+// CommentsPageSize is the number of comments requested per page
+const CommentsPageSize = 50
+
+// GetCommentsPage fetches one page of comments for a work item. Pass an empty
+// continuationToken for the first page; the returned token is empty on the last page.
+func (c *Client) GetCommentsPage(workItemID int, continuationToken string) ([]Comment, string, error) {
+	return c.GetCommentsPageOrdered(workItemID, continuationToken, false)
+}
+
+// GetCommentsPageOrdered is GetCommentsPage with the newest comments first when newestFirst is set,
+// so later pages continue back in time
+func (c *Client) GetCommentsPageOrdered(workItemID int, continuationToken string, newestFirst bool) ([]Comment, string, error) {
+	commentsURL := fmt.Sprintf("%s/_apis/wit/workitems/%d/comments?$top=%d&api-version=7.0-preview.3", c.baseURL(), workItemID, CommentsPageSize)
+	if newestFirst {
+		commentsURL += "&order=desc"
+	}
+	if continuationToken != "" {
+		commentsURL += "&continuationToken=" + url.QueryEscape(continuationToken)
+	}
+
+	req, err := http.NewRequest("GET", commentsURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, "", &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var result CommentsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, "", err
+	}
+
+	return result.Comments, result.ContinuationToken, nil
 }
 
 // AddComment adds a new comment to a work item.
@@ -618,7 +1343,7 @@ func (c *Client) AddComment(workItemID int, text string) error {
 	req.Header.Set("Authorization", c.authHeader())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return err
 	}
@@ -626,14 +1351,32 @@ func (c *Client) AddComment(workItemID int, text string) error {
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	return nil
 }
 
+// AddHistoryComment posts a discussion entry by updating System.History, for
+// processes that route discussion through the work item history
+func (c *Client) AddHistoryComment(workItemID int, text string) error {
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("comment text is required")
+	}
+	_, err := c.patchWorkItem(workItemID, []CreateWorkItemOp{
+		{Op: "add", Path: "/fields/System.History", Value: text},
+	})
+	return err
+}
+
 // UpdateWorkItem updates the title, state, assignee, and tags of a work item.
 func (c *Client) UpdateWorkItem(workItemID int, title, state, assignedTo, tags string) (*WorkItem, error) {
+	return c.UpdateWorkItemWithReason(workItemID, title, state, "", assignedTo, tags)
+}
+
+// UpdateWorkItemWithReason updates a work item like UpdateWorkItem and also sets the reason
+// for its state change. An empty reason lets Azure DevOps pick the transition's default.
+func (c *Client) UpdateWorkItemWithReason(workItemID int, title, state, reason, assignedTo, tags string) (*WorkItem, error) {
 	updateURL := fmt.Sprintf("%s/_apis/wit/workitems/%d?api-version=7.0", c.baseURL(), workItemID)
 
 	var ops []CreateWorkItemOp
@@ -643,6 +1386,9 @@ func (c *Client) UpdateWorkItem(workItemID int, title, state, assignedTo, tags s
 	if state != "" {
 		ops = append(ops, CreateWorkItemOp{Op: "replace", Path: "/fields/System.State", Value: state})
 	}
+	if reason != "" {
+		ops = append(ops, CreateWorkItemOp{Op: "replace", Path: "/fields/System.Reason", Value: reason})
+	}
 	// AssignedTo can be empty string to unassign
 	ops = append(ops, CreateWorkItemOp{Op: "replace", Path: "/fields/System.AssignedTo", Value: assignedTo})
 	// Tags can be empty string to clear tags
@@ -661,7 +1407,7 @@ func (c *Client) UpdateWorkItem(workItemID int, title, state, assignedTo, tags s
 	req.Header.Set("Authorization", c.authHeader())
 	req.Header.Set("Content-Type", "application/json-patch+json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -669,7 +1415,7 @@ func (c *Client) UpdateWorkItem(workItemID int, title, state, assignedTo, tags s
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var workItem WorkItem
@@ -690,7 +1436,7 @@ func (c *Client) GetWorkItemWithRelations(workItemID int) (*WorkItem, error) {
 	}
 	req.Header.Set("Authorization", c.authHeader())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -698,7 +1444,7 @@ func (c *Client) GetWorkItemWithRelations(workItemID int) (*WorkItem, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var workItem WorkItem
@@ -788,7 +1534,7 @@ func (c *Client) DeleteWorkItem(workItemID int) error {
 	}
 	req.Header.Set("Authorization", c.authHeader())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return err
 	}
@@ -796,15 +1542,98 @@ func (c *Client) DeleteWorkItem(workItemID int) error {
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	return nil
+}
+
+// ListRecycleBin lists the work items in the project's recycle bin
+func (c *Client) ListRecycleBin() ([]DeletedWorkItem, error) {
+	// The listing only returns IDs, so fetch the details in a second request
+	refs, err := c.getRecycleBin(fmt.Sprintf("%s/_apis/wit/recyclebin?api-version=7.0", c.baseURL()))
+	if err != nil || len(refs) == 0 {
+		return refs, err
+	}
+
+	var items []DeletedWorkItem
+	for start := 0; start < len(refs); start += maxIDsPerRequest {
+		end := start + maxIDsPerRequest
+		if end > len(refs) {
+			end = len(refs)
+		}
+		ids := make([]string, 0, end-start)
+		for _, ref := range refs[start:end] {
+			ids = append(ids, fmt.Sprintf("%d", ref.ID))
+		}
+		detailsURL := fmt.Sprintf("%s/_apis/wit/recyclebin?ids=%s&api-version=7.0", c.baseURL(), url.QueryEscape(strings.Join(ids, ",")))
+		batch, err := c.getRecycleBin(detailsURL)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, batch...)
+	}
+	return items, nil
+}
+
+// getRecycleBin fetches a recycle bin listing from the given URL
+func (c *Client) getRecycleBin(recycleBinURL string) ([]DeletedWorkItem, error) {
+	req, err := http.NewRequest("GET", recycleBinURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var result RecycleBinResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return result.Value, nil
+}
+
+// RestoreWorkItem restores a deleted work item from the recycle bin
+func (c *Client) RestoreWorkItem(workItemID int) error {
+	restoreURL := fmt.Sprintf("%s/_apis/wit/recyclebin/%d?api-version=7.0", c.baseURL(), workItemID)
+
+	jsonBody, _ := json.Marshal(map[string]bool{"IsDeleted": false})
+
+	req, err := http.NewRequest("PATCH", restoreURL, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	return nil
 }
 
 // TestConnection verifies that the client can connect to Azure DevOps with the configured credentials.
+// A 401 caused by an expired PAT is reported with a dedicated message, and any deprecation
+// notice or other warning the server sends is kept for DeprecationWarning and ConnectionWarning.
 func (c *Client) TestConnection() error {
-	testURL := fmt.Sprintf("https://dev.azure.com/%s/_apis/projects/%s?api-version=7.0", c.Organization, c.Project)
+	testURL := fmt.Sprintf("%s/_apis/projects/%s?api-version=7.0", c.OrganizationURL(), c.Project)
 
 	req, err := http.NewRequest("GET", testURL, nil)
 	if err != nil {
@@ -812,14 +1641,19 @@ func (c *Client) TestConnection() error {
 	}
 	req.Header.Set("Authorization", c.authHeader())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	c.deprecation, c.warning = connectionNotices(resp.Header)
+
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusUnauthorized && patExpired(resp.Header, string(respBody)) {
+			return fmt.Errorf("connection failed (%d): your PAT appears to have expired; create a new one under User settings > Personal access tokens", resp.StatusCode)
+		}
 		errMsg := truncateError(string(respBody), 100)
 		return fmt.Errorf("connection failed (%d): %s", resp.StatusCode, errMsg)
 	}
@@ -827,16 +1661,175 @@ func (c *Client) TestConnection() error {
 	return nil
 }
 
+// GetAuthenticatedUserID returns the identity ID of the user the PAT belongs to,
+// which is how @mentions in comments refer to them
+func (c *Client) GetAuthenticatedUserID() (string, error) {
+	dataURL := fmt.Sprintf("%s/_apis/connectionData", c.OrganizationURL())
+
+	req, err := http.NewRequest("GET", dataURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var data struct {
+		AuthenticatedUser struct {
+			ID string `json:"id"`
+		} `json:"authenticatedUser"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return "", err
+	}
+	if data.AuthenticatedUser.ID == "" {
+		return "", fmt.Errorf("connection data has no authenticated user")
+	}
+	return data.AuthenticatedUser.ID, nil
+}
+
+// DeprecationWarning returns the deprecation notice sent with the last TestConnection
+// response, or an empty string when there was none.
+func (c *Client) DeprecationWarning() string {
+	return c.deprecation
+}
+
+// ConnectionWarning returns any other warning sent with the last TestConnection
+// response, or an empty string when there was none.
+func (c *Client) ConnectionWarning() string {
+	return c.warning
+}
+
+// connectionNotices returns the deprecation notice and any other warning sent in h.
+// A Warning header only counts as a deprecation notice when it says so.
+func connectionNotices(h http.Header) (deprecation, warning string) {
+	deprecation = strings.TrimSpace(h.Get("Deprecation"))
+	if v := strings.TrimSpace(h.Get("Warning")); v != "" {
+		switch {
+		case !strings.Contains(strings.ToLower(v), "deprecat"):
+			warning = v
+		case deprecation == "":
+			deprecation = v
+		}
+	}
+	return deprecation, warning
+}
+
+// patExpired reports whether a 401 response says the PAT has expired. Azure DevOps puts
+// the reason in the URL-encoded X-TFS-ServiceError header and usually repeats it in the body.
+func patExpired(h http.Header, body string) bool {
+	hints := []string{h.Get("WWW-Authenticate"), body}
+	if serviceErr := h.Get("X-TFS-ServiceError"); serviceErr != "" {
+		if decoded, err := url.QueryUnescape(serviceErr); err == nil {
+			serviceErr = decoded
+		}
+		hints = append(hints, serviceErr)
+	}
+	for _, hint := range hints {
+		if strings.Contains(strings.ToLower(hint), "expired") {
+			return true
+		}
+	}
+	return false
+}
+
 // truncateError shortens error messages to a maximum length, useful for HTML responses
 func truncateError(msg string, maxLen int) string {
 	// Remove newlines and excess whitespace
 	msg = strings.Join(strings.Fields(msg), " ")
 	if len(msg) > maxLen {
-		return msg[:maxLen] + "..."
+		// Back up to a rune boundary so multi-byte characters aren't split
+		cut := maxLen
+		for cut > 0 && !utf8.RuneStart(msg[cut]) {
+			cut--
+		}
+		return msg[:cut] + "..."
 	}
 	return msg
 }
 
+// GetTeamFieldValues fetches the area paths that belong to the team
+func (c *Client) GetTeamFieldValues() (*TeamFieldValues, error) {
+	valuesURL := fmt.Sprintf("%s/_apis/work/teamsettings/teamfieldvalues?api-version=7.0", c.teamURL())
+
+	req, err := http.NewRequest("GET", valuesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var result TeamFieldValues
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UsesTeamAreas reports whether queries should be scoped to the team's area paths,
+// which is the case when a Team is set but no AreaPath is configured
+func (c *Client) UsesTeamAreas() bool {
+	return c.Team != "" && c.AreaPath == ""
+}
+
+// SetTeamAreas scopes queries and new work items to the team's area paths
+// (see UsesTeamAreas). Values keyed on a custom team field are ignored.
+func (c *Client) SetTeamAreas(values *TeamFieldValues) {
+	if values != nil && values.Field.ReferenceName != "" && values.Field.ReferenceName != "System.AreaPath" {
+		return
+	}
+	c.teamAreas = values
+}
+
+// areaClause returns the WIQL condition limiting a query to the configured or team area paths
+func (c *Client) areaClause() string {
+	if c.AreaPath != "" {
+		return fmt.Sprintf(" AND [System.AreaPath] UNDER '%s'", wiqlEscape(c.AreaPath))
+	}
+	if c.teamAreas == nil || len(c.teamAreas.Values) == 0 {
+		return ""
+	}
+	conditions := make([]string, 0, len(c.teamAreas.Values))
+	for _, v := range c.teamAreas.Values {
+		if v.IncludeChildren {
+			conditions = append(conditions, fmt.Sprintf("[System.AreaPath] UNDER '%s'", wiqlEscape(v.Value)))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("[System.AreaPath] = '%s'", wiqlEscape(v.Value)))
+		}
+	}
+	return fmt.Sprintf(" AND (%s)", strings.Join(conditions, " OR "))
+}
+
+// defaultAreaPath returns the area path given to new work items
+func (c *Client) defaultAreaPath() string {
+	if c.AreaPath != "" {
+		return c.AreaPath
+	}
+	if c.teamAreas != nil {
+		return c.teamAreas.DefaultValue
+	}
+	return ""
+}
+
 // GetIterations fetches available iterations for the team
 func (c *Client) GetIterations() ([]Iteration, error) {
 	// Use team URL to get team iterations
@@ -848,7 +1841,7 @@ func (c *Client) GetIterations() ([]Iteration, error) {
 	}
 	req.Header.Set("Authorization", c.authHeader())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -856,7 +1849,7 @@ func (c *Client) GetIterations() ([]Iteration, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var result IterationsResponse
@@ -869,7 +1862,7 @@ func (c *Client) GetIterations() ([]Iteration, error) {
 
 // UpdateWorkItemPlanning updates the planning fields of a work item
 // Pass nil for any field you don't want to update
-func (c *Client) UpdateWorkItemPlanning(workItemID int, storyPoints, originalEstimate, remainingWork, completedWork *float64) (*WorkItem, error) {
+func (c *Client) UpdateWorkItemPlanning(workItemID int, storyPoints, originalEstimate, remainingWork, completedWork, effort *float64) (*WorkItem, error) {
 	updateURL := fmt.Sprintf("%s/_apis/wit/workitems/%d?api-version=7.0", c.baseURL(), workItemID)
 
 	var ops []CreateWorkItemOp
@@ -887,6 +1880,9 @@ func (c *Client) UpdateWorkItemPlanning(workItemID int, storyPoints, originalEst
 	if completedWork != nil {
 		ops = append(ops, CreateWorkItemOp{Op: "add", Path: "/fields/Microsoft.VSTS.Scheduling.CompletedWork", Value: *completedWork})
 	}
+	if effort != nil {
+		ops = append(ops, CreateWorkItemOp{Op: "add", Path: "/fields/Microsoft.VSTS.Scheduling.Effort", Value: *effort})
+	}
 
 	if len(ops) == 0 {
 		return nil, fmt.Errorf("no planning updates specified")
@@ -901,7 +1897,7 @@ func (c *Client) UpdateWorkItemPlanning(workItemID int, storyPoints, originalEst
 	req.Header.Set("Authorization", c.authHeader())
 	req.Header.Set("Content-Type", "application/json-patch+json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -909,7 +1905,7 @@ func (c *Client) UpdateWorkItemPlanning(workItemID int, storyPoints, originalEst
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var workItem WorkItem
@@ -930,7 +1926,7 @@ func (c *Client) GetWorkItemTypeFields(workItemType string) ([]WorkItemTypeField
 	}
 	req.Header.Set("Authorization", c.authHeader())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -938,7 +1934,7 @@ func (c *Client) GetWorkItemTypeFields(workItemType string) ([]WorkItemTypeField
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var result WorkItemTypeFieldsResponse
@@ -949,6 +1945,87 @@ func (c *Client) GetWorkItemTypeFields(workItemType string) ([]WorkItemTypeField
 	return result.Value, nil
 }
 
+// GetWorkItemStates fetches the states of a work item type along with the allowed transitions
+// out of each state, in the order the process defines them.
+func (c *Client) GetWorkItemStates(workItemType string) ([]WorkItemState, error) {
+	typeURL := fmt.Sprintf("%s/_apis/wit/workitemtypes/%s?api-version=7.0", c.baseURL(), url.PathEscape(workItemType))
+
+	req, err := http.NewRequest("GET", typeURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var result workItemTypeStatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	states := make([]WorkItemState, 0, len(result.States))
+	for _, st := range result.States {
+		state := WorkItemState{Name: st.Name, Category: st.Category}
+		for _, t := range result.Transitions[st.Name] {
+			if t.To != "" && t.To != st.Name {
+				state.Transitions = append(state.Transitions, t.To)
+			}
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// GetStateReasons fetches the reasons that can be recorded with a state change of a work item type.
+// The REST API doesn't scope reasons to individual transitions, so these are the allowed values
+// of System.Reason for the whole type.
+func (c *Client) GetStateReasons(workItemType string) ([]string, error) {
+	return c.GetFieldAllowedValues(workItemType, "System.Reason")
+}
+
+// GetActivities fetches the values the Activity field of a work item type can take
+func (c *Client) GetActivities(workItemType string) ([]string, error) {
+	return c.GetFieldAllowedValues(workItemType, activityField)
+}
+
+// GetFieldAllowedValues fetches the values a picklist field (e.g. System.State) can take on a
+// work item type. Free-text fields have no allowed values and return an empty list.
+func (c *Client) GetFieldAllowedValues(workItemType, referenceName string) ([]string, error) {
+	fieldURL := fmt.Sprintf("%s/_apis/wit/workitemtypes/%s/fields/%s?$expand=allowedValues&api-version=7.0", c.baseURL(), url.PathEscape(workItemType), url.PathEscape(referenceName))
+
+	req, err := http.NewRequest("GET", fieldURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var field WorkItemTypeField
+	if err := json.NewDecoder(resp.Body).Decode(&field); err != nil {
+		return nil, err
+	}
+	return field.AllowedValues, nil
+}
+
 // GetPlanningFields returns the available planning fields for a work item type
 // This filters to only scheduling/planning related fields
 func (c *Client) GetPlanningFields(workItemType string) ([]PlanningField, error) {
@@ -957,31 +2034,103 @@ func (c *Client) GetPlanningFields(workItemType string) ([]PlanningField, error)
 		return nil, err
 	}
 
-	// Planning field reference names and their display names
-	planningFieldMap := map[string]string{
-		"Microsoft.VSTS.Scheduling.StoryPoints":      "Story Points",
-		"Microsoft.VSTS.Scheduling.OriginalEstimate": "Original Estimate (hours)",
-		"Microsoft.VSTS.Scheduling.RemainingWork":    "Remaining Work (hours)",
-		"Microsoft.VSTS.Scheduling.CompletedWork":    "Completed Work (hours)",
-		"Microsoft.VSTS.Scheduling.Effort":           "Effort",
+	// Field types are best-effort; without them fall back to the well-known numeric fields
+	fieldTypes, err := c.GetFieldTypes()
+	if err != nil {
+		fieldTypes = nil
 	}
 
 	var planningFields []PlanningField
 	for _, field := range fields {
-		if displayName, ok := planningFieldMap[field.ReferenceName]; ok {
-			// Only include if not read-only
-			if !field.ReadOnly {
-				planningFields = append(planningFields, PlanningField{
-					ReferenceName: field.ReferenceName,
-					DisplayName:   displayName,
-				})
-			}
+		if !strings.HasPrefix(field.ReferenceName, schedulingFieldPrefix) || field.ReadOnly {
+			continue
+		}
+		if !isNumericPlanningField(field.ReferenceName, fieldTypes) {
+			continue
+		}
+		// Prefer the server's name so org-renamed fields show the right label
+		displayName := field.Name
+		if displayName == "" {
+			displayName = defaultPlanningFieldNames[field.ReferenceName]
+		}
+		if displayName == "" {
+			displayName = strings.TrimPrefix(field.ReferenceName, schedulingFieldPrefix)
 		}
+		planningFields = append(planningFields, PlanningField{
+			ReferenceName: field.ReferenceName,
+			DisplayName:   displayName,
+		})
 	}
 
 	return planningFields, nil
 }
 
+// schedulingFieldPrefix is the namespace of the built-in planning/scheduling fields
+const schedulingFieldPrefix = "Microsoft.VSTS.Scheduling."
+
+// defaultPlanningFieldNames maps the well-known numeric scheduling fields to display
+// names, used when the server doesn't provide a name or field types
+var defaultPlanningFieldNames = map[string]string{
+	"Microsoft.VSTS.Scheduling.StoryPoints":      "Story Points",
+	"Microsoft.VSTS.Scheduling.OriginalEstimate": "Original Estimate (hours)",
+	"Microsoft.VSTS.Scheduling.RemainingWork":    "Remaining Work (hours)",
+	"Microsoft.VSTS.Scheduling.CompletedWork":    "Completed Work (hours)",
+	"Microsoft.VSTS.Scheduling.Effort":           "Effort",
+}
+
+// isNumericPlanningField reports whether a scheduling field holds a number.
+// Dates such as StartDate and TargetDate share the namespace and are excluded.
+func isNumericPlanningField(referenceName string, fieldTypes map[string]string) bool {
+	if fieldType := fieldTypes[referenceName]; fieldType != "" {
+		return fieldType == "double" || fieldType == "integer"
+	}
+	_, ok := defaultPlanningFieldNames[referenceName]
+	return ok
+}
+
+// GetFieldTypes fetches every field defined in the organization and returns a map
+// of reference name to field type (e.g. "double", "integer", "dateTime").
+// The result is cached on the client, since it covers the whole organization.
+func (c *Client) GetFieldTypes() (map[string]string, error) {
+	if c.fieldTypes != nil {
+		if types := c.fieldTypes.get(); types != nil {
+			return types, nil
+		}
+	}
+	fieldsURL := fmt.Sprintf("%s/_apis/wit/fields?api-version=7.0", c.baseURL())
+
+	req, err := http.NewRequest("GET", fieldsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var result FieldsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	fieldTypes := make(map[string]string, len(result.Value))
+	for _, field := range result.Value {
+		fieldTypes[field.ReferenceName] = field.Type
+	}
+	if c.fieldTypes != nil {
+		c.fieldTypes.set(fieldTypes)
+	}
+	return fieldTypes, nil
+}
+
 // UpdateWorkItemPlanningDynamic updates planning fields dynamically based on the provided map
 func (c *Client) UpdateWorkItemPlanningDynamic(workItemID int, fields map[string]float64) (*WorkItem, error) {
 	updateURL := fmt.Sprintf("%s/_apis/wit/workitems/%d?api-version=7.0", c.baseURL(), workItemID)
@@ -1009,7 +2158,7 @@ func (c *Client) UpdateWorkItemPlanningDynamic(workItemID int, fields map[string
 	req.Header.Set("Authorization", c.authHeader())
 	req.Header.Set("Content-Type", "application/json-patch+json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -1017,7 +2166,7 @@ func (c *Client) UpdateWorkItemPlanningDynamic(workItemID int, fields map[string
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var workItem WorkItem
@@ -1034,16 +2183,26 @@ func (c *Client) GetRecentlyChangedWorkItems(assignedTo string, withinMinutes in
 	if assignedTo == "" {
 		return []WorkItem{}, nil
 	}
+	return c.getRecentlyChanged(fmt.Sprintf(" AND [System.AssignedTo] = '%s'", assignedTo), assignedTo, withinMinutes)
+}
+
+// GetRecentlyMentionedWorkItems fetches work items the authenticated user was recently @mentioned in
+// that someone else changed within the last N minutes. user is the authenticated user's unique name.
+func (c *Client) GetRecentlyMentionedWorkItems(user string, withinMinutes int) ([]WorkItem, error) {
+	if user == "" {
+		return []WorkItem{}, nil
+	}
+	return c.getRecentlyChanged(" AND [System.Id] IN (@RecentMentions)", user, withinMinutes)
+}
 
-	// Build WIQL query for recently changed items assigned to user
+// getRecentlyChanged fetches work items matching clause that were changed by someone other than user
+func (c *Client) getRecentlyChanged(clause, user string, withinMinutes int) ([]WorkItem, error) {
 	// Exclude items where the user themselves made the change
 	query := fmt.Sprintf("SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = '%s'", c.Project)
-	query += fmt.Sprintf(" AND [System.AssignedTo] = '%s'", assignedTo)
-	query += fmt.Sprintf(" AND [System.ChangedBy] <> '%s'", assignedTo)
+	query += clause
+	query += fmt.Sprintf(" AND [System.ChangedBy] <> '%s'", user)
 	query += fmt.Sprintf(" AND [System.ChangedDate] >= @Today - %d", withinMinutes)
-	if c.AreaPath != "" {
-		query += fmt.Sprintf(" AND [System.AreaPath] UNDER '%s'", c.AreaPath)
-	}
+	query += c.areaClause()
 	query += " ORDER BY [System.ChangedDate] DESC"
 
 	wiqlURL := fmt.Sprintf("%s/_apis/wit/wiql?api-version=7.0", c.teamURL())
@@ -1058,7 +2217,7 @@ func (c *Client) GetRecentlyChangedWorkItems(assignedTo string, withinMinutes in
 	req.Header.Set("Authorization", c.authHeader())
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -1066,7 +2225,7 @@ func (c *Client) GetRecentlyChangedWorkItems(assignedTo string, withinMinutes in
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var queryResult WorkItemQueryResult
@@ -1086,13 +2245,109 @@ func (c *Client) GetRecentlyChangedWorkItems(assignedTo string, withinMinutes in
 	return c.getWorkItemsByIDs(ids)
 }
 
+// GetAreas fetches the project's area tree and returns every area path in it
+func (c *Client) GetAreas() ([]string, error) {
+	areasURL := fmt.Sprintf("%s/_apis/wit/classificationnodes/areas?$depth=10&api-version=7.0", c.baseURL())
+
+	req, err := http.NewRequest("GET", areasURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader())
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var root ClassificationNode
+	if err := json.NewDecoder(resp.Body).Decode(&root); err != nil {
+		return nil, err
+	}
+
+	var areas []string
+	var walk func(node ClassificationNode)
+	walk = func(node ClassificationNode) {
+		areas = append(areas, areaNodePath(node.Path))
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	return areas, nil
+}
+
+// areaNodePath converts a classification node path ("\Project\Area\Team")
+// into the System.AreaPath form ("Project\Team")
+func areaNodePath(nodePath string) string {
+	parts := strings.Split(strings.TrimPrefix(nodePath, "\\"), "\\")
+	if len(parts) >= 2 && parts[1] == "Area" {
+		parts = append(parts[:1], parts[2:]...)
+	}
+	return strings.Join(parts, "\\")
+}
+
+// UpdateWorkItemArea updates the area path of a work item
+func (c *Client) UpdateWorkItemArea(workItemID int, areaPath string) (*WorkItem, error) {
+	if strings.TrimSpace(areaPath) == "" {
+		return nil, fmt.Errorf("area path is required")
+	}
+	return c.patchWorkItem(workItemID, []CreateWorkItemOp{
+		{Op: "replace", Path: "/fields/System.AreaPath", Value: areaPath},
+	})
+}
+
 // UpdateWorkItemIteration updates the iteration path of a work item
 func (c *Client) UpdateWorkItemIteration(workItemID int, iterationPath string) (*WorkItem, error) {
-	updateURL := fmt.Sprintf("%s/_apis/wit/workitems/%d?api-version=7.0", c.baseURL(), workItemID)
-
-	ops := []CreateWorkItemOp{
+	return c.patchWorkItem(workItemID, []CreateWorkItemOp{
 		{Op: "replace", Path: "/fields/System.IterationPath", Value: iterationPath},
+	})
+}
+
+// UpdateAssignee sets only the assignee of a work item (empty unassigns it)
+func (c *Client) UpdateAssignee(workItemID int, assignedTo string) (*WorkItem, error) {
+	return c.patchWorkItem(workItemID, []CreateWorkItemOp{
+		{Op: "replace", Path: "/fields/System.AssignedTo", Value: assignedTo},
+	})
+}
+
+// UpdateState sets only the state of a work item
+func (c *Client) UpdateState(workItemID int, state string) (*WorkItem, error) {
+	if strings.TrimSpace(state) == "" {
+		return nil, fmt.Errorf("state is required")
 	}
+	return c.patchWorkItem(workItemID, []CreateWorkItemOp{
+		{Op: "replace", Path: "/fields/System.State", Value: state},
+	})
+}
+
+// UpdateTags replaces only the tags of a work item
+func (c *Client) UpdateTags(workItemID int, tags string) (*WorkItem, error) {
+	return c.patchWorkItem(workItemID, []CreateWorkItemOp{
+		{Op: "replace", Path: "/fields/System.Tags", Value: tags},
+	})
+}
+
+// activityField is the reference name of the Activity field on Tasks
+const activityField = "Microsoft.VSTS.Common.Activity"
+
+// UpdateActivity sets the Activity field of a work item
+func (c *Client) UpdateActivity(workItemID int, activity string) (*WorkItem, error) {
+	return c.patchWorkItem(workItemID, []CreateWorkItemOp{
+		{Op: "add", Path: "/fields/" + activityField, Value: activity},
+	})
+}
+
+// patchWorkItem applies JSON patch operations to a work item, leaving other fields untouched
+func (c *Client) patchWorkItem(workItemID int, ops []CreateWorkItemOp) (*WorkItem, error) {
+	updateURL := fmt.Sprintf("%s/_apis/wit/workitems/%d?api-version=7.0", c.baseURL(), workItemID)
 
 	jsonBody, _ := json.Marshal(ops)
 
@@ -1103,7 +2358,7 @@ func (c *Client) UpdateWorkItemIteration(workItemID int, iterationPath string) (
 	req.Header.Set("Authorization", c.authHeader())
 	req.Header.Set("Content-Type", "application/json-patch+json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -1111,7 +2366,7 @@ func (c *Client) UpdateWorkItemIteration(workItemID int, iterationPath string) (
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var workItem WorkItem
@@ -1154,7 +2409,7 @@ func (c *Client) GetHyperlinks(workItemID int) ([]Hyperlink, error) {
 }
 
 // AddHyperlink adds a hyperlink to a work item
-// Validates URL format and enforces length limits before adding
+// Validates URL format and enforces length limits before adding, and refuses a URL that is already linked
 func (c *Client) AddHyperlink(workItemID int, urlStr string, comment string) error {
 	// Validate URL format
 	parsedURL, err := url.Parse(urlStr)
@@ -1177,16 +2432,55 @@ func (c *Client) AddHyperlink(workItemID int, urlStr string, comment string) err
 		return fmt.Errorf("comment too long: maximum length is 500 characters")
 	}
 
-	updateURL := fmt.Sprintf("%s/_apis/wit/workitems/%d?api-version=7.0", c.baseURL(), workItemID)
-
 	attributes := map[string]interface{}{}
 	if comment != "" {
 		attributes["comment"] = comment
 	}
+	return c.addExternalLink(workItemID, "Hyperlink", urlStr, attributes)
+}
+
+// AddArtifactLink links a work item to an artifact such as a pull request or commit,
+// identified by its vstfs URI. name is the artifact link type, e.g. "Pull Request".
+func (c *Client) AddArtifactLink(workItemID int, artifactURI, name, comment string) error {
+	parsedURI, err := url.Parse(artifactURI)
+	if err != nil {
+		return fmt.Errorf("invalid artifact URI: %w", err)
+	}
+	if parsedURI.Scheme != "vstfs" {
+		return fmt.Errorf("invalid artifact URI: must start with vstfs:///")
+	}
+	if name == "" {
+		return fmt.Errorf("artifact link name is required")
+	}
+	if len(comment) > 500 {
+		return fmt.Errorf("comment too long: maximum length is 500 characters")
+	}
+
+	attributes := map[string]interface{}{"name": name}
+	if comment != "" {
+		attributes["comment"] = comment
+	}
+	return c.addExternalLink(workItemID, "ArtifactLink", artifactURI, attributes)
+}
+
+// addExternalLink adds a Hyperlink or ArtifactLink relation to a work item,
+// unless the item already links to the same URL
+func (c *Client) addExternalLink(workItemID int, rel, linkURL string, attributes map[string]interface{}) error {
+	wi, err := c.GetWorkItemWithRelations(workItemID)
+	if err != nil {
+		return err
+	}
+	for _, existing := range wi.Relations {
+		if (existing.Rel == "ArtifactLink" || existing.Rel == "Hyperlink") && existing.URL == linkURL {
+			return fmt.Errorf("link already exists: #%d already links to %s", workItemID, linkURL)
+		}
+	}
+
+	updateURL := fmt.Sprintf("%s/_apis/wit/workitems/%d?api-version=7.0", c.baseURL(), workItemID)
 
 	linkValue := map[string]interface{}{
-		"rel":        "Hyperlink",
-		"url":        urlStr,
+		"rel":        rel,
+		"url":        linkURL,
 		"attributes": attributes,
 	}
 
@@ -1210,7 +2504,7 @@ func (c *Client) AddHyperlink(workItemID int, urlStr string, comment string) err
 	req.Header.Set("Authorization", c.authHeader())
 	req.Header.Set("Content-Type", "application/json-patch+json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return err
 	}
@@ -1218,7 +2512,7 @@ func (c *Client) AddHyperlink(workItemID int, urlStr string, comment string) err
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package azdo
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -37,6 +38,8 @@ func testClientWithMockTransport(handler http.HandlerFunc) (*Client, *httptest.S
 				transport: http.DefaultTransport,
 			},
 		},
+		idCache:    newIDListCache(),
+		fieldTypes: &fieldTypeCache{},
 	}
 	return client, server
 }
@@ -61,6 +64,143 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClientWithHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	used := false
+	hc := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			used = true
+			return (&mockTransport{baseURL: server.URL, transport: http.DefaultTransport}).RoundTrip(req)
+		}),
+	}
+
+	client := NewClientWithHTTP("myorg", "myproject", "myteam", "", "pat123", hc)
+	if client.Organization != "myorg" || client.PAT != "pat123" {
+		t.Errorf("NewClientWithHTTP() = %+v, want fields set", client)
+	}
+
+	if err := client.TestConnection(); err != nil {
+		t.Fatalf("TestConnection failed: %v", err)
+	}
+	if !used {
+		t.Error("Expected requests to go through the custom transport")
+	}
+}
+
+func TestNewClientWithHTTPNil(t *testing.T) {
+	client := NewClientWithHTTP("myorg", "myproject", "", "", "pat", nil)
+	if client.httpClient == nil {
+		t.Error("Expected a default http.Client when nil is passed")
+	}
+}
+
+func TestNewHTTPClientProxy(t *testing.T) {
+	hc, err := NewHTTPClient("http://proxy.example.com:8080")
+	if err != nil {
+		t.Fatalf("NewHTTPClient failed: %v", err)
+	}
+
+	transport, ok := hc.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Transport = %T, want *http.Transport", hc.Transport)
+	}
+	req, _ := http.NewRequest("GET", "https://dev.azure.com/myorg", nil)
+	proxy, err := transport.Proxy(req)
+	if err != nil {
+		t.Fatalf("Proxy() failed: %v", err)
+	}
+	if proxy == nil || proxy.String() != "http://proxy.example.com:8080" {
+		t.Errorf("Proxy() = %v, want configured proxy URL", proxy)
+	}
+
+	client := NewClientWithHTTP("myorg", "myproject", "", "", "pat", hc)
+	if client.httpClient != hc {
+		t.Error("Expected the proxied http.Client to be used by the client")
+	}
+}
+
+func TestNewHTTPClientDefault(t *testing.T) {
+	hc, err := NewHTTPClient("")
+	if err != nil {
+		t.Fatalf("NewHTTPClient failed: %v", err)
+	}
+	if hc.Transport != nil {
+		t.Error("Expected the default transport (which honours proxy env vars) when no proxy is set")
+	}
+}
+
+func TestNewHTTPClientInvalidProxy(t *testing.T) {
+	for _, proxyURL := range []string{"proxy.example.com", "://bad"} {
+		if _, err := NewHTTPClient(proxyURL); err == nil {
+			t.Errorf("NewHTTPClient(%q) expected error", proxyURL)
+		}
+	}
+}
+
+func TestDebugLogging(t *testing.T) {
+	longBody := `{"count":1,"value":[{"name":"Bug"}],"padding":"` + strings.Repeat("x", 2000) + `"}`
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(longBody))
+	})
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client.SetDebugLog(&buf)
+
+	types, err := client.GetWorkItemTypes()
+	if err != nil {
+		t.Fatalf("GetWorkItemTypes failed: %v", err)
+	}
+	if len(types) != 1 || types[0] != "Bug" {
+		t.Errorf("types = %v, want body still readable after logging", types)
+	}
+
+	entry := buf.String()
+	if !strings.Contains(entry, "GET https://dev.azure.com/testorg/testproject/_apis/wit/workitemtypes") {
+		t.Errorf("Expected method and URL in log entry, got %q", entry)
+	}
+	if !strings.Contains(entry, "-> 200") {
+		t.Errorf("Expected status in log entry, got %q", entry)
+	}
+	if strings.Contains(entry, longBody) || !strings.Contains(entry, "...") {
+		t.Error("Expected response body to be truncated in the log")
+	}
+	if len(entry) > debugBodyLimit+300 {
+		t.Errorf("Log entry is %d bytes, want it bounded by the body limit", len(entry))
+	}
+	if strings.Contains(entry, "testpat") {
+		t.Error("Debug log must not contain credentials")
+	}
+}
+
+func TestDebugLoggingDisabled(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client.SetDebugLog(&buf)
+	client.SetDebugLog(nil)
+
+	_ = client.TestConnection()
+	if buf.Len() != 0 {
+		t.Errorf("Expected no log output when disabled, got %q", buf.String())
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 func TestBaseURL(t *testing.T) {
 	client := NewClient("myorg", "myproject", "", "", "pat")
 	expected := "https://dev.azure.com/myorg/myproject"
@@ -97,6 +237,61 @@ func TestTeamURL(t *testing.T) {
 	}
 }
 
+func TestBacklogWebURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		team     string
+		backlog  string
+		expected string
+	}{
+		{"with team", "myteam", "Stories", "https://dev.azure.com/myorg/myproject/_boards/board/t/myteam/Stories"},
+		{"team with spaces", "Web Team", "Backlog items", "https://dev.azure.com/myorg/myproject/_boards/board/t/Web%20Team/Backlog%20items"},
+		{"default team", "", "Features", "https://dev.azure.com/myorg/myproject/_boards/board/t/myproject%20Team/Features"},
+		{"default backlog", "myteam", "", "https://dev.azure.com/myorg/myproject/_boards/board/t/myteam"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("myorg", "myproject", tt.team, "", "pat")
+			if got := client.BacklogWebURL(tt.backlog); got != tt.expected {
+				t.Errorf("BacklogWebURL(%q) = %v, want %v", tt.backlog, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCustomBaseURL(t *testing.T) {
+	client := NewClient("DefaultCollection", "myproject", "myteam", "", "pat")
+	client.BaseURL = "https://tfs.example.com/tfs/"
+
+	if got, want := client.baseURL(), "https://tfs.example.com/tfs/DefaultCollection/myproject"; got != want {
+		t.Errorf("baseURL() = %v, want %v", got, want)
+	}
+	if got, want := client.teamURL(), "https://tfs.example.com/tfs/DefaultCollection/myproject/myteam"; got != want {
+		t.Errorf("teamURL() = %v, want %v", got, want)
+	}
+	if got, want := client.OrganizationURL(), "https://tfs.example.com/tfs/DefaultCollection"; got != want {
+		t.Errorf("OrganizationURL() = %v, want %v", got, want)
+	}
+}
+
+func TestTestConnectionCustomBaseURL(t *testing.T) {
+	var gotURL string
+	client := NewClientWithHTTP("DefaultCollection", "myproject", "", "", "pat", &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			gotURL = req.URL.String()
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Header: make(http.Header)}, nil
+		}),
+	})
+	client.BaseURL = "https://tfs.example.com/tfs"
+
+	if err := client.TestConnection(); err != nil {
+		t.Fatalf("TestConnection failed: %v", err)
+	}
+	if want := "https://tfs.example.com/tfs/DefaultCollection/_apis/projects/myproject?api-version=7.0"; gotURL != want {
+		t.Errorf("TestConnection URL = %v, want %v", gotURL, want)
+	}
+}
+
 func TestGetComments(t *testing.T) {
 	// Create mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -232,7 +427,7 @@ func TestWorkItemFields(t *testing.T) {
 	} else if fields.AssignedTo.DisplayName != "John Doe" {
 		t.Errorf("AssignedTo.DisplayName = %v, want %v", fields.AssignedTo.DisplayName, "John Doe")
 	}
-	if fields.Priority != 2 {
+	if fields.Priority == nil || *fields.Priority != 2 {
 		t.Errorf("Priority = %v, want %v", fields.Priority, 2)
 	}
 	if fields.CommentCount != 5 {
@@ -240,6 +435,63 @@ func TestWorkItemFields(t *testing.T) {
 	}
 }
 
+func TestWorkItemFieldsAbsentPriority(t *testing.T) {
+	var fields WorkItemFields
+	if err := json.Unmarshal([]byte(`{"System.Title": "Restricted"}`), &fields); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if fields.Priority != nil {
+		t.Errorf("Priority = %v, want nil when the field is absent", *fields.Priority)
+	}
+}
+
+func TestWorkItemFieldsCreated(t *testing.T) {
+	jsonData := `{
+		"System.CreatedBy": {
+			"displayName": "Jane Doe",
+			"uniqueName": "jane@example.com"
+		},
+		"System.CreatedDate": "2024-01-10T08:30:00Z"
+	}`
+
+	var fields WorkItemFields
+	if err := json.Unmarshal([]byte(jsonData), &fields); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if fields.CreatedBy == nil || fields.CreatedBy.DisplayName != "Jane Doe" {
+		t.Errorf("CreatedBy = %+v, want Jane Doe", fields.CreatedBy)
+	}
+	if fields.CreatedDate != "2024-01-10T08:30:00Z" {
+		t.Errorf("CreatedDate = %q", fields.CreatedDate)
+	}
+}
+
+func TestWorkItemIsBlocked(t *testing.T) {
+	const predecessor = "System.LinkTypes.Dependency-Reverse"
+	dependency := []WorkItemRelation{{Rel: predecessor, URL: "https://dev.azure.com/org/proj/_apis/wit/workItems/7"}}
+	tests := []struct {
+		name     string
+		wi       WorkItem
+		linkType string
+		want     bool
+	}{
+		{"unblocked", WorkItem{Fields: WorkItemFields{Tags: "frontend; urgent"}}, predecessor, false},
+		{"blocked tag", WorkItem{Fields: WorkItemFields{Tags: "frontend; blocked"}}, "", true},
+		{"tag containing blocked", WorkItem{Fields: WorkItemFields{Tags: "unblocked"}}, "", false},
+		{"CMMI field", WorkItem{Fields: WorkItemFields{Blocked: "Yes"}}, "", true},
+		{"CMMI field cleared", WorkItem{Fields: WorkItemFields{Blocked: "No"}}, "", false},
+		{"dependency link", WorkItem{Relations: dependency}, predecessor, true},
+		{"link type not configured", WorkItem{Relations: dependency}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.wi.IsBlocked(tt.linkType); got != tt.want {
+				t.Errorf("IsBlocked(%q) = %v, want %v", tt.linkType, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestCommentParsing(t *testing.T) {
 	jsonData := `{
 		"id": 42,
@@ -1122,6 +1374,30 @@ func TestCreateWorkItemWithAssignee(t *testing.T) {
 	}
 }
 
+func TestCreateWorkItemWithTags(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"path":"/fields/System.Tags","value":"ops; recurring"`) {
+			t.Errorf("Expected Tags in request body, got %s", string(body))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(WorkItem{ID: 124})
+	})
+	defer server.Close()
+
+	wi, err := client.CreateWorkItemWithTags("Task", "Test", "", 0, "", "ops; recurring")
+	if err != nil {
+		t.Fatalf("CreateWorkItemWithTags failed: %v", err)
+	}
+	if wi.ID != 124 {
+		t.Errorf("Expected ID 124, got %d", wi.ID)
+	}
+}
+
 func TestCreateWorkItemAPIError(t *testing.T) {
 	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
@@ -1221,6 +1497,78 @@ func TestAddChildLinkError(t *testing.T) {
 	}
 }
 
+func TestAddParentLink(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" {
+			t.Errorf("Expected PATCH, got %s", r.Method)
+		}
+		if !strings.Contains(r.URL.Path, "/workitems/101") {
+			t.Errorf("Expected PATCH on child item 101, got %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "System.LinkTypes.Hierarchy-Reverse") {
+			t.Error("Expected parent link relation in body")
+		}
+
+		response := WorkItem{ID: 101}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+	defer server.Close()
+
+	err := client.AddParentLink(101, 100)
+	if err != nil {
+		t.Fatalf("AddParentLink failed: %v", err)
+	}
+}
+
+func TestAddParentLinkRequestBody(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		var ops []CreateWorkItemOp
+		if err := json.NewDecoder(r.Body).Decode(&ops); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if len(ops) != 1 {
+			t.Fatalf("Expected 1 op, got %d", len(ops))
+		}
+		if ops[0].Op != "add" || ops[0].Path != "/relations/-" {
+			t.Errorf("Expected add to /relations/-, got %s %s", ops[0].Op, ops[0].Path)
+		}
+		value, ok := ops[0].Value.(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected relation object, got %T", ops[0].Value)
+		}
+		if value["rel"] != "System.LinkTypes.Hierarchy-Reverse" {
+			t.Errorf("Expected Hierarchy-Reverse relation, got %v", value["rel"])
+		}
+		wantURL := "https://dev.azure.com/testorg/testproject/_apis/wit/workItems/100"
+		if value["url"] != wantURL {
+			t.Errorf("Expected parent URL %s, got %v", wantURL, value["url"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(WorkItem{ID: 101})
+	})
+	defer server.Close()
+
+	if err := client.AddParentLink(101, 100); err != nil {
+		t.Fatalf("AddParentLink failed: %v", err)
+	}
+}
+
+func TestAddParentLinkError(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Item already has a parent"))
+	})
+	defer server.Close()
+
+	err := client.AddParentLink(101, 100)
+	if err == nil {
+		t.Error("Expected error for failed link")
+	}
+}
+
 func TestRemoveRelation(t *testing.T) {
 	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "PATCH" {
@@ -1388,6 +1736,85 @@ func TestGetCommentsAPIError(t *testing.T) {
 	}
 }
 
+// commentPagesHandler serves two pages of comments linked by a continuation token
+func commentPagesHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("$top") == "" {
+			t.Error("Expected $top page size")
+		}
+		var response CommentsResponse
+		switch token := r.URL.Query().Get("continuationToken"); token {
+		case "":
+			response = CommentsResponse{
+				Count:             2,
+				TotalCount:        3,
+				Comments:          []Comment{{ID: 1, Text: "First"}, {ID: 2, Text: "Second"}},
+				ContinuationToken: "page/2+",
+			}
+		case "page/2+":
+			response = CommentsResponse{Count: 1, TotalCount: 3, Comments: []Comment{{ID: 3, Text: "Third"}}}
+		default:
+			t.Errorf("unexpected continuation token %q", token)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}
+}
+
+func TestGetCommentsPage(t *testing.T) {
+	client, server := testClientWithMockTransport(commentPagesHandler(t))
+	defer server.Close()
+
+	comments, next, err := client.GetCommentsPage(123, "")
+	if err != nil {
+		t.Fatalf("GetCommentsPage failed: %v", err)
+	}
+	if len(comments) != 2 || next != "page/2+" {
+		t.Fatalf("first page = %d comments, token %q; want 2 comments and a token", len(comments), next)
+	}
+
+	comments, next, err = client.GetCommentsPage(123, next)
+	if err != nil {
+		t.Fatalf("GetCommentsPage failed: %v", err)
+	}
+	if len(comments) != 1 || comments[0].Text != "Third" || next != "" {
+		t.Errorf("second page = %+v, token %q; want the third comment and no token", comments, next)
+	}
+}
+
+func TestGetCommentsPageOrdered(t *testing.T) {
+	var orders []string
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		orders = append(orders, r.URL.Query().Get("order"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"comments": []}`))
+	})
+	defer server.Close()
+
+	if _, _, err := client.GetCommentsPageOrdered(123, "", true); err != nil {
+		t.Fatalf("GetCommentsPageOrdered failed: %v", err)
+	}
+	if _, _, err := client.GetCommentsPage(123, ""); err != nil {
+		t.Fatalf("GetCommentsPage failed: %v", err)
+	}
+	if len(orders) != 2 || orders[0] != "desc" || orders[1] != "" {
+		t.Errorf("Expected desc only for newest first, got %q", orders)
+	}
+}
+
+func TestGetCommentsFollowsContinuationToken(t *testing.T) {
+	client, server := testClientWithMockTransport(commentPagesHandler(t))
+	defer server.Close()
+
+	comments, err := client.GetComments(123)
+	if err != nil {
+		t.Fatalf("GetComments failed: %v", err)
+	}
+	if len(comments) != 3 || comments[2].Text != "Third" {
+		t.Errorf("GetComments returned %d comments, want all 3 across both pages", len(comments))
+	}
+}
+
 func TestAddComment(t *testing.T) {
 	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
@@ -1458,45 +1885,145 @@ func TestUpdateWorkItemError(t *testing.T) {
 	}
 }
 
-func TestGetWorkItemWithRelations(t *testing.T) {
+func TestUpdateWorkItemWithReason(t *testing.T) {
+	var ops []CreateWorkItemOp
 	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != "GET" {
-			t.Errorf("Expected GET, got %s", r.Method)
-		}
-
-		response := WorkItem{
-			ID:     123,
-			Fields: WorkItemFields{Title: "Test Item"},
-			Relations: []WorkItemRelation{
-				{Rel: "System.LinkTypes.Hierarchy-Forward", URL: "https://dev.azure.com/org/proj/_apis/wit/workItems/124"},
-			},
-		}
+		_ = json.NewDecoder(r.Body).Decode(&ops)
 		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(response)
+		_ = json.NewEncoder(w).Encode(WorkItem{ID: 123, Fields: WorkItemFields{State: "Resolved", Reason: "Fixed"}})
 	})
 	defer server.Close()
 
-	wi, err := client.GetWorkItemWithRelations(123)
+	wi, err := client.UpdateWorkItemWithReason(123, "Title", "Resolved", "Fixed", "", "")
 	if err != nil {
-		t.Fatalf("GetWorkItemWithRelations failed: %v", err)
+		t.Fatalf("UpdateWorkItemWithReason failed: %v", err)
+	}
+	if wi.Fields.Reason != "Fixed" {
+		t.Errorf("Reason = %q, want Fixed", wi.Fields.Reason)
+	}
+	found := false
+	for _, op := range ops {
+		if op.Path == "/fields/System.Reason" && op.Value == "Fixed" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("ops = %+v, want a System.Reason op", ops)
 	}
 
-	if wi.ID != 123 {
-		t.Errorf("ID = %d, want 123", wi.ID)
+	if _, err := client.UpdateWorkItem(123, "Title", "Active", "", ""); err != nil {
+		t.Fatalf("UpdateWorkItem failed: %v", err)
 	}
-	if len(wi.Relations) != 1 {
-		t.Errorf("Relations count = %d, want 1", len(wi.Relations))
+	for _, op := range ops {
+		if op.Path == "/fields/System.Reason" {
+			t.Error("UpdateWorkItem should leave the reason to Azure DevOps")
+		}
 	}
 }
 
-func TestGetWorkItemWithRelationsError(t *testing.T) {
+func TestGetWorkItemStates(t *testing.T) {
 	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusNotFound)
-		_, _ = w.Write([]byte("Work item not found"))
+		if !strings.HasSuffix(r.URL.Path, "/_apis/wit/workitemtypes/User Story") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"name": "User Story",
+			"states": [
+				{"name": "New", "color": "b2b2b2", "category": "Proposed"},
+				{"name": "Active", "color": "007acc", "category": "InProgress"},
+				{"name": "Closed", "color": "339933", "category": "Completed"}
+			],
+			"transitions": {
+				"New": [{"to": "New"}, {"to": "Active"}, {"to": "Closed"}],
+				"Active": [{"to": "New"}, {"to": "Closed"}],
+				"Closed": [{"to": "Active"}]
+			}
+		}`))
 	})
 	defer server.Close()
 
-	_, err := client.GetWorkItemWithRelations(999)
+	states, err := client.GetWorkItemStates("User Story")
+	if err != nil {
+		t.Fatalf("GetWorkItemStates failed: %v", err)
+	}
+	if len(states) != 3 || states[1].Name != "Active" || states[1].Category != "InProgress" {
+		t.Fatalf("states = %+v, want New, Active, Closed in order", states)
+	}
+	if got := states[0].Transitions; len(got) != 2 || got[0] != "Active" || got[1] != "Closed" {
+		t.Errorf("New transitions = %v, want [Active Closed] without the self-transition", got)
+	}
+}
+
+func TestGetStateReasons(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/fields/System.Reason") || r.URL.Query().Get("$expand") != "allowedValues" {
+			t.Errorf("unexpected request %s", r.URL.String())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"referenceName": "System.Reason", "name": "Reason", "allowedValues": ["New", "Fixed", "Obsolete"]}`))
+	})
+	defer server.Close()
+
+	reasons, err := client.GetStateReasons("Bug")
+	if err != nil {
+		t.Fatalf("GetStateReasons failed: %v", err)
+	}
+	if len(reasons) != 3 || reasons[1] != "Fixed" {
+		t.Errorf("reasons = %v, want [New Fixed Obsolete]", reasons)
+	}
+}
+
+func TestGetStateReasonsError(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	if _, err := client.GetStateReasons("Bug"); !IsNotFound(err) {
+		t.Errorf("err = %v, want a not found API error", err)
+	}
+}
+
+func TestGetWorkItemWithRelations(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("Expected GET, got %s", r.Method)
+		}
+
+		response := WorkItem{
+			ID:     123,
+			Fields: WorkItemFields{Title: "Test Item"},
+			Relations: []WorkItemRelation{
+				{Rel: "System.LinkTypes.Hierarchy-Forward", URL: "https://dev.azure.com/org/proj/_apis/wit/workItems/124"},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+	defer server.Close()
+
+	wi, err := client.GetWorkItemWithRelations(123)
+	if err != nil {
+		t.Fatalf("GetWorkItemWithRelations failed: %v", err)
+	}
+
+	if wi.ID != 123 {
+		t.Errorf("ID = %d, want 123", wi.ID)
+	}
+	if len(wi.Relations) != 1 {
+		t.Errorf("Relations count = %d, want 1", len(wi.Relations))
+	}
+}
+
+func TestGetWorkItemWithRelationsError(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("Work item not found"))
+	})
+	defer server.Close()
+
+	_, err := client.GetWorkItemWithRelations(999)
 	if err == nil {
 		t.Error("Expected error for not found")
 	}
@@ -1636,6 +2163,76 @@ func TestTestConnectionError(t *testing.T) {
 	}
 }
 
+func TestTestConnectionExpiredPAT(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-TFS-ServiceError", "Access+Denied%3a+The+Personal+Access+Token+used+has+expired.")
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	defer server.Close()
+
+	err := client.TestConnection()
+	if err == nil {
+		t.Fatal("Expected error for expired PAT")
+	}
+	if !strings.Contains(err.Error(), "PAT appears to have expired") {
+		t.Errorf("Expected expired PAT message, got: %v", err)
+	}
+}
+
+func TestTestConnectionUnauthorizedNotExpired(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("Invalid PAT"))
+	})
+	defer server.Close()
+
+	err := client.TestConnection()
+	if err == nil || strings.Contains(err.Error(), "expired") {
+		t.Errorf("Expected plain unauthorized error, got: %v", err)
+	}
+}
+
+func TestTestConnectionDeprecationWarning(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "api-version 7.0 is deprecated")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "project-id", "name": "testproject"}`))
+	})
+	defer server.Close()
+
+	if err := client.TestConnection(); err != nil {
+		t.Fatalf("TestConnection failed: %v", err)
+	}
+	if got := client.DeprecationWarning(); got != "api-version 7.0 is deprecated" {
+		t.Errorf("DeprecationWarning() = %q, want header value", got)
+	}
+}
+
+func TestConnectionNotices(t *testing.T) {
+	tests := []struct {
+		name            string
+		headers         map[string]string
+		wantDeprecation string
+		wantWarning     string
+	}{
+		{"none", nil, "", ""},
+		{"deprecation header", map[string]string{"Deprecation": "true"}, "true", ""},
+		{"deprecation warning", map[string]string{"Warning": `299 - "api-version 5.0 is deprecated"`}, `299 - "api-version 5.0 is deprecated"`, ""},
+		{"other warning", map[string]string{"Warning": `199 - "Service maintenance tonight"`}, "", `199 - "Service maintenance tonight"`},
+		{"both", map[string]string{"Deprecation": "true", "Warning": `199 - "Slow responses"`}, "true", `199 - "Slow responses"`},
+	}
+	for _, tt := range tests {
+		h := http.Header{}
+		for k, v := range tt.headers {
+			h.Set(k, v)
+		}
+		deprecation, warning := connectionNotices(h)
+		if deprecation != tt.wantDeprecation || warning != tt.wantWarning {
+			t.Errorf("%s: connectionNotices() = %q, %q, want %q, %q", tt.name, deprecation, warning, tt.wantDeprecation, tt.wantWarning)
+		}
+	}
+}
+
 func TestGetIterations(t *testing.T) {
 	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "GET" {
@@ -1695,7 +2292,7 @@ func TestUpdateWorkItemPlanning(t *testing.T) {
 	defer server.Close()
 
 	sp := 5.0
-	wi, err := client.UpdateWorkItemPlanning(123, &sp, nil, nil, nil)
+	wi, err := client.UpdateWorkItemPlanning(123, &sp, nil, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("UpdateWorkItemPlanning failed: %v", err)
 	}
@@ -1708,7 +2305,7 @@ func TestUpdateWorkItemPlanning(t *testing.T) {
 func TestUpdateWorkItemPlanningNoUpdates(t *testing.T) {
 	client := NewClient("org", "proj", "", "", "pat")
 
-	_, err := client.UpdateWorkItemPlanning(123, nil, nil, nil, nil)
+	_, err := client.UpdateWorkItemPlanning(123, nil, nil, nil, nil, nil)
 	if err == nil {
 		t.Error("Expected error when no updates specified")
 	}
@@ -1725,7 +2322,7 @@ func TestUpdateWorkItemPlanningError(t *testing.T) {
 	defer server.Close()
 
 	sp := 5.0
-	_, err := client.UpdateWorkItemPlanning(123, &sp, nil, nil, nil)
+	_, err := client.UpdateWorkItemPlanning(123, &sp, nil, nil, nil, nil)
 	if err == nil {
 		t.Error("Expected error for bad request")
 	}
@@ -1900,6 +2497,55 @@ func TestGetRecentlyChangedWorkItemsEmpty(t *testing.T) {
 	}
 }
 
+func TestGetRecentlyMentionedWorkItems(t *testing.T) {
+	var query string
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/wiql") {
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			query = body["query"]
+			_ = json.NewEncoder(w).Encode(WorkItemQueryResult{WorkItems: []WorkItemRef{{ID: 7}}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(WorkItemListResponse{Count: 1, Value: []WorkItem{{ID: 7}}})
+	})
+	defer server.Close()
+
+	items, err := client.GetRecentlyMentionedWorkItems("user@example.com", 2)
+	if err != nil {
+		t.Fatalf("GetRecentlyMentionedWorkItems failed: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != 7 {
+		t.Errorf("Expected item 7, got %v", items)
+	}
+	if !strings.Contains(query, "[System.Id] IN (@RecentMentions)") || !strings.Contains(query, "[System.ChangedBy] <> 'user@example.com'") {
+		t.Errorf("Expected recent mentions changed by others, got %s", query)
+	}
+	if strings.Contains(query, "[System.AssignedTo]") {
+		t.Errorf("Expected mentions not to be limited to assigned items, got %s", query)
+	}
+}
+
+func TestGetAuthenticatedUserID(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/testorg/_apis/connectionData" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"authenticatedUser": {"id": "8c8c7d32-6b1b-47f4-b2e9-30b477b5ab3d", "providerDisplayName": "Jane Doe"}}`))
+	})
+	defer server.Close()
+
+	id, err := client.GetAuthenticatedUserID()
+	if err != nil {
+		t.Fatalf("GetAuthenticatedUserID failed: %v", err)
+	}
+	if id != "8c8c7d32-6b1b-47f4-b2e9-30b477b5ab3d" {
+		t.Errorf("id = %q", id)
+	}
+}
+
 func TestUpdateWorkItemIteration(t *testing.T) {
 	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "PATCH" {
@@ -1981,9 +2627,12 @@ func TestGetHyperlinksAPI(t *testing.T) {
 
 func TestAddHyperlinkAPI(t *testing.T) {
 	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
-		body, _ := io.ReadAll(r.Body)
-		if !strings.Contains(string(body), "Hyperlink") {
-			t.Error("Expected Hyperlink relation type in body")
+		// The item's existing relations are fetched first to catch duplicates
+		if r.Method == "PATCH" {
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), "Hyperlink") {
+				t.Error("Expected Hyperlink relation type in body")
+			}
 		}
 
 		response := WorkItem{ID: 123}
@@ -1998,6 +2647,106 @@ func TestAddHyperlinkAPI(t *testing.T) {
 	}
 }
 
+func TestAddArtifactLinkAPI(t *testing.T) {
+	const prURI = "vstfs:///Git/PullRequestId/proj-id%2Frepo-id%2F42"
+	var ops []CreateWorkItemOp
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/workitems/123") {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Method == "PATCH" {
+			if err := json.NewDecoder(r.Body).Decode(&ops); err != nil {
+				t.Fatalf("Failed to decode body: %v", err)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(WorkItem{ID: 123})
+	})
+	defer server.Close()
+
+	if err := client.AddArtifactLink(123, prURI, "Pull Request", "Fix for the login bug"); err != nil {
+		t.Fatalf("AddArtifactLink failed: %v", err)
+	}
+
+	if len(ops) != 1 || ops[0].Op != "add" || ops[0].Path != "/relations/-" {
+		t.Fatalf("Expected one add relation op, got %+v", ops)
+	}
+	value, ok := ops[0].Value.(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected a relation object, got %T", ops[0].Value)
+	}
+	if value["rel"] != "ArtifactLink" {
+		t.Errorf("rel = %v, want ArtifactLink", value["rel"])
+	}
+	if value["url"] != prURI {
+		t.Errorf("url = %v, want %s", value["url"], prURI)
+	}
+	attributes, _ := value["attributes"].(map[string]interface{})
+	if attributes["name"] != "Pull Request" || attributes["comment"] != "Fix for the login bug" {
+		t.Errorf("Unexpected attributes %v", attributes)
+	}
+}
+
+func TestAddArtifactLinkValidation(t *testing.T) {
+	client := NewClient("org", "proj", "", "", "pat")
+	tests := []struct {
+		name     string
+		uri      string
+		linkName string
+		comment  string
+		wantErr  string
+	}{
+		{"https URL", "https://github.com/owner/repo/pull/1", "Pull Request", "", "vstfs"},
+		{"no scheme", "Git/Commit/abc", "Fixed in Commit", "", "vstfs"},
+		{"missing name", "vstfs:///Git/Commit/abc", "", "", "name is required"},
+		{"long comment", "vstfs:///Git/Commit/abc", "Fixed in Commit", strings.Repeat("a", 501), "comment too long"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.AddArtifactLink(123, tt.uri, tt.linkName, tt.comment)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAddHyperlinkAlreadyExists(t *testing.T) {
+	tests := []struct {
+		name string
+		add  func(c *Client) error
+	}{
+		{"hyperlink", func(c *Client) error { return c.AddHyperlink(123, "https://example.com/docs", "") }},
+		{"artifact", func(c *Client) error {
+			return c.AddArtifactLink(123, "vstfs:///Git/Commit/abc", "Fixed in Commit", "")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "GET" {
+					t.Errorf("Expected no %s once the link was found", r.Method)
+				}
+				response := WorkItem{
+					ID: 123,
+					Relations: []WorkItemRelation{
+						{Rel: "Hyperlink", URL: "https://example.com/docs"},
+						{Rel: "ArtifactLink", URL: "vstfs:///Git/Commit/abc"},
+					},
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(response)
+			})
+			defer server.Close()
+
+			err := tt.add(client)
+			if err == nil || !strings.Contains(err.Error(), "link already exists") {
+				t.Errorf("Expected a link already exists error, got %v", err)
+			}
+		})
+	}
+}
+
 func TestRemoveHyperlinkAPI(t *testing.T) {
 	requestCount := 0
 	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
@@ -2150,6 +2899,12 @@ func TestTruncateError(t *testing.T) {
 			maxLen:   5,
 			expected: "12345",
 		},
+		{
+			name:     "multi-byte characters kept whole",
+			input:    "Fehler: Größe überschritten",
+			maxLen:   12,
+			expected: "Fehler: Grö...",
+		},
 	}
 
 	for _, tt := range tests {
@@ -2161,3 +2916,1166 @@ func TestTruncateError(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildWorkItemQueryExcludeStates(t *testing.T) {
+	client := NewClient("org", "proj", "", "", "pat")
+
+	query := client.buildWorkItemQuery(WorkItemFilter{ExcludeStates: []string{"Closed", "Done"}})
+	if !strings.Contains(query, "AND [System.State] NOT IN ('Closed', 'Done')") {
+		t.Errorf("Expected NOT IN clause for excluded states, got: %s", query)
+	}
+
+	query = client.buildWorkItemQuery(WorkItemFilter{})
+	if strings.Contains(query, "NOT IN") {
+		t.Errorf("Expected no state clause without excluded states, got: %s", query)
+	}
+}
+
+func TestBuildWorkItemQueryIterationPath(t *testing.T) {
+	client := NewClient("org", "proj", "", "", "pat")
+
+	query := client.buildWorkItemQuery(WorkItemFilter{IterationPath: "proj\\Sprint 2"})
+	if !strings.Contains(query, "AND [System.IterationPath] UNDER 'proj\\Sprint 2'") {
+		t.Errorf("Expected UNDER clause for the iteration, got: %s", query)
+	}
+
+	// Quotes in the path are escaped
+	query = client.buildWorkItemQuery(WorkItemFilter{IterationPath: "proj\\Q1 'Launch'"})
+	if !strings.Contains(query, "AND [System.IterationPath] UNDER 'proj\\Q1 ''Launch'''") {
+		t.Errorf("Expected quotes in the iteration path to be escaped, got: %s", query)
+	}
+
+	query = client.buildWorkItemQuery(WorkItemFilter{})
+	if strings.Contains(query, "IterationPath") {
+		t.Errorf("Expected no iteration clause without an iteration filter, got: %s", query)
+	}
+}
+
+func TestBuildWorkItemQueryCurrentIteration(t *testing.T) {
+	client := NewClient("org", "proj", "Web Team", "", "pat")
+
+	query := client.buildWorkItemQuery(WorkItemFilter{CurrentIteration: true})
+	if !strings.Contains(query, "AND [System.IterationPath] = @CurrentIteration('[proj]\\Web Team')") {
+		t.Errorf("Expected @CurrentIteration macro for the team, got: %s", query)
+	}
+
+	// Without a team the project's default team is used; quotes are escaped
+	client = NewClient("org", "O'Brien", "", "", "pat")
+	query = client.buildWorkItemQuery(WorkItemFilter{CurrentIteration: true})
+	if !strings.Contains(query, "@CurrentIteration('[O''Brien]\\O''Brien Team')") {
+		t.Errorf("Expected the default team in the macro, got: %s", query)
+	}
+
+	query = client.buildWorkItemQuery(WorkItemFilter{})
+	if strings.Contains(query, "@CurrentIteration") {
+		t.Errorf("Expected no macro without the current iteration filter, got: %s", query)
+	}
+}
+
+func TestBuildWorkItemQuerySearch(t *testing.T) {
+	client := NewClient("org", "proj", "", "", "pat")
+
+	query := client.buildWorkItemQuery(WorkItemFilter{Search: "login"})
+	if !strings.Contains(query, "AND ([System.Title] CONTAINS 'login' OR [System.Tags] CONTAINS 'login')") {
+		t.Errorf("Expected title/tags OR clause, got: %s", query)
+	}
+	if strings.Contains(query, "[System.Id] =") {
+		t.Errorf("Expected no ID condition for non-numeric text, got: %s", query)
+	}
+
+	query = client.buildWorkItemQuery(WorkItemFilter{Search: "#42"})
+	if !strings.Contains(query, "[System.Title] CONTAINS '#42' OR [System.Tags] CONTAINS '#42' OR [System.Id] = 42)") {
+		t.Errorf("Expected ID condition for a numeric search, got: %s", query)
+	}
+
+	query = client.buildWorkItemQuery(WorkItemFilter{Search: "it's"})
+	if !strings.Contains(query, "CONTAINS 'it''s'") {
+		t.Errorf("Expected quotes to be escaped, got: %s", query)
+	}
+
+	query = client.buildWorkItemQuery(WorkItemFilter{Search: "  "})
+	if strings.Contains(query, "CONTAINS") {
+		t.Errorf("Expected no search clause for blank text, got: %s", query)
+	}
+}
+
+func TestGetTeamFieldValuesScopesQueries(t *testing.T) {
+	var wiql string
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/testteam/_apis/work/teamsettings/teamfieldvalues"):
+			_, _ = w.Write([]byte(`{
+				"field": {"referenceName": "System.AreaPath"},
+				"defaultValue": "testproject\\Web",
+				"values": [
+					{"value": "testproject\\Web", "includeChildren": true},
+					{"value": "testproject\\Shared", "includeChildren": false}
+				]
+			}`))
+		case strings.Contains(r.URL.Path, "/wiql"):
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			wiql = body["query"]
+			_ = json.NewEncoder(w).Encode(WorkItemQueryResult{})
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+	client.AreaPath = ""
+
+	if !client.UsesTeamAreas() {
+		t.Fatal("Expected a team without an area path to use the team's areas")
+	}
+	values, err := client.GetTeamFieldValues()
+	if err != nil {
+		t.Fatalf("GetTeamFieldValues failed: %v", err)
+	}
+	if values.DefaultValue != "testproject\\Web" || len(values.Values) != 2 || !values.Values[0].IncludeChildren {
+		t.Errorf("Unexpected team field values %+v", values)
+	}
+
+	client.SetTeamAreas(values)
+	if _, err := client.QueryWorkItems(WorkItemFilter{}, 10, 0); err != nil {
+		t.Fatalf("QueryWorkItems failed: %v", err)
+	}
+	want := " AND ([System.AreaPath] UNDER 'testproject\\Web' OR [System.AreaPath] = 'testproject\\Shared')"
+	if !strings.Contains(wiql, want) {
+		t.Errorf("Expected the team area clause in WIQL, got: %s", wiql)
+	}
+	if got := client.defaultAreaPath(); got != "testproject\\Web" {
+		t.Errorf("defaultAreaPath() = %q, want the team default", got)
+	}
+}
+
+func TestSetTeamAreasIgnoresCustomTeamField(t *testing.T) {
+	client := NewClient("org", "proj", "team", "", "pat")
+	values := &TeamFieldValues{Values: []TeamFieldValue{{Value: "Contoso"}}}
+	values.Field.ReferenceName = "Custom.Customer"
+
+	client.SetTeamAreas(values)
+	if clause := client.areaClause(); clause != "" {
+		t.Errorf("Expected no area clause for a custom team field, got %q", clause)
+	}
+}
+
+func TestAreaClauseEscapesQuotes(t *testing.T) {
+	client := NewClient("org", "proj", "team", "", "pat")
+	client.SetTeamAreas(&TeamFieldValues{Values: []TeamFieldValue{
+		{Value: "proj\\Bob's Team", IncludeChildren: true},
+		{Value: "proj\\O'Neil"},
+	}})
+	want := " AND ([System.AreaPath] UNDER 'proj\\Bob''s Team' OR [System.AreaPath] = 'proj\\O''Neil')"
+	if clause := client.areaClause(); clause != want {
+		t.Errorf("areaClause() = %q, want %q", clause, want)
+	}
+
+	client.AreaPath = "proj\\Bob's Team"
+	if clause := client.areaClause(); clause != " AND [System.AreaPath] UNDER 'proj\\Bob''s Team'" {
+		t.Errorf("areaClause() = %q", clause)
+	}
+}
+
+func TestAreaPathOverridesTeamAreas(t *testing.T) {
+	client := NewClient("org", "proj", "team", "proj\\Mine", "pat")
+	if client.UsesTeamAreas() {
+		t.Error("Expected a configured area path to take precedence")
+	}
+	client.SetTeamAreas(&TeamFieldValues{DefaultValue: "proj\\Team", Values: []TeamFieldValue{{Value: "proj\\Team"}}})
+	if clause := client.areaClause(); clause != " AND [System.AreaPath] UNDER 'proj\\Mine'" {
+		t.Errorf("areaClause() = %q", clause)
+	}
+}
+
+func TestBuildWorkItemQueryOrderBy(t *testing.T) {
+	client := NewClient("org", "proj", "", "", "pat")
+	tests := []struct {
+		sort WorkItemSort
+		want string
+	}{
+		{WorkItemSort{}, " ORDER BY [System.ChangedDate] DESC"},
+		{WorkItemSort{Field: "System.CreatedDate"}, " ORDER BY [System.CreatedDate] DESC"},
+		{WorkItemSort{Field: "Microsoft.VSTS.Common.Priority", Ascending: true}, " ORDER BY [Microsoft.VSTS.Common.Priority] ASC"},
+		{WorkItemSort{Field: "System.Id", Ascending: true}, " ORDER BY [System.Id] ASC"},
+	}
+	for _, tt := range tests {
+		query := client.buildWorkItemQuery(WorkItemFilter{Sort: tt.sort})
+		if !strings.HasSuffix(query, tt.want) {
+			t.Errorf("sort %+v: expected query to end with %q, got: %s", tt.sort, tt.want, query)
+		}
+	}
+}
+
+func TestQueryWorkItemsSendsFilter(t *testing.T) {
+	requestCount := 0
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if requestCount == 1 {
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), "NOT IN ('Removed')") {
+				t.Errorf("Expected excluded state in WIQL, got: %s", string(body))
+			}
+			response := WorkItemQueryResult{WorkItems: []WorkItemRef{{ID: 1}}}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(response)
+		} else {
+			response := WorkItemListResponse{Count: 1, Value: []WorkItem{{ID: 1}}}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(response)
+		}
+	})
+	defer server.Close()
+
+	items, err := client.QueryWorkItems(WorkItemFilter{ExcludeStates: []string{"Removed"}}, 10, 0)
+	if err != nil {
+		t.Fatalf("QueryWorkItems failed: %v", err)
+	}
+	if len(items) != 1 {
+		t.Errorf("Expected 1 item, got %d", len(items))
+	}
+}
+
+func TestWorkItemWebURL(t *testing.T) {
+	client := NewClient("myorg", "myproject", "myteam", "", "pat")
+	expected := "https://dev.azure.com/myorg/myproject/_workitems/edit/42"
+	if got := client.WorkItemWebURL(42); got != expected {
+		t.Errorf("WorkItemWebURL() = %v, want %v", got, expected)
+	}
+}
+
+func TestUpdateWorkItemPlanningDynamicEffort(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		var ops []CreateWorkItemOp
+		if err := json.NewDecoder(r.Body).Decode(&ops); err != nil {
+			t.Fatalf("Failed to decode body: %v", err)
+		}
+		if len(ops) != 1 {
+			t.Fatalf("Expected 1 op, got %d", len(ops))
+		}
+		if ops[0].Op != "add" || ops[0].Path != "/fields/Microsoft.VSTS.Scheduling.Effort" || ops[0].Value != 5.0 {
+			t.Errorf("Unexpected op: %+v", ops[0])
+		}
+
+		effort := 5.0
+		response := WorkItem{ID: 123, Fields: WorkItemFields{Effort: &effort}}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+	defer server.Close()
+
+	wi, err := client.UpdateWorkItemPlanningDynamic(123, map[string]float64{"Microsoft.VSTS.Scheduling.Effort": 5.0})
+	if err != nil {
+		t.Fatalf("UpdateWorkItemPlanningDynamic failed: %v", err)
+	}
+	if wi.Fields.Effort == nil || *wi.Fields.Effort != 5.0 {
+		t.Error("Expected Effort to round-trip as 5.0")
+	}
+}
+
+func TestUpdateWorkItemPlanningEffort(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "Microsoft.VSTS.Scheduling.Effort") {
+			t.Error("Expected Effort field in body")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(WorkItem{ID: 123})
+	})
+	defer server.Close()
+
+	effort := 3.0
+	if _, err := client.UpdateWorkItemPlanning(123, nil, nil, nil, nil, &effort); err != nil {
+		t.Fatalf("UpdateWorkItemPlanning failed: %v", err)
+	}
+}
+
+func TestGetMyWorkItems(t *testing.T) {
+	requestCount := 0
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if requestCount == 1 {
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), "[System.AssignedTo] = 'me@example.com'") {
+				t.Errorf("Expected AssignedTo clause in WIQL, got: %s", string(body))
+			}
+			response := WorkItemQueryResult{WorkItems: []WorkItemRef{{ID: 7}}}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(response)
+		} else {
+			response := WorkItemListResponse{Count: 1, Value: []WorkItem{{ID: 7}}}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(response)
+		}
+	})
+	defer server.Close()
+
+	items, err := client.GetMyWorkItems("me@example.com", 25)
+	if err != nil {
+		t.Fatalf("GetMyWorkItems failed: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != 7 {
+		t.Errorf("Expected item 7, got %+v", items)
+	}
+}
+
+func TestGetMyWorkItemsEmptyUsername(t *testing.T) {
+	client := NewClient("org", "proj", "", "", "pat")
+
+	if _, err := client.GetMyWorkItems("", 10); err == nil {
+		t.Error("Expected error when username is empty")
+	}
+}
+
+func TestUpdateWorkItemArea(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" {
+			t.Errorf("Expected PATCH, got %s", r.Method)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "/fields/System.AreaPath") {
+			t.Error("Expected AreaPath op in body")
+		}
+		if !strings.Contains(string(body), `Project\\Other Team`) {
+			t.Errorf("Expected new area path in body, got %s", body)
+		}
+
+		response := WorkItem{
+			ID:     123,
+			Fields: WorkItemFields{Title: "Test", AreaPath: "Project\\Other Team"},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+	defer server.Close()
+
+	wi, err := client.UpdateWorkItemArea(123, "Project\\Other Team")
+	if err != nil {
+		t.Fatalf("UpdateWorkItemArea failed: %v", err)
+	}
+
+	if wi.Fields.AreaPath != "Project\\Other Team" {
+		t.Errorf("AreaPath = %s, want 'Project\\Other Team'", wi.Fields.AreaPath)
+	}
+}
+
+func TestUpdateWorkItemAreaEmptyPath(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request for an empty area path")
+	})
+	defer server.Close()
+
+	_, err := client.UpdateWorkItemArea(123, "  ")
+	if err == nil {
+		t.Error("Expected error for empty area path")
+	}
+}
+
+func TestGetAreas(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/classificationnodes/areas") {
+			t.Errorf("Expected classification nodes URL, got %s", r.URL.Path)
+		}
+
+		response := ClassificationNode{
+			Name: "Project",
+			Path: "\\Project\\Area",
+			Children: []ClassificationNode{
+				{
+					Name: "Team",
+					Path: "\\Project\\Area\\Team",
+					Children: []ClassificationNode{
+						{Name: "Sub", Path: "\\Project\\Area\\Team\\Sub"},
+					},
+				},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+	defer server.Close()
+
+	areas, err := client.GetAreas()
+	if err != nil {
+		t.Fatalf("GetAreas failed: %v", err)
+	}
+
+	want := []string{"Project", "Project\\Team", "Project\\Team\\Sub"}
+	if len(areas) != len(want) {
+		t.Fatalf("GetAreas() = %v, want %v", areas, want)
+	}
+	for i := range want {
+		if areas[i] != want[i] {
+			t.Errorf("areas[%d] = %q, want %q", i, areas[i], want[i])
+		}
+	}
+}
+
+func TestQueryWorkItemsPagesFromCachedIDs(t *testing.T) {
+	wiqlCalls := 0
+	var requestedIDs []string
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/wiql") {
+			wiqlCalls++
+			refs := make([]WorkItemRef, 50)
+			for i := range refs {
+				refs[i] = WorkItemRef{ID: i + 1}
+			}
+			_ = json.NewEncoder(w).Encode(WorkItemQueryResult{WorkItems: refs})
+			return
+		}
+		ids := r.URL.Query().Get("ids")
+		requestedIDs = append(requestedIDs, ids)
+		var items []WorkItem
+		for _, id := range strings.Split(ids, ",") {
+			var n int
+			_, _ = fmt.Sscanf(id, "%d", &n)
+			items = append(items, WorkItem{ID: n})
+		}
+		_ = json.NewEncoder(w).Encode(WorkItemListResponse{Count: len(items), Value: items})
+	})
+	defer server.Close()
+
+	filter := WorkItemFilter{AssignedTo: "me@example.com"}
+	if _, err := client.QueryWorkItems(filter, 10, 0); err != nil {
+		t.Fatalf("QueryWorkItems page 0 failed: %v", err)
+	}
+
+	requestedIDs = nil
+	items, err := client.QueryWorkItems(filter, 10, 30)
+	if err != nil {
+		t.Fatalf("QueryWorkItems page 3 failed: %v", err)
+	}
+
+	if wiqlCalls != 1 {
+		t.Errorf("Expected the WIQL query to run once and be cached, ran %d times", wiqlCalls)
+	}
+	if len(requestedIDs) != 1 || requestedIDs[0] != "31,32,33,34,35,36,37,38,39,40" {
+		t.Errorf("Expected page 3 to request only IDs 31-40, got %v", requestedIDs)
+	}
+	if len(items) != 10 || items[0].ID != 31 {
+		t.Errorf("Expected items 31-40, got %+v", items)
+	}
+
+	// Going back to the first page re-runs the query to pick up changes
+	if _, err := client.QueryWorkItems(filter, 10, 0); err != nil {
+		t.Fatalf("QueryWorkItems refresh failed: %v", err)
+	}
+	if wiqlCalls != 2 {
+		t.Errorf("Expected the first page to refresh the query, ran %d times", wiqlCalls)
+	}
+
+	// Past the end returns no items without a fetch
+	requestedIDs = nil
+	items, err = client.QueryWorkItems(filter, 10, 50)
+	if err != nil || len(items) != 0 || len(requestedIDs) != 0 {
+		t.Errorf("Expected no items past the end, got %v (requests %v, err %v)", items, requestedIDs, err)
+	}
+}
+
+func TestCountWorkItemsUsesCachedIDs(t *testing.T) {
+	wiqlCalls := 0
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		wiqlCalls++
+		_ = json.NewEncoder(w).Encode(WorkItemQueryResult{WorkItems: []WorkItemRef{{ID: 1}, {ID: 2}, {ID: 3}}})
+	})
+	defer server.Close()
+
+	for range 2 {
+		count, err := client.CountWorkItems(WorkItemFilter{})
+		if err != nil {
+			t.Fatalf("CountWorkItems failed: %v", err)
+		}
+		if count != 3 {
+			t.Errorf("count = %d, want 3", count)
+		}
+	}
+	if wiqlCalls != 1 {
+		t.Errorf("Expected the count to come from the cached ID list, ran the query %d times", wiqlCalls)
+	}
+}
+
+func TestQueryWorkItemsBatchesLargeWindows(t *testing.T) {
+	var batchSizes []int
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/wiql") {
+			refs := make([]WorkItemRef, 450)
+			for i := range refs {
+				refs[i] = WorkItemRef{ID: i + 1}
+			}
+			_ = json.NewEncoder(w).Encode(WorkItemQueryResult{WorkItems: refs})
+			return
+		}
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		batchSizes = append(batchSizes, len(ids))
+		items := make([]WorkItem, len(ids))
+		_ = json.NewEncoder(w).Encode(WorkItemListResponse{Count: len(items), Value: items})
+	})
+	defer server.Close()
+
+	items, err := client.QueryWorkItems(WorkItemFilter{}, 450, 0)
+	if err != nil {
+		t.Fatalf("QueryWorkItems failed: %v", err)
+	}
+	if len(items) != 450 {
+		t.Errorf("Expected 450 items, got %d", len(items))
+	}
+	if len(batchSizes) != 3 || batchSizes[0] != 200 || batchSizes[2] != 50 {
+		t.Errorf("Expected batches of 200, 200, 50, got %v", batchSizes)
+	}
+}
+
+func TestGetPlanningFieldsServerNames(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/_apis/wit/fields") {
+			_ = json.NewEncoder(w).Encode(FieldsResponse{
+				Value: []FieldDefinition{
+					{ReferenceName: "Microsoft.VSTS.Scheduling.StoryPoints", Type: "double"},
+					{ReferenceName: "Microsoft.VSTS.Scheduling.Size", Type: "integer"},
+					{ReferenceName: "Microsoft.VSTS.Scheduling.TargetDate", Type: "dateTime"},
+				},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(WorkItemTypeFieldsResponse{
+			Value: []WorkItemTypeField{
+				{ReferenceName: "Microsoft.VSTS.Scheduling.StoryPoints", Name: "Complexity Points"},
+				{ReferenceName: "Microsoft.VSTS.Scheduling.Size", Name: "Size"},
+				{ReferenceName: "Microsoft.VSTS.Scheduling.TargetDate", Name: "Target Date"},
+				{ReferenceName: "System.Title", Name: "Title"},
+			},
+		})
+	})
+	defer server.Close()
+
+	fields, err := client.GetPlanningFields("User Story")
+	if err != nil {
+		t.Fatalf("GetPlanningFields failed: %v", err)
+	}
+
+	if len(fields) != 2 {
+		t.Fatalf("Expected StoryPoints and Size (dates excluded), got %+v", fields)
+	}
+	if fields[0].DisplayName != "Complexity Points" {
+		t.Errorf("DisplayName = %q, want server name 'Complexity Points'", fields[0].DisplayName)
+	}
+	if fields[1].ReferenceName != "Microsoft.VSTS.Scheduling.Size" {
+		t.Errorf("Expected additional numeric scheduling field, got %s", fields[1].ReferenceName)
+	}
+}
+
+func TestGetFieldTypesCached(t *testing.T) {
+	fieldRequests := 0
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/_apis/wit/fields") {
+			fieldRequests++
+			_ = json.NewEncoder(w).Encode(FieldsResponse{
+				Value: []FieldDefinition{{ReferenceName: "Microsoft.VSTS.Scheduling.Size", Type: "integer"}},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(WorkItemTypeFieldsResponse{
+			Value: []WorkItemTypeField{{ReferenceName: "Microsoft.VSTS.Scheduling.Size", Name: "Size"}},
+		})
+	})
+	defer server.Close()
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetPlanningFields("User Story"); err != nil {
+			t.Fatalf("GetPlanningFields failed: %v", err)
+		}
+	}
+	if fieldRequests != 1 {
+		t.Errorf("Expected the organization's fields to be fetched once, got %d requests", fieldRequests)
+	}
+}
+
+func TestWorkItemFieldsPlanningValue(t *testing.T) {
+	var wi WorkItem
+	data := `{"id": 1, "fields": {
+		"System.Title": "Story",
+		"Microsoft.VSTS.Scheduling.StoryPoints": 5,
+		"Microsoft.VSTS.Scheduling.Size": 3,
+		"Microsoft.VSTS.Scheduling.TargetDate": "2026-01-01T00:00:00Z"
+	}}`
+	if err := json.Unmarshal([]byte(data), &wi); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if wi.Fields.Title != "Story" || wi.Fields.StoryPoints == nil || *wi.Fields.StoryPoints != 5 {
+		t.Errorf("Expected the named fields to decode as before, got %+v", wi.Fields)
+	}
+	if v := wi.Fields.PlanningValue("Microsoft.VSTS.Scheduling.Size"); v == nil || *v != 3 {
+		t.Errorf("PlanningValue(Size) = %v, want 3", v)
+	}
+	if v := wi.Fields.PlanningValue("Microsoft.VSTS.Scheduling.TargetDate"); v != nil {
+		t.Errorf("PlanningValue(TargetDate) = %v, want nil for a date", *v)
+	}
+
+	// Fields set directly are found too
+	effort := 8.0
+	fields := WorkItemFields{Effort: &effort}
+	if v := fields.PlanningValue("Microsoft.VSTS.Scheduling.Effort"); v == nil || *v != 8 {
+		t.Errorf("PlanningValue(Effort) = %v, want 8", v)
+	}
+}
+
+func TestGetPlanningFieldsWithoutFieldTypes(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/_apis/wit/fields") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(WorkItemTypeFieldsResponse{
+			Value: []WorkItemTypeField{
+				{ReferenceName: "Microsoft.VSTS.Scheduling.Effort"},
+				{ReferenceName: "Microsoft.VSTS.Scheduling.StartDate", Name: "Start Date"},
+			},
+		})
+	})
+	defer server.Close()
+
+	fields, err := client.GetPlanningFields("Product Backlog Item")
+	if err != nil {
+		t.Fatalf("GetPlanningFields failed: %v", err)
+	}
+	if len(fields) != 1 || fields[0].DisplayName != "Effort" {
+		t.Errorf("Expected only Effort with its default name, got %+v", fields)
+	}
+}
+
+func TestRestoreWorkItem(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" {
+			t.Errorf("Expected PATCH, got %s", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/_apis/wit/recyclebin/123") {
+			t.Errorf("Expected recycle bin URL for item 123, got %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"IsDeleted":false`) {
+			t.Errorf("Expected IsDeleted false in body, got %s", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":123}`))
+	})
+	defer server.Close()
+
+	if err := client.RestoreWorkItem(123); err != nil {
+		t.Fatalf("RestoreWorkItem failed: %v", err)
+	}
+}
+
+func TestRestoreWorkItemNotFound(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("Work item 999 does not exist in the recycle bin"))
+	})
+	defer server.Close()
+
+	err := client.RestoreWorkItem(999)
+	if err == nil {
+		t.Fatal("Expected error restoring an item not in the recycle bin")
+	}
+	if !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected 404 in error, got %v", err)
+	}
+}
+
+func TestListRecycleBin(t *testing.T) {
+	var detailIDs string
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/_apis/wit/recyclebin") {
+			t.Errorf("Expected recycle bin URL, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if ids := r.URL.Query().Get("ids"); ids != "" {
+			detailIDs = ids
+			_ = json.NewEncoder(w).Encode(RecycleBinResponse{
+				Count: 2,
+				Value: []DeletedWorkItem{
+					{ID: 5, Name: "Old bug", Type: "Bug", DeletedBy: "Jane"},
+					{ID: 6, Name: "Old task", Type: "Task", DeletedBy: "Jane"},
+				},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(RecycleBinResponse{Count: 2, Value: []DeletedWorkItem{{ID: 5}, {ID: 6}}})
+	})
+	defer server.Close()
+
+	items, err := client.ListRecycleBin()
+	if err != nil {
+		t.Fatalf("ListRecycleBin failed: %v", err)
+	}
+	if detailIDs != "5,6" {
+		t.Errorf("Expected details requested for 5,6, got %q", detailIDs)
+	}
+	if len(items) != 2 || items[0].Name != "Old bug" || items[1].Type != "Task" {
+		t.Errorf("Expected deleted item details, got %+v", items)
+	}
+}
+
+func TestListRecycleBinEmpty(t *testing.T) {
+	requests := 0
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"count":0,"value":[]}`))
+	})
+	defer server.Close()
+
+	items, err := client.ListRecycleBin()
+	if err != nil {
+		t.Fatalf("ListRecycleBin failed: %v", err)
+	}
+	if len(items) != 0 || requests != 1 {
+		t.Errorf("Expected no items and a single request, got %d items and %d requests", len(items), requests)
+	}
+}
+
+func TestListRecycleBinError(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	defer server.Close()
+
+	if _, err := client.ListRecycleBin(); err == nil {
+		t.Error("Expected error listing the recycle bin")
+	}
+}
+
+func TestUpdateAssigneeOnlyPatchesAssignee(t *testing.T) {
+	var ops []CreateWorkItemOp
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" {
+			t.Errorf("Expected PATCH, got %s", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/_apis/wit/workitems/42") {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&ops)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":42}`))
+	})
+	defer server.Close()
+
+	if _, err := client.UpdateAssignee(42, "me@example.com"); err != nil {
+		t.Fatalf("UpdateAssignee failed: %v", err)
+	}
+	if len(ops) != 1 {
+		t.Fatalf("Expected exactly one op, got %+v", ops)
+	}
+	if ops[0].Path != "/fields/System.AssignedTo" || ops[0].Value != "me@example.com" {
+		t.Errorf("Unexpected op %+v", ops[0])
+	}
+}
+
+func TestUpdateStateOnlyPatchesState(t *testing.T) {
+	var ops []CreateWorkItemOp
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&ops)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":42}`))
+	})
+	defer server.Close()
+
+	if _, err := client.UpdateState(42, "Resolved"); err != nil {
+		t.Fatalf("UpdateState failed: %v", err)
+	}
+	if len(ops) != 1 || ops[0].Path != "/fields/System.State" || ops[0].Value != "Resolved" {
+		t.Errorf("Expected a single state op, got %+v", ops)
+	}
+}
+
+func TestAddHistoryComment(t *testing.T) {
+	var ops []CreateWorkItemOp
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || !strings.HasSuffix(r.URL.Path, "/_apis/wit/workitems/42") {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&ops)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":42}`))
+	})
+	defer server.Close()
+
+	if err := client.AddHistoryComment(42, "<div>Deployed to staging</div>"); err != nil {
+		t.Fatalf("AddHistoryComment failed: %v", err)
+	}
+	if len(ops) != 1 || ops[0].Op != "add" || ops[0].Path != "/fields/System.History" || ops[0].Value != "<div>Deployed to staging</div>" {
+		t.Errorf("Expected a single System.History op, got %+v", ops)
+	}
+}
+
+func TestAddHistoryCommentEmpty(t *testing.T) {
+	client := NewClient("testorg", "testproject", "", "", "testpat")
+	if err := client.AddHistoryComment(42, "  "); err == nil {
+		t.Error("Expected error for an empty comment")
+	}
+}
+
+func TestUpdateTagsOnlyPatchesTags(t *testing.T) {
+	var ops []CreateWorkItemOp
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&ops)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":42}`))
+	})
+	defer server.Close()
+
+	if _, err := client.UpdateTags(42, "backend; release"); err != nil {
+		t.Fatalf("UpdateTags failed: %v", err)
+	}
+	if len(ops) != 1 || ops[0].Path != "/fields/System.Tags" || ops[0].Value != "backend; release" {
+		t.Errorf("Expected a single tags op, got %+v", ops)
+	}
+}
+
+func TestUpdateStateEmpty(t *testing.T) {
+	client := NewClient("testorg", "testproject", "", "", "testpat")
+	if _, err := client.UpdateState(42, " "); err == nil {
+		t.Error("Expected error for empty state")
+	}
+}
+
+func TestUpdateAssigneeError(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("unknown identity"))
+	})
+	defer server.Close()
+
+	_, err := client.UpdateAssignee(42, "nobody")
+	if err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("Expected 400 error, got %v", err)
+	}
+}
+
+func TestGetTags(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/_apis/wit/tags") {
+			t.Errorf("Expected tags URL, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(TagsResponse{
+			Count: 3,
+			Value: []WorkItemTag{{ID: "1", Name: "backend"}, {ID: "2", Name: ""}, {ID: "3", Name: "urgent"}},
+		})
+	})
+	defer server.Close()
+
+	tags, err := client.GetTags()
+	if err != nil {
+		t.Fatalf("GetTags failed: %v", err)
+	}
+	if len(tags) != 2 || tags[0] != "backend" || tags[1] != "urgent" {
+		t.Errorf("Expected [backend urgent], got %v", tags)
+	}
+}
+
+func TestGetTagsError(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	defer server.Close()
+
+	if _, err := client.GetTags(); err == nil {
+		t.Error("Expected error fetching tags")
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("gone"))
+	})
+	defer server.Close()
+
+	_, err := client.GetComments(1)
+	if !IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+	if err.Error() != "API error 404: gone" {
+		t.Errorf("Error() = %q, want the API error format", err.Error())
+	}
+	if IsNotFound(&APIError{StatusCode: http.StatusUnauthorized}) || IsNotFound(nil) {
+		t.Error("IsNotFound should only match 404 errors")
+	}
+	if !IsNotFound(fmt.Errorf("wrapped: %w", err)) {
+		t.Error("IsNotFound should see through wrapped errors")
+	}
+}
+
+func TestCheckExistingParent(t *testing.T) {
+	tests := []struct {
+		name          string
+		currentParent int
+		parentID      int
+		wantErr       string
+	}{
+		{"no parent yet", 0, 10, ""},
+		{"duplicate link", 10, 10, "#5 is already a child of #10"},
+		{"second parent", 7, 10, "#5 already has parent #7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkExistingParent(5, tt.currentParent, tt.parentID)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestIsAncestor(t *testing.T) {
+	// 4 -> 3 -> 2 -> 1 (child -> parent)
+	parents := map[int]int{4: 3, 3: 2, 2: 1}
+	parentOf := func(id int) (int, error) { return parents[id], nil }
+
+	tests := []struct {
+		ancestor, id int
+		want         bool
+	}{
+		{1, 4, true},
+		{3, 4, true},
+		{4, 4, true},
+		{4, 1, false},
+		{9, 4, false},
+	}
+	for _, tt := range tests {
+		got, err := isAncestor(tt.ancestor, tt.id, parentOf)
+		if err != nil {
+			t.Fatalf("isAncestor(%d, %d) failed: %v", tt.ancestor, tt.id, err)
+		}
+		if got != tt.want {
+			t.Errorf("isAncestor(%d, %d) = %v, want %v", tt.ancestor, tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestIsAncestorStopsOnLoops(t *testing.T) {
+	// A corrupt chain that loops back on itself must not hang
+	parents := map[int]int{1: 2, 2: 1}
+	got, err := isAncestor(9, 1, func(id int) (int, error) { return parents[id], nil })
+	if err != nil || got {
+		t.Errorf("isAncestor() = %v, %v; want false, nil", got, err)
+	}
+}
+
+func TestIsAncestorLookupError(t *testing.T) {
+	_, err := isAncestor(9, 1, func(int) (int, error) { return 0, fmt.Errorf("boom") })
+	if err == nil {
+		t.Error("Expected the lookup error to be returned")
+	}
+}
+
+func TestCheckHierarchyLink(t *testing.T) {
+	// 3 is the child of 2, which is the child of 1
+	parents := map[int]int{3: 2, 2: 1}
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("Expected only GET requests, got %s", r.Method)
+		}
+		var id int
+		_, _ = fmt.Sscanf(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], "%d", &id)
+		wi := WorkItem{ID: id}
+		if p := parents[id]; p != 0 {
+			wi.Relations = []WorkItemRelation{{
+				Rel: "System.LinkTypes.Hierarchy-Reverse",
+				URL: fmt.Sprintf("https://dev.azure.com/testorg/testproject/_apis/wit/workItems/%d", p),
+			}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(wi)
+	})
+	defer server.Close()
+
+	tests := []struct {
+		name             string
+		parentID, child  int
+		wantErrSubstring string
+	}{
+		{"new link", 3, 4, ""},
+		{"self link", 4, 4, "linked to itself"},
+		{"duplicate", 2, 3, "already a child of #2"},
+		{"second parent", 1, 3, "already has parent #2"},
+		{"cycle", 3, 1, "would create a cycle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.CheckHierarchyLink(tt.parentID, tt.child)
+			if tt.wantErrSubstring == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErrSubstring) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErrSubstring, err)
+			}
+		})
+	}
+}
+
+func TestCreateSavedQuery(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		if !strings.HasSuffix(r.URL.Path, "/_apis/wit/queries/My Queries") {
+			t.Errorf("Expected the My Queries folder, got %s", r.URL.Path)
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode body: %v", err)
+		}
+		if body["name"] != "Active bugs" || !strings.Contains(body["wiql"], "[System.Title]") {
+			t.Errorf("Unexpected body %v", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(SavedQuery{ID: "abc", Name: body["name"], Path: "My Queries/" + body["name"], Wiql: body["wiql"]})
+	})
+	defer server.Close()
+
+	wiql := client.SavedQueryWIQL(WorkItemFilter{WorkItemType: "Bug", ExcludeStates: []string{"Closed"}})
+	if !strings.HasPrefix(wiql, "SELECT "+savedQueryColumns+" FROM WorkItems") || !strings.Contains(wiql, "[System.WorkItemType] = 'Bug'") {
+		t.Errorf("Unexpected saved WIQL %q", wiql)
+	}
+
+	query, err := client.CreateSavedQuery("Active bugs", wiql)
+	if err != nil {
+		t.Fatalf("CreateSavedQuery failed: %v", err)
+	}
+	if query.ID != "abc" || query.Path != "My Queries/Active bugs" {
+		t.Errorf("Unexpected query %+v", query)
+	}
+}
+
+func TestCreateSavedQueryError(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte("A query with this name already exists"))
+	})
+	defer server.Close()
+
+	_, err := client.CreateSavedQuery("Active bugs", "SELECT [System.Id] FROM WorkItems")
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != http.StatusConflict {
+		t.Errorf("Expected a 409 APIError, got %v", err)
+	}
+}
+
+func TestCreateSavedQueryRequiresName(t *testing.T) {
+	client := NewClient("org", "proj", "", "", "pat")
+	if _, err := client.CreateSavedQuery("  ", "SELECT [System.Id] FROM WorkItems"); err == nil {
+		t.Error("Expected an error for a blank name")
+	}
+}
+
+func TestGetActivities(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/workitemtypes/Task/fields/Microsoft.VSTS.Common.Activity") || r.URL.Query().Get("$expand") != "allowedValues" {
+			t.Errorf("unexpected request %s", r.URL.String())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"referenceName": "Microsoft.VSTS.Common.Activity", "name": "Activity", "allowedValues": ["Deployment", "Design", "Development", "Documentation", "Requirements", "Testing"]}`))
+	})
+	defer server.Close()
+
+	activities, err := client.GetActivities("Task")
+	if err != nil {
+		t.Fatalf("GetActivities failed: %v", err)
+	}
+	if len(activities) != 6 || activities[2] != "Development" {
+		t.Errorf("activities = %v, want the six allowed values in order", activities)
+	}
+}
+
+func TestGetActivitiesError(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	if _, err := client.GetActivities("Bug"); !IsNotFound(err) {
+		t.Errorf("err = %v, want a not found API error", err)
+	}
+}
+
+func TestUpdateActivity(t *testing.T) {
+	var ops []CreateWorkItemOp
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" {
+			t.Errorf("Expected PATCH, got %s", r.Method)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&ops)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":42,"fields":{"Microsoft.VSTS.Common.Activity":"Testing"}}`))
+	})
+	defer server.Close()
+
+	wi, err := client.UpdateActivity(42, "Testing")
+	if err != nil {
+		t.Fatalf("UpdateActivity failed: %v", err)
+	}
+	if len(ops) != 1 || ops[0].Op != "add" || ops[0].Path != "/fields/Microsoft.VSTS.Common.Activity" || ops[0].Value != "Testing" {
+		t.Errorf("Expected a single activity op, got %+v", ops)
+	}
+	if wi.Fields.Activity != "Testing" {
+		t.Errorf("Activity = %q, want Testing", wi.Fields.Activity)
+	}
+}
+
+func TestGetFieldAllowedValues(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/workitemtypes/User Story/fields/Custom.Risk") || r.URL.Query().Get("$expand") != "allowedValues" {
+			t.Errorf("unexpected request %s", r.URL.String())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"referenceName": "Custom.Risk",
+			"name": "Risk",
+			"alwaysRequired": false,
+			"allowedValues": ["1 - High", "2 - Medium", "3 - Low"],
+			"defaultValue": "2 - Medium"
+		}`))
+	})
+	defer server.Close()
+
+	values, err := client.GetFieldAllowedValues("User Story", "Custom.Risk")
+	if err != nil {
+		t.Fatalf("GetFieldAllowedValues failed: %v", err)
+	}
+	if len(values) != 3 || values[0] != "1 - High" || values[2] != "3 - Low" {
+		t.Errorf("values = %v, want the three risk levels in order", values)
+	}
+}
+
+func TestGetFieldAllowedValuesFreeText(t *testing.T) {
+	client, server := testClientWithMockTransport(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"referenceName": "System.Title", "name": "Title", "allowedValues": []}`))
+	})
+	defer server.Close()
+
+	values, err := client.GetFieldAllowedValues("Bug", "System.Title")
+	if err != nil {
+		t.Fatalf("GetFieldAllowedValues failed: %v", err)
+	}
+	if len(values) != 0 {
+		t.Errorf("values = %v, want none for a free-text field", values)
+	}
+}
@@ -3,18 +3,83 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/laupski/bored/tui"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// options holds the command-line options
+type options struct {
+	noAltScreen bool // keep output in the terminal's scrollback instead of using the alternate screen
+	workItemID  int  // work item to open after connecting (0 shows the board)
+}
+
+// parseFlags parses the command-line arguments (without the program name)
+func parseFlags(args []string, output io.Writer) (options, error) {
+	var opts options
+	fs := flag.NewFlagSet("bored", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.BoolVar(&opts.noAltScreen, "no-altscreen", false, "run without the alternate screen so output stays in terminal history")
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(fs.Output(), "Usage: bored [flags] [work-item-id]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return options{}, err
+	}
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		id, err := strconv.Atoi(strings.TrimPrefix(fs.Arg(0), "#"))
+		if err != nil || id <= 0 {
+			err = fmt.Errorf("invalid work item ID %q", fs.Arg(0))
+			_, _ = fmt.Fprintln(output, err)
+			return options{}, err
+		}
+		opts.workItemID = id
+	default:
+		err := fmt.Errorf("expected at most one work item ID, got %d arguments", fs.NArg())
+		_, _ = fmt.Fprintln(output, err)
+		return options{}, err
+	}
+	return opts, nil
+}
+
+// programOptions returns the Bubble Tea options for the parsed command-line options
+func programOptions(opts options) []tea.ProgramOption {
+	var programOpts []tea.ProgramOption
+	if !opts.noAltScreen {
+		programOpts = append(programOpts, tea.WithAltScreen())
+	}
+	return programOpts
+}
+
 func main() {
-	p := tea.NewProgram(tui.NewModel(), tea.WithAltScreen())
+	opts, err := parseFlags(os.Args[1:], os.Stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	}
+
+	closeDebugLog := tui.OpenDebugLog()
+	defer closeDebugLog()
+
+	model := tui.NewModel()
+	model.OpenOnConnect(opts.workItemID)
+	p := tea.NewProgram(model, programOptions(opts)...)
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error: %v", err)
+		closeDebugLog() // os.Exit skips deferred calls
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"io"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		noAltScreen bool
+		workItemID  int
+		wantErr     bool
+	}{
+		{name: "defaults", args: nil},
+		{name: "no-altscreen", args: []string{"--no-altscreen"}, noAltScreen: true},
+		{name: "single dash", args: []string{"-no-altscreen"}, noAltScreen: true},
+		{name: "unknown flag", args: []string{"--bogus"}, wantErr: true},
+		{name: "work item ID", args: []string{"12345"}, workItemID: 12345},
+		{name: "hash work item ID", args: []string{"#12345"}, workItemID: 12345},
+		{name: "flag and ID", args: []string{"--no-altscreen", "7"}, noAltScreen: true, workItemID: 7},
+		{name: "invalid ID", args: []string{"abc"}, wantErr: true},
+		{name: "zero ID", args: []string{"0"}, wantErr: true},
+		{name: "too many IDs", args: []string{"1", "2"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := parseFlags(tt.args, io.Discard)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if opts.noAltScreen != tt.noAltScreen {
+				t.Errorf("noAltScreen = %v, want %v", opts.noAltScreen, tt.noAltScreen)
+			}
+			if opts.workItemID != tt.workItemID {
+				t.Errorf("workItemID = %d, want %d", opts.workItemID, tt.workItemID)
+			}
+		})
+	}
+}
+
+func TestProgramOptions(t *testing.T) {
+	if got := len(programOptions(options{})); got != 1 {
+		t.Errorf("default options = %d program options, want 1 (alt screen)", got)
+	}
+	if got := len(programOptions(options{noAltScreen: true})); got != 0 {
+		t.Errorf("--no-altscreen = %d program options, want none", got)
+	}
+}
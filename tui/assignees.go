@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// assigneeGroup is one section of the group-by-assignee view
+type assigneeGroup struct {
+	label string // assignee name, or "(unassigned)"
+	items []azdo.WorkItem
+}
+
+// groupByAssignee partitions items by assignee, labelled like the board's Assigned column.
+// Groups are ordered by name with unassigned items last, and items keep their order
+// within a group. Identities are matched by unique name, so two people sharing a
+// display name stay apart.
+func groupByAssignee(items []azdo.WorkItem, unique bool) []assigneeGroup {
+	var groups []assigneeGroup
+	var unassigned assigneeGroup
+	index := make(map[string]int)
+	for _, wi := range items {
+		a := wi.Fields.AssignedTo
+		if a == nil || a.UniqueName == "" && a.DisplayName == "" {
+			unassigned.items = append(unassigned.items, wi)
+			continue
+		}
+		key := strings.ToLower(a.UniqueName)
+		if key == "" {
+			key = a.DisplayName
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, assigneeGroup{label: assigneeLabel(wi, unique)})
+		}
+		groups[i].items = append(groups[i].items, wi)
+	}
+	slices.SortStableFunc(groups, func(a, b assigneeGroup) int {
+		return strings.Compare(strings.ToLower(a.label), strings.ToLower(b.label))
+	})
+	if len(unassigned.items) > 0 {
+		unassigned.label = "(unassigned)"
+		groups = append(groups, unassigned)
+	}
+	return groups
+}
+
+// assigneeItems returns the loaded work items in the order the assignee view lists them
+func (m Model) assigneeItems() []azdo.WorkItem {
+	var items []azdo.WorkItem
+	for _, group := range groupByAssignee(m.workItems, m.showUniqueNames) {
+		items = append(items, group.items...)
+	}
+	return items
+}
+
+func (m Model) updateAssignees(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	items := m.assigneeItems()
+	switch keyMsg.String() {
+	case "esc", "q", "w":
+		m.view = ViewBoard
+		return m, nil
+	case "up", "k":
+		if m.assigneeCursor > 0 {
+			m.assigneeCursor--
+		}
+	case "down", "j":
+		if m.assigneeCursor < len(items)-1 {
+			m.assigneeCursor++
+		}
+	case "e", "enter":
+		if m.assigneeCursor >= len(items) {
+			return m, nil
+		}
+		wi := items[m.assigneeCursor]
+		m.view = ViewDetail
+		newModel, cmd := m.navigateToWorkItem(&wi)
+		updated := newModel.(Model)
+		return updated, tea.Batch(cmd, updated.updateDetailFocus())
+	}
+	return m, nil
+}
+
+func (m Model) viewAssignees() string {
+	var b strings.Builder
+
+	header := titleStyle.Render(fmt.Sprintf("👥 By Assignee - %s", m.connectionLabel()))
+	b.WriteString(header)
+	b.WriteString("\n\n")
+
+	groups := groupByAssignee(m.workItems, m.showUniqueNames)
+	if len(groups) == 0 {
+		b.WriteString("No work items found.")
+		b.WriteString("\n")
+	}
+
+	// Section headers and items as lines, remembering which line holds the cursor
+	groupHeaderStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Bold(true)
+	var lines []string
+	cursorLine, i := 0, 0
+	for _, group := range groups {
+		lines = append(lines, groupHeaderStyle.Render(fmt.Sprintf("%s (%d)", group.label, len(group.items))))
+		for _, wi := range group.items {
+			line := fmt.Sprintf("  #%d [%s] %s (%s)", wi.ID, wi.Fields.WorkItemType, wi.Fields.Title, wi.Fields.State)
+			if m.width > 0 {
+				line = truncateWidth(line, m.width-normalStyle.GetHorizontalFrameSize()-2)
+			}
+			if i == m.assigneeCursor {
+				cursorLine = len(lines)
+				line = selectedStyle.Render(line)
+			} else {
+				line = normalStyle.Render(line)
+			}
+			lines = append(lines, line)
+			i++
+		}
+	}
+
+	// Keep the cursor in view on short terminals
+	start, end := 0, len(lines)
+	if maxRows := m.height - 8; m.height > 0 && maxRows > 0 && len(lines) > maxRows {
+		start = max(cursorLine-maxRows/2, 0)
+		if start+maxRows > len(lines) {
+			start = len(lines) - maxRows
+		}
+		end = start + maxRows
+	}
+	for _, line := range lines[start:end] {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpStyle.Render("↑/k ↓/j: navigate • enter: open • esc/w: board"))
+
+	return b.String()
+}
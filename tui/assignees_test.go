@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func assignedItem(id int, displayName, uniqueName string) azdo.WorkItem {
+	wi := azdo.WorkItem{ID: id}
+	if displayName != "" || uniqueName != "" {
+		wi.Fields.AssignedTo = &azdo.IdentityRef{DisplayName: displayName, UniqueName: uniqueName}
+	}
+	return wi
+}
+
+func TestGroupByAssignee(t *testing.T) {
+	items := []azdo.WorkItem{
+		assignedItem(1, "Zoe Park", "zoe@example.com"),
+		assignedItem(2, "", ""),
+		assignedItem(3, "alex Kim", "alex@example.com"),
+		assignedItem(4, "Zoe Park", "ZOE@example.com"), // same identity, different case
+		assignedItem(5, "Zoe Park", "zoe.park@example.com"),
+		{ID: 6, Fields: azdo.WorkItemFields{AssignedTo: &azdo.IdentityRef{}}},
+	}
+
+	groups := groupByAssignee(items, false)
+	type bucket struct {
+		label string
+		ids   []int
+	}
+	var got []bucket
+	for _, g := range groups {
+		got = append(got, bucket{g.label, itemIDs(g.items)})
+	}
+	want := []bucket{
+		{"alex Kim", []int{3}},
+		{"Zoe Park", []int{1, 4}},
+		{"Zoe Park", []int{5}}, // a different person with the same display name
+		{"(unassigned)", []int{2, 6}},
+	}
+	if !slices.EqualFunc(got, want, func(a, b bucket) bool { return a.label == b.label && slices.Equal(a.ids, b.ids) }) {
+		t.Errorf("groupByAssignee() = %v, want %v", got, want)
+	}
+
+	// Unique names label and order the groups when shown
+	groups = groupByAssignee(items, true)
+	if groups[0].label != "alex@example.com" || groups[1].label != "zoe.park@example.com" {
+		t.Errorf("groups = %+v, want unique name labels in order", groups)
+	}
+
+	if groups := groupByAssignee(items[:1], false); len(groups) != 1 || groups[0].label != "Zoe Park" {
+		t.Errorf("groupByAssignee() = %+v, want no unassigned group without unassigned items", groups)
+	}
+}
+
+func TestBoardAssigneeView(t *testing.T) {
+	m := setupBoardModel()
+	m.workItems[0].Fields.AssignedTo = &azdo.IdentityRef{DisplayName: "Zoe Park", UniqueName: "zoe@example.com"}
+	m.workItems[1].Fields.AssignedTo = nil
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	m = newModel.(Model)
+	if m.view != ViewAssignees {
+		t.Fatalf("w should open the assignee view, got view %v", m.view)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Zoe Park (1)") || !strings.Contains(view, "(unassigned) (1)") {
+		t.Errorf("assignee view should head each group with its count, got %q", view)
+	}
+	if strings.Index(view, "First Item") > strings.Index(view, "(unassigned)") {
+		t.Error("unassigned items should come last")
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = newModel.(Model)
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.view != ViewDetail || m.selectedItem == nil || m.selectedItem.ID != 2 || cmd == nil {
+		t.Error("enter should open the selected item")
+	}
+}
@@ -4,19 +4,52 @@ import (
 	"fmt"
 	"os/exec"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/laupski/bored/azdo"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// boardSort is a board ordering, applied by the server query so paging stays consistent
+type boardSort struct {
+	label string
+	sort  azdo.WorkItemSort
+}
+
+// boardSorts are the orderings the board cycles through with s; the first is the default
+var boardSorts = []boardSort{
+	{label: "last changed", sort: azdo.WorkItemSort{Field: "System.ChangedDate"}},
+	{label: "newest", sort: azdo.WorkItemSort{Field: "System.CreatedDate"}},
+	{label: "priority", sort: azdo.WorkItemSort{Field: "Microsoft.VSTS.Common.Priority", Ascending: true}},
+	{label: "ID", sort: azdo.WorkItemSort{Field: "System.Id", Ascending: true}},
+	{label: "title", sort: azdo.WorkItemSort{Field: "System.Title", Ascending: true}},
+}
+
 func (m Model) updateBoard(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		// Clear notification message on any key press
 		m.notifyMessage = ""
 
+		// Handle quick delete confirmation mode (y/n instead of typing the title)
+		if m.deletingWorkItem && m.appConfig.QuickDelete {
+			switch msg.String() {
+			case "y", "Y":
+				m.loading = true
+				m.deletingWorkItem = false
+				return m, m.deleteWorkItem(m.deleteWorkItemID)
+			case "n", "N", "esc":
+				m.deletingWorkItem = false
+				return m, nil
+			}
+			return m, nil
+		}
+
 		// Handle delete confirmation mode
 		if m.deletingWorkItem {
 			switch msg.String() {
@@ -52,7 +85,253 @@ func (m Model) updateBoard(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Handle jump to ID mode
+		if m.jumpingToID {
+			switch msg.String() {
+			case "esc":
+				m.jumpingToID = false
+				m.jumpIDInput = ""
+				return m, nil
+			case "enter":
+				id, err := strconv.Atoi(m.jumpIDInput)
+				m.jumpingToID = false
+				m.jumpIDInput = ""
+				if err != nil || id <= 0 {
+					m.err = fmt.Errorf("invalid work item ID")
+					return m, nil
+				}
+				m.err = nil
+				m.loading = true
+				return m, m.jumpToWorkItem(id)
+			case "backspace":
+				if len(m.jumpIDInput) > 0 {
+					m.jumpIDInput = m.jumpIDInput[:len(m.jumpIDInput)-1]
+				}
+				return m, nil
+			default:
+				// Only accept digits for the work item ID
+				if key := msg.String(); len(key) == 1 && key[0] >= '0' && key[0] <= '9' {
+					m.jumpIDInput += key
+				}
+				return m, nil
+			}
+		}
+
+		// Only browsing is possible while showing the offline cache
+		if m.offline && !offlineBoardKeys[msg.String()] {
+			m.message = offlineReadOnlyMessage
+			return m, nil
+		}
+
+		// Handle bulk tag prompt
+		if m.bulkTagging {
+			return m.updateBulkTagPrompt(msg)
+		}
+
+		// Handle search prompt
+		if m.searching {
+			return m.updateSearchPrompt(msg)
+		}
+
+		// Handle fuzzy filter prompt
+		if m.fuzzyFiltering {
+			return m.updateFuzzyPrompt(msg)
+		}
+
+		// Handle CSV import prompt
+		if m.importing {
+			return m.updateImportPrompt(msg)
+		}
+
+		// Handle save query prompt
+		if m.savingQuery {
+			return m.updateSaveQueryPrompt(msg)
+		}
+
+		// Handle create child form
+		if m.creatingRelated {
+			return m.updateCreateRelated(msg)
+		}
+
+		// Handle iteration filter picker
+		if m.pickingIterationFilter {
+			options := m.iterationFilterOptions()
+			switch msg.String() {
+			case "esc", "i":
+				m.pickingIterationFilter = false
+				return m, nil
+			case "up", "k":
+				if m.iterationFilterCursor > 0 {
+					m.iterationFilterCursor--
+				}
+				return m, nil
+			case "down", "j":
+				if m.iterationFilterCursor < len(options) {
+					m.iterationFilterCursor++
+				}
+				return m, nil
+			case "enter":
+				// Option 0 clears the filter; the rest map to iterations
+				m.pickingIterationFilter = false
+				m.iterationFilter = ""
+				m.currentIteration = false
+				if m.iterationFilterCursor > 0 && m.iterationFilterCursor <= len(options) {
+					m.iterationFilter = options[m.iterationFilterCursor-1].Path
+				}
+				m.loading = true
+				m.cursor = 0
+				return m, m.fetchWorkItems()
+			}
+			return m, nil
+		}
+
 		switch msg.String() {
+		case " ":
+			// Select or deselect the current item for bulk actions
+			if len(m.workItems) > 0 && m.cursor < len(m.workItems) {
+				m = m.toggleSelected(m.workItems[m.cursor].ID)
+			}
+			return m, nil
+		case "esc":
+			// Clear the bulk selection, or the fuzzy filter or search once nothing is selected
+			if len(m.selectedIDs) == 0 && m.fuzzyAll != nil {
+				return m.clearFuzzy(), nil
+			}
+			if len(m.selectedIDs) == 0 && m.searchText != "" {
+				return m.applySearch("")
+			}
+			m.selectedIDs = nil
+			return m, nil
+		case "/":
+			// Search the board by ID, title or tags
+			m.searching = true
+			m.searchInput = m.searchText
+			m.err = nil
+			m.message = ""
+			return m, nil
+		case "f":
+			// Narrow the loaded items as you type, without querying the server
+			m.fuzzyFiltering = true
+			m.err = nil
+			m.message = ""
+			return m.setFuzzyQuery(m.fuzzyQuery), nil
+		case "T":
+			// Add a tag to every selected item
+			if len(m.selectedIDs) == 0 {
+				m.message = "Select items with space first"
+				return m, nil
+			}
+			m.bulkTagging = true
+			m.bulkTagInput = ""
+			m.err = nil
+			m.message = ""
+			return m, nil
+		case "+":
+			// Create a child of the selected item without opening it
+			if len(m.workItems) == 0 || m.cursor >= len(m.workItems) {
+				return m, nil
+			}
+			m = m.startCreateRelated(m.workItems[m.cursor].ID, true)
+			// Breaking work down usually means tasks
+			for i, t := range m.workItemTypes {
+				if t == "Task" {
+					m.createRelatedType = i
+					break
+				}
+			}
+			m.err = nil
+			m.message = ""
+			return m, nil
+		case "U":
+			// Create work items from a CSV file
+			m.importing = true
+			m.importPathInput = ""
+			m.err = nil
+			m.message = ""
+			return m, nil
+		case "Q":
+			// Save the board's current query to My Queries
+			m.savingQuery = true
+			m.saveQueryInput = ""
+			m.err = nil
+			m.message = ""
+			return m, nil
+		case "[":
+			// Narrow the title column
+			return m.resizeTitle(-titleWidthStep)
+		case "]":
+			// Widen the title column
+			return m.resizeTitle(titleWidthStep)
+		case "i":
+			// Filter the board to an iteration
+			m.pickingIterationFilter = true
+			m.iterationFilterCursor = 0
+			for i, iter := range m.iterationFilterOptions() {
+				if iter.Path == m.iterationFilter {
+					m.iterationFilterCursor = i + 1
+					break
+				}
+			}
+			if len(m.iterations) == 0 {
+				return m, m.fetchIterations()
+			}
+			return m, nil
+		case "C":
+			// Follow the team's current iteration, whichever sprint that is
+			m.currentIteration = !m.currentIteration
+			m.iterationFilter = ""
+			m.loading = true
+			m.cursor = 0
+			return m, m.fetchWorkItems()
+		case "I":
+			// Clear the iteration filter
+			if m.iterationFilter == "" && !m.currentIteration {
+				return m, nil
+			}
+			m.iterationFilter = ""
+			m.currentIteration = false
+			m.loading = true
+			m.cursor = 0
+			return m, m.fetchWorkItems()
+		case "s":
+			// Cycle the board order
+			m.sortMode = (m.sortMode + 1) % len(boardSorts)
+			m.loading = true
+			m.cursor = 0
+			return m, m.fetchWorkItems()
+		case "z":
+			// Undo the last delete or unlink
+			return m.undoLast()
+		case "b":
+			// Open the recycle bin
+			m.view = ViewRecycleBin
+			m.recycleBin = nil
+			m.recycleBinCursor = 0
+			m.loading = true
+			m.err = nil
+			m.message = ""
+			return m, m.fetchRecycleBin()
+		case "t":
+			// Show the loaded items as a parent/child outline
+			m.view = ViewOutline
+			m.outlineCursor = 0
+			m.err = nil
+			m.message = ""
+			return m, nil
+		case "w":
+			// Show the loaded items grouped by who they're assigned to
+			m.view = ViewAssignees
+			m.assigneeCursor = 0
+			m.err = nil
+			m.message = ""
+			return m, nil
+		case "#":
+			// Open a work item by ID, even if it's not in the current list
+			m.jumpingToID = true
+			m.jumpIDInput = ""
+			m.err = nil
+			m.message = ""
+			return m, nil
 		case "up", "k":
 			if m.cursor > 0 {
 				m.cursor--
@@ -63,14 +342,22 @@ func (m Model) updateBoard(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.cursor++
 			}
 			return m, nil
-		case "left", "h", "pgup":
+		case "pgup":
+			// Move up a screenful within the loaded items
+			m.cursor = max(m.cursor-m.boardPageSize(), 0)
+			return m, nil
+		case "pgdown":
+			// Move down a screenful within the loaded items
+			m.cursor = max(min(m.cursor+m.boardPageSize(), len(m.workItems)-1), 0)
+			return m, nil
+		case "left", "h":
 			// Previous page - fetch from API
 			if m.apiPage > 0 {
 				m.loading = true
 				return m, m.fetchWorkItemsPage(m.apiPage - 1)
 			}
 			return m, nil
-		case "right", "l", "pgdown":
+		case "right", "l":
 			// Next page - fetch from API
 			if m.hasMoreData {
 				m.loading = true
@@ -90,25 +377,107 @@ func (m Model) updateBoard(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.cursor = len(m.workItems) - 1
 			}
 			return m, nil
+		case "g":
+			// Go to the first item of all results
+			if m.apiPage > 0 {
+				m.loading = true
+				return m, m.fetchWorkItemsPage(0)
+			}
+			m.cursor = 0
+			return m, nil
+		case "G":
+			// Go to the last item of all results, fetching the final page if needed
+			if m.hasMoreData {
+				m.loading = true
+				return m, m.fetchLastWorkItemsPage()
+			}
+			if len(m.workItems) > 0 {
+				m.cursor = len(m.workItems) - 1
+			}
+			return m, nil
 		case "r":
 			m.loading = true
 			m.err = nil
+			// Look parents up again, including ones that couldn't be found before
+			m.parentTitles = nil
 			return m, m.fetchWorkItems()
+		case "R":
+			// Reconnect after a dropped session; success refetches the board
+			m.loading = true
+			m.err = nil
+			return m, m.connect()
 		case "a":
 			// Toggle show all / my items filter
 			m.showAll = !m.showAll
 			m.loading = true
 			m.cursor = 0
 			return m, m.fetchWorkItems()
+		case "M":
+			// Toggle "my queue" mode, which always shows the current user's most recently changed items
+			if m.username == "" {
+				return m, nil
+			}
+			m.myQueue = !m.myQueue
+			m.loading = true
+			m.cursor = 0
+			return m, m.fetchWorkItems()
+		case "m":
+			// Assign the selected work item to yourself
+			if m.username == "" || len(m.workItems) == 0 || m.cursor >= len(m.workItems) {
+				return m, nil
+			}
+			m.loading = true
+			m.err = nil
+			wi := m.workItems[m.cursor]
+			return m.confirmWrite(fmt.Sprintf("Assign #%d to yourself?", wi.ID), m.assignToMe(wi))
+		case "A":
+			// Assign the selected work item to the last assignee entered
+			if len(m.workItems) == 0 || m.cursor >= len(m.workItems) {
+				return m, nil
+			}
+			if m.lastAssignee == "" {
+				m.message = "No assignee entered yet"
+				return m, nil
+			}
+			m.loading = true
+			m.err = nil
+			wi := m.workItems[m.cursor]
+			return m.confirmWrite(fmt.Sprintf("Assign #%d to %s?", wi.ID, m.lastAssignee), m.assignTo(wi, m.lastAssignee))
+		case "S":
+			// Toggle notification sound mute
+			m.soundMuted = !m.soundMuted
+			if m.soundMuted {
+				m.message = "Notification sound muted"
+			} else {
+				m.message = "Notification sound unmuted"
+			}
+			return m, nil
+		case "u":
+			// Toggle showing assignees by unique name or display name
+			m.showUniqueNames = !m.showUniqueNames
+			if m.showUniqueNames {
+				m.message = "Showing assignee unique names"
+			} else {
+				m.message = "Showing assignee display names"
+			}
+			return m, nil
+		case "x":
+			// Toggle hiding completed items
+			m.hideCompleted = !m.hideCompleted
+			m.loading = true
+			m.cursor = 0
+			return m, m.fetchWorkItems()
 		case "o":
 			// Open selected work item in browser
 			if len(m.workItems) > 0 && m.cursor < len(m.workItems) {
 				wi := m.workItems[m.cursor]
-				url := fmt.Sprintf("https://dev.azure.com/%s/%s/_workitems/edit/%d",
-					m.client.Organization, m.client.Project, wi.ID)
-				_ = openBrowser(url)
+				_ = openBrowser(m.client.WorkItemWebURL(wi.ID))
 			}
 			return m, nil
+		case "O":
+			// Open the team's board in the browser at the selected item's backlog level
+			_ = openBrowser(m.backlogURL())
+			return m, nil
 		case "e", "enter":
 			// Open detail/edit view
 			if len(m.workItems) > 0 && m.cursor < len(m.workItems) {
@@ -125,9 +494,14 @@ func (m Model) updateBoard(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.detailInputs[2].SetValue(assignedTo)
 				m.detailInputs[3].SetValue(wi.Fields.Tags)
 				m.detailInputs[4].SetValue("")
-				m.detailFocus = 0
-				m.detailInputs[0].Focus()
+				m.detailFocus = m.firstEditableField()
+				m.detailScroll = 0
+				focusCmd := m.updateDetailFocus()
 				m.comments = nil
+				m.commentsToken = ""
+				m.stateReasons = nil
+				m.pendingReason = ""
+				m.loadingMoreComments = false
 				m.parentItem = nil
 				m.childItems = nil
 				m.relatedExpanded = false
@@ -136,12 +510,18 @@ func (m Model) updateBoard(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.commentScroll = 0
 				m.iterationExpanded = false
 				m.iterationCursor = 0
+				m.areaExpanded = false
+				m.areaCursor = 0
+				m.tagEditing = false
 				m.hyperlinks = nil
 				m.hyperlinksExpanded = false
 				m.hyperlinkCursor = 0
 				m.err = nil
 				m.message = ""
-				return m, tea.Batch(m.fetchComments(wi.ID), m.fetchRelatedItems(wi.ID), m.fetchHyperlinks(wi.ID))
+				if m.offline {
+					return m.loadCachedDetail(wi.ID), nil
+				}
+				return m, tea.Batch(focusCmd, m.fetchComments(wi.ID), m.fetchRelatedItems(wi.ID), m.fetchHyperlinks(wi.ID), m.fetchReadOnlyFields(&wi))
 			}
 			return m, nil
 		case "c", "n":
@@ -179,67 +559,94 @@ func (m Model) viewBoard() string {
 
 	filterStatus := ""
 	if m.username != "" {
-		if m.showAll {
+		if m.myQueue {
+			filterStatus = fmt.Sprintf(" (my queue: %s)", m.username)
+		} else if m.showAll {
 			filterStatus = " (showing all)"
 		} else {
 			filterStatus = fmt.Sprintf(" (filtered: %s)", m.username)
 		}
 	}
-	header := titleStyle.Render(fmt.Sprintf("📋 Work Items - %s/%s%s", m.client.Organization, m.client.Project, filterStatus))
+	// My queue ignores the other query filters until it's turned off
+	if !m.inMyQueue() {
+		if m.hideCompleted {
+			filterStatus += " (hiding done)"
+		}
+		if m.iterationFilter != "" {
+			filterStatus += fmt.Sprintf(" (iteration: %s)", lastPathSegment(m.iterationFilter))
+		} else if m.currentIteration {
+			filterStatus += " (iteration: current)"
+		}
+		if m.searchText != "" {
+			filterStatus += fmt.Sprintf(" (search: %q)", m.searchText)
+		}
+	}
+	if m.fuzzyQuery != "" {
+		filterStatus += fmt.Sprintf(" (fuzzy: %q)", m.fuzzyQuery)
+	}
+	if m.sortMode != 0 && !m.inMyQueue() {
+		filterStatus += fmt.Sprintf(" (sorted by %s)", boardSorts[m.sortMode].label)
+	}
+	if len(m.selectedIDs) > 0 {
+		filterStatus += fmt.Sprintf(" (%d selected)", len(m.selectedIDs))
+	}
+	header := titleStyle.Render(fmt.Sprintf("📋 Work Items - %s%s", m.connectionLabel(), filterStatus))
 	b.WriteString(header)
 	b.WriteString("\n\n")
+	if m.offline {
+		b.WriteString(m.offlineBanner())
+		b.WriteString("\n\n")
+	}
 
-	if m.loading {
+	if m.loading && !m.bulkRunning() {
+		// A bulk action keeps the board on screen and reports progress below it
 		b.WriteString("Loading work items...")
 		b.WriteString("\n")
 	} else if len(m.workItems) == 0 && m.err == nil {
 		b.WriteString("No work items found.")
 		b.WriteString("\n")
-	} else {
-		// Column definitions: ID, Type, Title, Assigned To, State, Area Path, Tags, Comments, Related, Activity Date
-		colID := lipgloss.NewStyle().Width(10).Align(lipgloss.Left).MarginRight(2)
-		colType := lipgloss.NewStyle().Width(12).Align(lipgloss.Left)
-		colTitle := lipgloss.NewStyle().Width(35).Align(lipgloss.Left)
-		colAssigned := lipgloss.NewStyle().Width(25).Align(lipgloss.Left)
-		colState := lipgloss.NewStyle().Width(12).Align(lipgloss.Left)
-		colArea := lipgloss.NewStyle().Width(18).Align(lipgloss.Left)
-		colTags := lipgloss.NewStyle().Width(15).Align(lipgloss.Left)
-		colComments := lipgloss.NewStyle().Width(4).Align(lipgloss.Left)
-		colRelated := lipgloss.NewStyle().Width(4).Align(lipgloss.Left)
-		colActivity := lipgloss.NewStyle().Width(14).Align(lipgloss.Left)
+	} else if m.isCompact() {
+		// Narrow terminal: one truncated line per item
+		rowWidth := m.width - normalStyle.GetHorizontalFrameSize()
+		b.WriteString(strings.Repeat("─", m.width))
+		b.WriteString("\n")
 
+		start, end := m.visibleRange()
+		for i := start; i < end; i++ {
+			wi := m.workItems[i]
+			if isRemovedState(wi.Fields.State) {
+				wi.Fields.Title = removedMarker + wi.Fields.Title
+			}
+			if wi.IsBlocked(m.appConfig.BlockedLinkType) {
+				wi.Fields.Title = blockedMarker + wi.Fields.Title
+			}
+			row := renderRowCompact(wi, rowWidth)
+			if i == m.cursor {
+				b.WriteString(selectedStyle.Render(row))
+			} else if isRemovedState(wi.Fields.State) {
+				b.WriteString(removedStyle.Render(row))
+			} else {
+				b.WriteString(normalStyle.Render(row))
+			}
+			b.WriteString("\n")
+		}
+	} else {
 		headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-		headerRow := lipgloss.JoinHorizontal(
-			lipgloss.Top,
-			colID.Inherit(headerStyle).Render("ID"),
-			colType.Inherit(headerStyle).Render("Type"),
-			colTitle.Inherit(headerStyle).Render("Title"),
-			colAssigned.Inherit(headerStyle).Render("Assigned To"),
-			colState.Inherit(headerStyle).Render("State"),
-			colArea.Inherit(headerStyle).Render("Area Path"),
-			colTags.Inherit(headerStyle).Render("Tags"),
-			colComments.Inherit(headerStyle).Render("💬"),
-			colRelated.Inherit(headerStyle).Render("🔗"),
-			colActivity.Inherit(headerStyle).Render("Activity"),
-		)
+		columns := m.boardColumns()
+		headers := make([]string, len(columns))
+		tableWidth := 0
+		for i, col := range columns {
+			headers[i] = col.header
+			tableWidth += col.width
+		}
+		headerRow := headerStyle.Render(renderBoardRow(columns, headers))
 		b.WriteString(headerRow)
 		b.WriteString("\n")
-		b.WriteString(strings.Repeat("─", 152))
+		b.WriteString(strings.Repeat("─", tableWidth))
 		b.WriteString("\n")
 
 		// Calculate pagination
-		maxVisible := m.height - 12
-		if m.height == 0 || maxVisible < 1 {
-			maxVisible = 10 // Height not yet initialized
-		}
-		pageSize := maxVisible
-		currentPage := m.cursor / pageSize
-
-		start := currentPage * pageSize
-		end := start + pageSize
-		if end > len(m.workItems) {
-			end = len(m.workItems)
-		}
+		start, end := m.visibleRange()
 
 		// Show page indicator (API page, not local page)
 		pageInfo := fmt.Sprintf("Page %d", m.apiPage+1)
@@ -256,75 +663,55 @@ func (m Model) viewBoard() string {
 			wi := m.workItems[i]
 
 			id := fmt.Sprintf("#%d", wi.ID)
-
-			wiType := wi.Fields.WorkItemType
-			if len(wiType) > 11 {
-				wiType = wiType[:8] + "..."
+			removed := isRemovedState(wi.Fields.State)
+			if removed {
+				id = removedMarker + id
 			}
-
-			title := wi.Fields.Title
-			if len(title) > 34 {
-				title = title[:31] + "..."
+			if wi.IsBlocked(m.appConfig.BlockedLinkType) {
+				id = blockedMarker + id
 			}
-
-			assignedTo := ""
-			if wi.Fields.AssignedTo != nil {
-				assignedTo = wi.Fields.AssignedTo.DisplayName
-				if len(assignedTo) > 24 {
-					assignedTo = assignedTo[:21] + "..."
-				}
+			if m.selectedIDs[wi.ID] {
+				id = "● " + id
 			}
 
-			state := wi.Fields.State
-
 			areaPath := wi.Fields.AreaPath
 			// Show only the last part of area path
 			if idx := strings.LastIndex(areaPath, "\\"); idx >= 0 {
 				areaPath = areaPath[idx+1:]
 			}
-			if len(areaPath) > 17 {
-				areaPath = areaPath[:14] + "..."
-			}
 
-			tags := wi.Fields.Tags
-			if len(tags) > 14 {
-				tags = tags[:11] + "..."
+			title := m.searchTitle(wi)
+			if m.fuzzyQuery != "" && i != m.cursor {
+				// Highlights are left off the cursor row so they don't cut its background short
+				label := strings.TrimSuffix(title, wi.Fields.Title)
+				title = label + highlightFuzzy(wi.Fields.Title, m.fuzzyQuery, columns[titleColumn].width-1-lipgloss.Width(label))
 			}
 
-			comments := fmt.Sprintf("%d", wi.Fields.CommentCount)
-
-			// Count hierarchy relations (parent + children)
-			relatedCount := 0
-			for _, rel := range wi.Relations {
-				if rel.Rel == "System.LinkTypes.Hierarchy-Reverse" || rel.Rel == "System.LinkTypes.Hierarchy-Forward" {
-					relatedCount++
-				}
-			}
-			related := fmt.Sprintf("%d", relatedCount)
+			row := renderBoardRow(columns, []string{
+				id,
+				wi.Fields.WorkItemType,
+				title,
+				m.parentLabel(wi),
+				assigneeLabel(wi, m.showUniqueNames),
+				wi.Fields.State,
+				areaPath,
+				wi.Fields.Tags,
+				fmt.Sprintf("%d", wi.Fields.CommentCount),
+				linkBadge(countHierarchyLinks(wi)),
+			})
 
-			activityDate := ""
-			if wi.Fields.ChangedDate != "" {
-				if t, err := time.Parse(time.RFC3339, wi.Fields.ChangedDate); err == nil {
-					activityDate = t.Format("Jan 02 '06")
-				}
+			// The activity cell is styled on its own so stale items can be highlighted
+			activityDate := padToWidth(relativeTime(wi.Fields.ChangedDate), columns[len(columns)-1].width)
+			if i != m.cursor && !removed && isStale(wi.Fields.ChangedDate, m.appConfig.StaleDays) {
+				activityDate = staleStyle.Render(activityDate)
 			}
-
-			row := lipgloss.JoinHorizontal(
-				lipgloss.Top,
-				colID.Render(id),
-				colType.Render(wiType),
-				colTitle.Render(title),
-				colAssigned.Render(assignedTo),
-				colState.Render(state),
-				colArea.Render(areaPath),
-				colTags.Render(tags),
-				colComments.Render(comments),
-				colRelated.Render(related),
-				colActivity.Render(activityDate),
-			)
+			row += activityDate
 
 			if i == m.cursor {
 				b.WriteString(selectedStyle.Render(row))
+			} else if removed {
+				// Removed items still match queries, so they're shown but dimmed
+				b.WriteString(removedStyle.Render(row))
 			} else {
 				b.WriteString(normalStyle.Render(row))
 			}
@@ -332,9 +719,15 @@ func (m Model) viewBoard() string {
 		}
 	}
 
+	// Show how fresh the data is
+	if !m.lastFetched.IsZero() {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("updated " + updatedAgo(m.lastFetched)))
+		b.WriteString("\n")
+	}
+
 	if m.err != nil {
 		b.WriteString("\n")
-		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString(m.renderError())
 	}
 
 	if m.message != "" {
@@ -350,6 +743,21 @@ func (m Model) viewBoard() string {
 			Bold(true)
 		b.WriteString(notifyStyle.Render(m.notifyMessage))
 	}
+	if unread := m.unreadNotifications(); unread > 0 {
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(fmt.Sprintf("🔔 %d unread (alt+n: history)", unread)))
+	}
+
+	if m.bulkRunning() {
+		b.WriteString("\n")
+		b.WriteString(m.renderBulkProgress())
+	}
+
+	// Warn when background change checks keep failing (e.g. expired PAT)
+	if m.notificationsEnabled && m.notifyFailures >= notifyFailureThreshold {
+		b.WriteString("\n")
+		b.WriteString(staleStyle.Render("⚠ change checks failing"))
+	}
 
 	b.WriteString("\n")
 
@@ -364,28 +772,521 @@ func (m Model) viewBoard() string {
 			Bold(true)
 
 		deletePrompt := fmt.Sprintf("⚠️  DELETE #%d\n\n", m.deleteWorkItemID)
-		deletePrompt += warningStyle.Render("To confirm deletion, type the title:") + "\n"
-		deletePrompt += fmt.Sprintf("\"%s\"\n\n", m.deleteWorkItemTitle)
-		deletePrompt += fmt.Sprintf("Your input: %s_\n\n", m.deleteConfirmInput)
-		deletePrompt += "enter: confirm • esc: cancel"
+		if m.appConfig.QuickDelete {
+			deletePrompt += warningStyle.Render(fmt.Sprintf("Delete \"%s\"?", m.deleteWorkItemTitle)) + "\n\n"
+			deletePrompt += "y: delete • n/esc: cancel"
+		} else {
+			deletePrompt += warningStyle.Render("To confirm deletion, type the title:") + "\n"
+			deletePrompt += fmt.Sprintf("\"%s\"\n\n", m.deleteWorkItemTitle)
+			deletePrompt += fmt.Sprintf("Your input: %s_\n\n", m.deleteConfirmInput)
+			deletePrompt += "enter: confirm • esc: cancel"
+		}
 		b.WriteString(deleteStyle.Render(deletePrompt))
 		b.WriteString("\n")
-	} else {
-		helpText := "↑/k ↓/j: navigate • ←/h →/l: page • c/n: create • d: delete • r: refresh"
-		if m.username != "" {
-			if m.showAll {
-				helpText += " • a: show mine"
+	} else if m.pickingIterationFilter {
+		pickerStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("39")).
+			Padding(0, 1)
+		var picker strings.Builder
+		picker.WriteString("Filter by iteration\n\n")
+		options := m.iterationFilterOptions()
+		labels := []string{"(all iterations)"}
+		for _, iter := range options {
+			label := iter.Name
+			if dates := formatIterationDates(iter.Attributes, m.appConfig.dateLayout()); dates != "" {
+				label += fmt.Sprintf(" (%s)", dates)
+			}
+			labels = append(labels, label)
+		}
+		for i, label := range labels {
+			if i == m.iterationFilterCursor {
+				picker.WriteString(selectedStyle.Render(label))
 			} else {
-				helpText += " • a: show all"
+				picker.WriteString(normalStyle.Render(label))
 			}
+			picker.WriteString("\n")
+		}
+		if len(options) == 0 {
+			picker.WriteString("Loading iterations...\n")
 		}
-		helpText += " • e: edit • o: open • q: quit"
-		b.WriteString(helpStyle.Render(helpText))
+		picker.WriteString("\n↑↓: select • enter: apply • esc: cancel")
+		b.WriteString(pickerStyle.Render(picker.String()))
+		b.WriteString("\n")
+	} else if m.jumpingToID {
+		jumpStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("39")).
+			Padding(0, 1)
+		jumpPrompt := fmt.Sprintf("Go to work item #%s_\n\n", m.jumpIDInput)
+		jumpPrompt += "enter: open • esc: cancel"
+		b.WriteString(jumpStyle.Render(jumpPrompt))
+		b.WriteString("\n")
+	} else if m.bulkTagging {
+		tagStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("39")).
+			Padding(0, 1)
+		tagPrompt := fmt.Sprintf("Add tag to %d selected items: %s_\n\n", len(m.selectedIDs), m.bulkTagInput)
+		tagPrompt += "enter: apply • esc: cancel"
+		b.WriteString(tagStyle.Render(tagPrompt))
+		b.WriteString("\n")
+	} else if m.searching {
+		searchStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("39")).
+			Padding(0, 1)
+		searchPrompt := fmt.Sprintf("Search ID, title or tags: %s_\n", m.searchInput)
+		if strings.TrimSpace(m.searchInput) != "" {
+			searchPrompt += fmt.Sprintf("%d of %d loaded items match\n", countSearchMatches(m.workItems, m.searchInput), len(m.workItems))
+		}
+		searchPrompt += "\nenter: search • esc: cancel"
+		b.WriteString(searchStyle.Render(searchPrompt))
+		b.WriteString("\n")
+	} else if m.fuzzyFiltering {
+		fuzzyStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("39")).
+			Padding(0, 1)
+		fuzzyPrompt := fmt.Sprintf("Filter titles: %s_\n", m.fuzzyQuery)
+		fuzzyPrompt += fmt.Sprintf("%d of %d loaded items match\n\n", len(m.workItems), len(m.fuzzyAll))
+		fuzzyPrompt += "↑↓: move • enter: keep filter • esc: clear"
+		b.WriteString(fuzzyStyle.Render(fuzzyPrompt))
+		b.WriteString("\n")
+	} else if m.importing {
+		importStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("39")).
+			Padding(0, 1)
+		importPrompt := fmt.Sprintf("Import work items from CSV file: %s_\n", m.importPathInput)
+		importPrompt += fmt.Sprintf("Columns: %s (type and title required)\n\n", strings.Join(importColumns, ", "))
+		importPrompt += "enter: import • esc: cancel"
+		b.WriteString(importStyle.Render(importPrompt))
+		b.WriteString("\n")
+	} else if m.savingQuery {
+		queryStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("39")).
+			Padding(0, 1)
+		queryPrompt := fmt.Sprintf("Save board query to My Queries as: %s_\n\n", m.saveQueryInput)
+		queryPrompt += "enter: save • esc: cancel"
+		b.WriteString(queryStyle.Render(queryPrompt))
+		b.WriteString("\n")
+	} else if m.creatingRelated {
+		b.WriteString(m.renderCreateRelatedForm())
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("type title • ←/→: change type • enter: create • esc: cancel"))
+	} else if m.offline {
+		b.WriteString(helpStyle.Render("↑/k ↓/j: navigate • pgup/pgdn: scroll • e: view • R: reconnect • q: quit"))
+	} else {
+		b.WriteString(helpStyle.Render(m.boardHelpText()))
 	}
 
 	return b.String()
 }
 
+// boardHelpText returns the board's key help, which switches to the bulk actions
+// while items are selected
+func (m Model) boardHelpText() string {
+	if n := len(m.selectedIDs); n > 0 {
+		return fmt.Sprintf("%d selected • space: select/deselect • T: tag selected • esc: clear selection • ↑/k ↓/j: navigate • q: quit", n)
+	}
+	helpText := "↑/k ↓/j: navigate • pgup/pgdn: scroll • ←/h →/l: page • g/G: first/last • c/n: create • d: delete • r: refresh"
+	if m.username != "" {
+		helpText += " • m: assign to me"
+		if m.myQueue {
+			helpText += " • M: exit my queue"
+		} else if m.showAll {
+			helpText += " • a: show mine • M: my queue"
+		} else {
+			helpText += " • a: show all • M: my queue"
+		}
+	}
+	if m.lastAssignee != "" {
+		helpText += fmt.Sprintf(" • A: assign to %s", m.lastAssignee)
+	}
+	if m.iterationFilter != "" || m.currentIteration {
+		helpText += " • i: iteration • C: current iteration • I: clear iteration"
+	} else {
+		helpText += " • i: iteration • C: current iteration"
+	}
+	if m.hideCompleted {
+		helpText += " • x: show done"
+	} else {
+		helpText += " • x: hide done"
+	}
+	if m.soundMuted {
+		helpText += " • S: unmute"
+	} else {
+		helpText += " • S: mute"
+	}
+	if m.lastUndo != nil {
+		helpText += " • z: undo"
+	}
+	if m.searchText != "" {
+		helpText += " • /: search • esc: clear search"
+	} else {
+		helpText += " • /: search"
+	}
+	if m.fuzzyAll != nil {
+		helpText += " • f: fuzzy filter • esc: clear filter"
+	} else {
+		helpText += " • f: fuzzy filter"
+	}
+	helpText += " • space: select • s: sort • [/]: title width • Q: save query • U: import CSV • +: add child"
+	if m.showUniqueNames {
+		helpText += " • u: display names"
+	} else {
+		helpText += " • u: unique names"
+	}
+	helpText += " • e: edit • #: go to ID • b: recycle bin • o: open • O: open board • alt+e: error log • alt+n: notifications • q: quit"
+	return helpText
+}
+
+// iterationFilterOptions returns the iterations offered by the board's iteration filter
+func (m Model) iterationFilterOptions() []azdo.Iteration {
+	now := time.Now()
+	if hasIterationGroups(m.iterations, now) {
+		return groupIterations(m.iterations, now)
+	}
+	return m.iterations
+}
+
+// lastPathSegment returns the last segment of a backslash-separated path (e.g. an iteration name)
+func lastPathSegment(path string) string {
+	if idx := strings.LastIndex(path, "\\"); idx >= 0 {
+		return path[idx+1:]
+	}
+	return path
+}
+
+// boardPageSize returns how many board rows fit on screen
+func (m Model) boardPageSize() int {
+	pageSize := m.height - 12
+	if m.height == 0 || pageSize < 1 {
+		pageSize = 10 // Height not yet initialized
+	}
+	return pageSize
+}
+
+// visibleRange returns the slice bounds of work items on the cursor's local page
+func (m Model) visibleRange() (start, end int) {
+	pageSize := m.boardPageSize()
+	currentPage := m.cursor / pageSize
+
+	start = currentPage * pageSize
+	end = start + pageSize
+	if end > len(m.workItems) {
+		end = len(m.workItems)
+	}
+	return start, end
+}
+
+// isCompact reports whether the terminal is too narrow for the full board table
+func (m Model) isCompact() bool {
+	return m.width > 0 && m.width < m.appConfig.CompactWidth
+}
+
+// removedState is the state Azure DevOps uses for soft-deleted work items
+const removedState = "Removed"
+
+// removedMarker prefixes items in the Removed state on the board
+const removedMarker = "⊘ "
+
+// blockedMarker prefixes blocked items on the board and in the detail header
+const blockedMarker = "🚫 "
+
+// isRemovedState reports whether state is the Removed state
+func isRemovedState(state string) bool {
+	return strings.EqualFold(state, removedState)
+}
+
+// renderRowCompact renders a work item as a single line (ID, title, state badge)
+// that fits within width columns
+func renderRowCompact(wi azdo.WorkItem, width int) string {
+	id := fmt.Sprintf("#%d", wi.ID)
+	badge := stateBadge(wi.Fields.State)
+
+	titleWidth := width - lipgloss.Width(id) - lipgloss.Width(badge) - 2
+	if titleWidth < 4 {
+		// Not even room for a title; show what fits of the ID and badge
+		return truncateWidth(id+" "+badge, width)
+	}
+	title := truncateWidth(wi.Fields.Title, titleWidth)
+	title = padToWidth(title, titleWidth)
+	return id + " " + title + " " + badge
+}
+
+// missingParentIDs returns the unique parent IDs of items whose parent is neither among
+// items nor in known, in order of first appearance
+func missingParentIDs(items []azdo.WorkItem, known map[int]string) []int {
+	loaded := make(map[int]bool, len(items))
+	for _, wi := range items {
+		loaded[wi.ID] = true
+	}
+	var ids []int
+	for _, wi := range items {
+		pid := wi.ParentID()
+		if pid == 0 || loaded[pid] {
+			continue
+		}
+		if _, ok := known[pid]; ok {
+			continue
+		}
+		loaded[pid] = true // only list each parent once
+		ids = append(ids, pid)
+	}
+	return ids
+}
+
+// parentLabel returns "#ID Title" for an item's parent, or "" for items without one.
+// The title comes from the board or the fetched parent titles, and is omitted until known.
+func (m Model) parentLabel(wi azdo.WorkItem) string {
+	pid := wi.ParentID()
+	if pid == 0 {
+		return ""
+	}
+	// A parent that couldn't be looked up is recorded with an empty title
+	title := m.parentTitles[pid]
+	if title == "" {
+		for _, item := range m.workItems {
+			if item.ID == pid {
+				title = item.Fields.Title
+				break
+			}
+		}
+	}
+	if title == "" {
+		return fmt.Sprintf("#%d", pid)
+	}
+	return fmt.Sprintf("#%d %s", pid, title)
+}
+
+// countHierarchyLinks returns the number of parent and child links on a work item
+func countHierarchyLinks(wi azdo.WorkItem) int {
+	count := 0
+	for _, rel := range wi.Relations {
+		if rel.Rel == "System.LinkTypes.Hierarchy-Reverse" || rel.Rel == "System.LinkTypes.Hierarchy-Forward" {
+			count++
+		}
+	}
+	return count
+}
+
+// linkBadge renders a link count as "🔗N", or nothing for items without links
+func linkBadge(count int) string {
+	if count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("🔗%d", count)
+}
+
+// assigneeLabel returns the display name of a work item's assignee, or its unique name
+// when unique is set, falling back to the other name when one is missing.
+// Items without an assignee return "(unassigned)".
+func assigneeLabel(wi azdo.WorkItem, unique bool) string {
+	a := wi.Fields.AssignedTo
+	if a == nil {
+		return "(unassigned)"
+	}
+	first, second := a.DisplayName, a.UniqueName
+	if unique {
+		first, second = second, first
+	}
+	if first != "" {
+		return first
+	}
+	if second != "" {
+		return second
+	}
+	return "(unassigned)"
+}
+
+// stateBadge returns a short bracketed abbreviation of a state (e.g. "[ACT]")
+func stateBadge(state string) string {
+	abbrev := []rune(strings.ToUpper(state))
+	if len(abbrev) > 3 {
+		abbrev = abbrev[:3]
+	}
+	return "[" + string(abbrev) + "]"
+}
+
+// boardColumn is a fixed-width column of the full board table
+type boardColumn struct {
+	header string
+	width  int // display columns, including the gap before the next column
+}
+
+// boardColumns are the columns of the full board table, in order
+var boardColumns = []boardColumn{
+	{header: "ID", width: 12},
+	{header: "Type", width: 12},
+	{header: "Title", width: 35},
+	{header: "Parent", width: 20},
+	{header: "Assigned To", width: 25},
+	{header: "State", width: 12},
+	{header: "Area Path", width: 18},
+	{header: "Tags", width: 15},
+	{header: "💬", width: 4},
+	{header: "🔗", width: 5},
+	{header: "Activity", width: 14},
+}
+
+// titleColumn is the index of the Title column in boardColumns
+const titleColumn = 2
+
+// Limits and step for the board's title column width
+const (
+	minTitleWidth  = 20
+	maxTitleWidth  = 120
+	titleWidthStep = 5
+)
+
+// titleWidth returns the width of the board's title column: the configured width,
+// or whatever the terminal has left after the other columns
+func (m Model) titleWidth() int {
+	width := m.appConfig.TitleWidth
+	if width <= 0 {
+		if m.width <= 0 {
+			return boardColumns[titleColumn].width
+		}
+		width = m.width - normalStyle.GetHorizontalFrameSize()
+		for i, col := range boardColumns {
+			if i != titleColumn {
+				width -= col.width
+			}
+		}
+	}
+	return min(max(width, minTitleWidth), maxTitleWidth)
+}
+
+// boardColumns returns the board columns with the title column sized for the terminal
+func (m Model) boardColumns() []boardColumn {
+	columns := slices.Clone(boardColumns)
+	columns[titleColumn].width = m.titleWidth()
+	return columns
+}
+
+// resizeTitle grows or shrinks the title column by delta and saves the new width
+func (m Model) resizeTitle(delta int) (Model, tea.Cmd) {
+	width := min(max(m.titleWidth()+delta, minTitleWidth), maxTitleWidth)
+	m.appConfig.TitleWidth = width
+	m.message = fmt.Sprintf("Title column width: %d", width)
+	return m, m.saveAppConfig()
+}
+
+// renderBoardRow lays cells out in the given columns, truncating and padding
+// each by display width so wide characters and emoji keep the columns aligned
+func renderBoardRow(columns []boardColumn, cells []string) string {
+	var b strings.Builder
+	for i, cell := range cells {
+		if i >= len(columns) {
+			break
+		}
+		width := columns[i].width
+		b.WriteString(padToWidth(truncateWidth(cell, width-1), width))
+	}
+	return b.String()
+}
+
+// padToWidth pads s with spaces to w display columns; wider strings are returned unchanged
+func padToWidth(s string, w int) string {
+	if gap := w - lipgloss.Width(s); gap > 0 {
+		return s + strings.Repeat(" ", gap)
+	}
+	return s
+}
+
+// truncateWidth shortens s to at most width display columns, adding "…" when cut
+func truncateWidth(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// relativeTime formats an RFC3339 timestamp as a short age relative to now (e.g. "3d ago")
+func relativeTime(changedDate string) string {
+	if changedDate == "" {
+		return ""
+	}
+	t, err := time.Parse(time.RFC3339, changedDate)
+	if err != nil {
+		return ""
+	}
+
+	age := time.Since(t)
+	if age < time.Minute {
+		return "just now"
+	}
+	return formatAge(age)
+}
+
+// updatedAgo formats the time since t with seconds precision (e.g. "12s ago")
+func updatedAgo(t time.Time) string {
+	age := time.Since(t)
+	if age < time.Minute {
+		return fmt.Sprintf("%ds ago", int(age.Seconds()))
+	}
+	return formatAge(age)
+}
+
+// formatAge formats an age of at least a minute in its largest whole unit
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	case age < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	case age < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(age.Hours()/(24*30)))
+	default:
+		return fmt.Sprintf("%dy ago", int(age.Hours()/(24*365)))
+	}
+}
+
+// isStale reports whether an item has gone untouched for at least staleDays days
+// A staleDays value of zero or less disables the check
+func isStale(changedDate string, staleDays int) bool {
+	if staleDays <= 0 || changedDate == "" {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339, changedDate)
+	if err != nil {
+		return false
+	}
+	return time.Since(t) >= time.Duration(staleDays)*24*time.Hour
+}
+
+// backlogLevels maps work item types to the backlog level whose board shows them
+var backlogLevels = map[string]string{
+	"Epic":                 "Epics",
+	"Feature":              "Features",
+	"User Story":           "Stories",
+	"Product Backlog Item": "Backlog items",
+	"Requirement":          "Requirements",
+	"Issue":                "Issues",
+}
+
+// backlogURL returns the web URL of the team's board for the selected item's backlog level,
+// or of the team's default board when its type isn't a backlog item
+func (m Model) backlogURL() string {
+	backlog := ""
+	if m.cursor < len(m.workItems) {
+		backlog = backlogLevels[m.workItems[m.cursor].Fields.WorkItemType]
+	}
+	return m.client.BacklogWebURL(backlog)
+}
+
 // openBrowser opens the specified URL in the default browser
 func openBrowser(url string) error {
 	var cmd *exec.Cmd
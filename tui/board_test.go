@@ -0,0 +1,1343 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "empty date",
+			input: "",
+			want:  "",
+		},
+		{
+			name:  "invalid date",
+			input: "not-a-date",
+			want:  "",
+		},
+		{
+			name:  "just now",
+			input: now.Add(-10 * time.Second).Format(time.RFC3339),
+			want:  "just now",
+		},
+		{
+			name:  "minutes",
+			input: now.Add(-5*time.Minute - time.Second).Format(time.RFC3339),
+			want:  "5m ago",
+		},
+		{
+			name:  "hours",
+			input: now.Add(-3*time.Hour - time.Minute).Format(time.RFC3339),
+			want:  "3h ago",
+		},
+		{
+			name:  "days",
+			input: now.Add(-3*24*time.Hour - time.Hour).Format(time.RFC3339),
+			want:  "3d ago",
+		},
+		{
+			name:  "months",
+			input: now.Add(-65 * 24 * time.Hour).Format(time.RFC3339),
+			want:  "2mo ago",
+		},
+		{
+			name:  "years",
+			input: now.Add(-800 * 24 * time.Hour).Format(time.RFC3339),
+			want:  "2y ago",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := relativeTime(tt.input); got != tt.want {
+				t.Errorf("relativeTime(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsStale(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		input     string
+		staleDays int
+		want      bool
+	}{
+		{
+			name:      "disabled threshold",
+			input:     now.Add(-100 * 24 * time.Hour).Format(time.RFC3339),
+			staleDays: 0,
+			want:      false,
+		},
+		{
+			name:      "just under threshold",
+			input:     now.Add(-7*24*time.Hour + time.Minute).Format(time.RFC3339),
+			staleDays: 7,
+			want:      false,
+		},
+		{
+			name:      "exactly at threshold",
+			input:     now.Add(-7 * 24 * time.Hour).Format(time.RFC3339),
+			staleDays: 7,
+			want:      true,
+		},
+		{
+			name:      "well past threshold",
+			input:     now.Add(-30 * 24 * time.Hour).Format(time.RFC3339),
+			staleDays: 7,
+			want:      true,
+		},
+		{
+			name:      "invalid date",
+			input:     "garbage",
+			staleDays: 7,
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isStale(tt.input, tt.staleDays); got != tt.want {
+				t.Errorf("isStale(%q, %d) = %v, want %v", tt.input, tt.staleDays, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkItemFilterHideCompleted(t *testing.T) {
+	m := NewModel()
+	m.username = "me@example.com"
+	m.appConfig.DoneStates = []string{"Closed", "Done"}
+
+	filter := m.workItemFilter()
+	if filter.AssignedTo != "me@example.com" {
+		t.Errorf("AssignedTo = %q, want username when not showing all", filter.AssignedTo)
+	}
+	if len(filter.ExcludeStates) != 0 {
+		t.Errorf("ExcludeStates = %v, want none when not hiding completed", filter.ExcludeStates)
+	}
+
+	m.hideCompleted = true
+	m.showAll = true
+	filter = m.workItemFilter()
+	if filter.AssignedTo != "" {
+		t.Errorf("AssignedTo = %q, want empty when showing all", filter.AssignedTo)
+	}
+	if len(filter.ExcludeStates) != 2 || filter.ExcludeStates[0] != "Closed" {
+		t.Errorf("ExcludeStates = %v, want configured done states", filter.ExcludeStates)
+	}
+}
+
+func TestBoardToggleHideCompleted(t *testing.T) {
+	m := setupBoardModel()
+	m.cursor = 1
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = newModel.(Model)
+
+	if !m.hideCompleted {
+		t.Error("x should enable hiding completed items")
+	}
+	if m.cursor != 0 {
+		t.Errorf("cursor = %d, want 0 after toggling", m.cursor)
+	}
+	if cmd == nil {
+		t.Error("toggling should refetch work items")
+	}
+	if !strings.Contains(m.viewBoard(), "hiding done") {
+		t.Error("board header should indicate completed items are hidden")
+	}
+}
+
+func TestBoardMyQueueMode(t *testing.T) {
+	m := setupBoardModel()
+	m.username = "me@example.com"
+	m.showAll = true
+	m.hideCompleted = true
+	var query string
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/wiql") {
+			body, _ := io.ReadAll(r.Body)
+			query = string(body)
+			_, _ = w.Write([]byte(`{"workItems":[{"id":7}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"count":1,"value":[{"id":7}]}`))
+	})
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}})
+	m = newModel.(Model)
+
+	if !m.myQueue {
+		t.Fatal("M should enable my queue mode")
+	}
+	if cmd == nil {
+		t.Fatal("entering my queue should refetch work items")
+	}
+	if msg, ok := cmd().(workItemsPageMsg); !ok || msg.err != nil || len(msg.items) != 1 {
+		t.Fatalf("Expected the queue to load, got %+v", msg)
+	}
+	if !strings.Contains(query, "[System.AssignedTo] = 'me@example.com'") || strings.Contains(query, "NOT IN") {
+		t.Errorf("Expected the GetMyWorkItems query, got %s", query)
+	}
+	if got := m.workItemFilter().AssignedTo; got != "me@example.com" {
+		t.Errorf("AssignedTo = %q, want username even when showing all", got)
+	}
+	if !strings.Contains(m.viewBoard(), "my queue") {
+		t.Error("board header should indicate my queue mode")
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}})
+	m = newModel.(Model)
+	if m.myQueue {
+		t.Error("M should toggle my queue mode off")
+	}
+	if got := m.workItemFilter().AssignedTo; got != "" {
+		t.Errorf("AssignedTo = %q, want empty when showing all", got)
+	}
+}
+
+func TestRenderRowCompact(t *testing.T) {
+	wi := azdo.WorkItem{
+		ID: 12345,
+		Fields: azdo.WorkItemFields{
+			Title: "A very long work item title that would never fit on a narrow terminal",
+			State: "Active",
+		},
+	}
+
+	for _, width := range []int{10, 20, 40, 78} {
+		row := renderRowCompact(wi, width)
+		if strings.Contains(row, "\n") {
+			t.Errorf("width %d: row contains a line break: %q", width, row)
+		}
+		if got := lipgloss.Width(row); got > width {
+			t.Errorf("width %d: row is %d columns wide: %q", width, got, row)
+		}
+	}
+
+	row := renderRowCompact(wi, 40)
+	if !strings.HasPrefix(row, "#12345 ") || !strings.HasSuffix(row, "[ACT]") {
+		t.Errorf("row = %q, want ID prefix and state badge suffix", row)
+	}
+}
+
+func TestBoardCompactMode(t *testing.T) {
+	m := setupBoardModel()
+	m.appConfig.CompactWidth = 80
+	m.width = 50
+	m.height = 30
+
+	if !m.isCompact() {
+		t.Fatal("board should be compact below the configured width")
+	}
+
+	rows := 0
+	for _, line := range strings.Split(m.viewBoard(), "\n") {
+		if !strings.Contains(line, "First Item") && !strings.Contains(line, "Second Item") {
+			continue
+		}
+		rows++
+		if got := lipgloss.Width(line); got > m.width {
+			t.Errorf("row is %d columns wide, want at most %d: %q", got, m.width, line)
+		}
+	}
+
+	if rows != 2 {
+		t.Errorf("found %d compact rows, want one line per item", rows)
+	}
+
+	m.width = 120
+	if m.isCompact() {
+		t.Error("board should use the full table at or above the configured width")
+	}
+}
+
+func TestBoardJumpToID(t *testing.T) {
+	m := setupBoardModel()
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'#'}})
+	m = newModel.(Model)
+	if !m.jumpingToID {
+		t.Fatal("# should open the jump to ID prompt")
+	}
+	for _, r := range "4x2" {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = newModel.(Model)
+	}
+	if m.jumpIDInput != "42" {
+		t.Errorf("jumpIDInput = %q, want only digits", m.jumpIDInput)
+	}
+	if !strings.Contains(m.viewBoard(), "Go to work item #42") {
+		t.Error("board should show the jump prompt")
+	}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd == nil || !m.loading || m.jumpingToID {
+		t.Fatal("enter should fetch the work item")
+	}
+
+	item := &azdo.WorkItem{ID: 42, Fields: azdo.WorkItemFields{Title: "Far Away Item", State: "New"}}
+	newModel, _ = m.Update(jumpToWorkItemMsg{id: 42, item: item})
+	m = newModel.(Model)
+	if m.view != ViewDetail {
+		t.Errorf("view = %v, want ViewDetail", m.view)
+	}
+	if m.selectedItem == nil || m.selectedItem.ID != 42 {
+		t.Errorf("selectedItem = %v, want #42", m.selectedItem)
+	}
+	if m.detailInputs[0].Value() != "Far Away Item" {
+		t.Errorf("title input = %q, want fetched title", m.detailInputs[0].Value())
+	}
+}
+
+func TestBoardJumpToIDErrors(t *testing.T) {
+	m := setupBoardModel()
+	m.jumpingToID = true
+
+	// Empty input is rejected without a request
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd != nil || m.err == nil {
+		t.Error("enter with no ID should show an error")
+	}
+
+	// A failed lookup reports the item as not found
+	m.client = azdo.NewClient("%zz", "testproject", "", "", "testpat")
+	msg := m.jumpToWorkItem(999)()
+	newModel, _ = m.Update(msg)
+	m = newModel.(Model)
+	if m.view != ViewBoard {
+		t.Errorf("view = %v, want to stay on the board", m.view)
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "#999 not found") {
+		t.Errorf("err = %v, want not found error", m.err)
+	}
+}
+
+func TestBoardLastFetchedFooter(t *testing.T) {
+	m := setupBoardModel()
+	if strings.Contains(m.viewBoard(), "updated") {
+		t.Error("footer should not show a refresh time before the first fetch")
+	}
+
+	m.lastFetched = time.Now().Add(-12 * time.Second)
+	if view := m.viewBoard(); !strings.Contains(view, "updated 12s ago") {
+		t.Errorf("footer should show the age of the last fetch, got:\n%s", view)
+	}
+
+	m.lastFetched = time.Now().Add(-5*time.Minute - time.Second)
+	if !strings.Contains(m.viewBoard(), "updated 5m ago") {
+		t.Error("footer should switch to minutes for older data")
+	}
+
+	newModel, _ := m.Update(workItemsPageMsg{items: m.workItems})
+	m = newModel.(Model)
+	if time.Since(m.lastFetched) > time.Second {
+		t.Errorf("lastFetched = %v, want set on a successful fetch", m.lastFetched)
+	}
+}
+
+func TestBoardQuickDelete(t *testing.T) {
+	m := setupBoardModel()
+	m.appConfig.QuickDelete = true
+	m.cursor = 1
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	m = newModel.(Model)
+	if !m.deletingWorkItem || m.deleteWorkItemID != 2 {
+		t.Fatalf("d should start deleting #2, got deleting=%v id=%d", m.deletingWorkItem, m.deleteWorkItemID)
+	}
+	if view := m.viewBoard(); !strings.Contains(view, "y: delete") || strings.Contains(view, "type the title") {
+		t.Errorf("quick delete should show a y/n prompt, got:\n%s", view)
+	}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = newModel.(Model)
+	if cmd == nil || !m.loading || m.deletingWorkItem {
+		t.Error("y should dispatch the delete without a title match")
+	}
+}
+
+func TestBoardQuickDeleteCancel(t *testing.T) {
+	m := setupBoardModel()
+	m.appConfig.QuickDelete = true
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	m = newModel.(Model)
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = newModel.(Model)
+	if cmd != nil || m.deletingWorkItem || m.loading {
+		t.Error("n should cancel the delete")
+	}
+}
+
+func TestBoardStrictDeleteIsDefault(t *testing.T) {
+	m := setupBoardModel()
+	if m.appConfig.QuickDelete {
+		t.Fatal("QuickDelete should be off by default")
+	}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	m = newModel.(Model)
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = newModel.(Model)
+	if cmd != nil || !m.deletingWorkItem || m.deleteConfirmInput != "y" {
+		t.Error("y should be typed into the title confirmation when QuickDelete is off")
+	}
+}
+
+func TestBoardAssigneeColumn(t *testing.T) {
+	m := setupBoardModel()
+	m.showAll = true
+	m.workItems[0].Fields.AssignedTo = &azdo.IdentityRef{DisplayName: "Jane Doe", UniqueName: "jane@example.com"}
+	m.workItems[1].Fields.AssignedTo = nil
+
+	var firstRow, secondRow string
+	for _, line := range strings.Split(m.viewBoard(), "\n") {
+		if strings.Contains(line, "First Item") {
+			firstRow = line
+		}
+		if strings.Contains(line, "Second Item") {
+			secondRow = line
+		}
+	}
+	if !strings.Contains(firstRow, "Jane Doe") {
+		t.Errorf("assigned row should show the display name, got %q", firstRow)
+	}
+	if !strings.Contains(secondRow, "(unassigned)") {
+		t.Errorf("unassigned row should say (unassigned), got %q", secondRow)
+	}
+	if strings.Index(firstRow, "Jane Doe") != strings.Index(secondRow, "(unassigned)") {
+		t.Error("assignee column should be aligned across rows")
+	}
+}
+
+func TestBoardAssigneeTruncated(t *testing.T) {
+	m := setupBoardModel()
+	m.workItems[0].Fields.AssignedTo = &azdo.IdentityRef{DisplayName: "Bartholomew Maximilian Longname-Smith"}
+
+	view := m.viewBoard()
+	if strings.Contains(view, "Longname-Smith") {
+		t.Error("long assignee names should be truncated")
+	}
+	if !strings.Contains(view, "Bartholomew Maximilian …") {
+		t.Errorf("truncated assignee should end with an ellipsis, got:\n%s", view)
+	}
+}
+
+func TestBoardAssignToMe(t *testing.T) {
+	var ops []azdo.CreateWorkItemOp
+	m := setupBoardModel()
+	m.username = "me@example.com"
+	m.cursor = 1
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			_ = json.NewDecoder(r.Body).Decode(&ops)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":2}`))
+	})
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	m = newModel.(Model)
+	if cmd == nil || !m.loading {
+		t.Fatal("m should dispatch an update for the selected item")
+	}
+
+	newModel, cmd = m.Update(cmd())
+	m = newModel.(Model)
+	values := map[string]interface{}{}
+	for _, op := range ops {
+		values[op.Path] = op.Value
+	}
+	if values["/fields/System.AssignedTo"] != "me@example.com" {
+		t.Errorf("AssignedTo = %v, want the username", values["/fields/System.AssignedTo"])
+	}
+	if len(ops) != 1 {
+		t.Errorf("update should only touch the assignee, got %v", values)
+	}
+	if !strings.Contains(m.message, "Assigned #2 to you") {
+		t.Errorf("message = %q, want assignment confirmation", m.message)
+	}
+	if cmd == nil {
+		t.Error("assigning should refresh the board")
+	}
+}
+
+func TestBoardAssignToLastAssignee(t *testing.T) {
+	var assigned []string
+	m := setupBoardModel()
+	m.username = "me@example.com"
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			var ops []azdo.CreateWorkItemOp
+			_ = json.NewDecoder(r.Body).Decode(&ops)
+			for _, op := range ops {
+				if op.Path == "/fields/System.AssignedTo" {
+					if v, ok := op.Value.(string); ok {
+						assigned = append(assigned, v)
+					}
+				}
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1}`))
+	})
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	m = newModel.(Model)
+	if m.message != "No assignee entered yet" {
+		t.Errorf("Expected A to need an earlier assignee, got %q", m.message)
+	}
+
+	// Assigning someone in the detail view remembers them once the save succeeds
+	client := m.client
+	m = setupDetailModel()
+	m.client = client
+	m.username = "me@example.com"
+	m.detailInputs[2].SetValue("pat@example.com")
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = newModel.(Model)
+	if m.lastAssignee != "" || cmd == nil {
+		t.Fatalf("Expected the assignee to wait for the save, got %q", m.lastAssignee)
+	}
+	if failed, _ := m.Update(updateWorkItemMsg{assignee: "pat@example.com", err: errors.New("API error 400")}); failed.(Model).lastAssignee != "" {
+		t.Error("Expected a failed save not to remember the assignee")
+	}
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	if m.lastAssignee != "pat@example.com" {
+		t.Fatalf("Expected the assignee to be remembered, got %q", m.lastAssignee)
+	}
+
+	// A on the board reuses it for another item
+	m.view = ViewBoard
+	m.cursor = 1
+	if !strings.Contains(m.boardHelpText(), "A: assign to pat@example.com") {
+		t.Error("Expected the help to offer the last assignee")
+	}
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	m = newModel.(Model)
+	if cmd == nil {
+		t.Fatal("Expected A to assign the selected item")
+	}
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	if len(assigned) != 2 || assigned[1] != "pat@example.com" {
+		t.Errorf("Expected both updates to assign pat@example.com, got %v", assigned)
+	}
+	if m.message != "Assigned #2 to pat@example.com" {
+		t.Errorf("message = %q", m.message)
+	}
+
+	// Assigning yourself doesn't replace the remembered teammate
+	if m = m.rememberAssignee("ME@example.com"); m.lastAssignee != "pat@example.com" {
+		t.Errorf("Expected the current user not to be remembered, got %q", m.lastAssignee)
+	}
+}
+
+func TestBoardAssignToMeWithoutUsername(t *testing.T) {
+	m := setupBoardModel()
+	m.username = ""
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	if cmd != nil {
+		t.Error("m should do nothing without a username")
+	}
+}
+
+func TestBoardIterationFilter(t *testing.T) {
+	m := setupBoardModel()
+	m.iterations = []azdo.Iteration{
+		{ID: "1", Name: "Sprint 1", Path: "testproject\\Sprint 1"},
+		{ID: "2", Name: "Sprint 2", Path: "testproject\\Sprint 2"},
+	}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	m = newModel.(Model)
+	if !m.pickingIterationFilter {
+		t.Fatal("i should open the iteration filter picker")
+	}
+	if view := m.viewBoard(); !strings.Contains(view, "(all iterations)") || !strings.Contains(view, "Sprint 2") {
+		t.Errorf("picker should list the iterations, got:\n%s", view)
+	}
+
+	for i := 0; i < 2; i++ {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = newModel.(Model)
+	}
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd == nil || m.pickingIterationFilter {
+		t.Fatal("enter should apply the filter and refetch")
+	}
+	if m.iterationFilter != "testproject\\Sprint 2" {
+		t.Errorf("iterationFilter = %q, want Sprint 2's path", m.iterationFilter)
+	}
+	if got := m.workItemFilter().IterationPath; got != "testproject\\Sprint 2" {
+		t.Errorf("filter IterationPath = %q, want the selected iteration", got)
+	}
+	if !strings.Contains(m.viewBoard(), "(iteration: Sprint 2)") {
+		t.Error("board header should show the active iteration filter")
+	}
+
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'I'}})
+	m = newModel.(Model)
+	if cmd == nil || m.iterationFilter != "" {
+		t.Error("I should clear the iteration filter and refetch")
+	}
+	if m.workItemFilter().IterationPath != "" {
+		t.Error("cleared filter should not limit the query")
+	}
+}
+
+func TestBoardCurrentIteration(t *testing.T) {
+	m := setupBoardModel()
+	m.iterationFilter = "testproject\\Sprint 1"
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}})
+	m = newModel.(Model)
+	if cmd == nil || !m.currentIteration || m.iterationFilter != "" {
+		t.Fatal("C should switch the board to the current iteration and refetch")
+	}
+	if filter := m.workItemFilter(); !filter.CurrentIteration || filter.IterationPath != "" {
+		t.Errorf("filter = %+v, want only the current iteration", filter)
+	}
+	if !strings.Contains(m.viewBoard(), "(iteration: current)") {
+		t.Error("board header should show the current iteration mode")
+	}
+
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'I'}})
+	m = newModel.(Model)
+	if cmd == nil || m.currentIteration || m.workItemFilter().CurrentIteration {
+		t.Error("I should leave the current iteration mode and refetch")
+	}
+}
+
+func TestBoardIterationFilterFetchesIterations(t *testing.T) {
+	m := setupBoardModel()
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	m = newModel.(Model)
+	if cmd == nil {
+		t.Error("opening the picker should fetch iterations when none are loaded")
+	}
+
+	// Choosing "(all iterations)" leaves the board unfiltered
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.iterationFilter != "" {
+		t.Errorf("iterationFilter = %q, want empty", m.iterationFilter)
+	}
+}
+
+func TestCountHierarchyLinks(t *testing.T) {
+	wi := azdo.WorkItem{Relations: []azdo.WorkItemRelation{
+		{Rel: "System.LinkTypes.Hierarchy-Reverse", URL: "https://example.com/workItems/1"},
+		{Rel: "System.LinkTypes.Hierarchy-Forward", URL: "https://example.com/workItems/2"},
+		{Rel: "System.LinkTypes.Hierarchy-Forward", URL: "https://example.com/workItems/3"},
+		{Rel: "Hyperlink", URL: "https://example.com"},
+	}}
+	if got := countHierarchyLinks(wi); got != 3 {
+		t.Errorf("countHierarchyLinks() = %d, want 3", got)
+	}
+	if got := countHierarchyLinks(azdo.WorkItem{}); got != 0 {
+		t.Errorf("countHierarchyLinks() = %d, want 0 for an item without relations", got)
+	}
+}
+
+func TestBoardLinkBadge(t *testing.T) {
+	m := setupBoardModel()
+	m.workItems[0].Relations = []azdo.WorkItemRelation{
+		{Rel: "System.LinkTypes.Hierarchy-Reverse"},
+		{Rel: "System.LinkTypes.Hierarchy-Forward"},
+	}
+
+	var firstRow, secondRow string
+	for _, line := range strings.Split(m.viewBoard(), "\n") {
+		if strings.Contains(line, "First Item") {
+			firstRow = line
+		}
+		if strings.Contains(line, "Second Item") {
+			secondRow = line
+		}
+	}
+	if !strings.Contains(firstRow, "🔗2") {
+		t.Errorf("linked row should show a 🔗2 badge, got %q", firstRow)
+	}
+	if strings.Contains(secondRow, "🔗") {
+		t.Errorf("row without links should show no badge, got %q", secondRow)
+	}
+}
+
+func TestBoardReconnect(t *testing.T) {
+	m := setupBoardModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/_apis/projects/") {
+			t.Errorf("reconnect should test the connection, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "project-id", "name": "testproject"}`))
+	})
+	m.appConfig.EnableNotifications = true
+	m.notifyTicking = true
+	m.notifyFailures = 5
+	m.err = errors.New("dial tcp: connection reset")
+	// Tracking from before the outage
+	m.notificationsEnabled = true
+	m.knownRevisions = map[int]int{1: 3}
+	lastCheck := time.Now().Add(-time.Hour)
+	m.lastNotifyCheck = lastCheck
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	m = newModel.(Model)
+	if cmd == nil || !m.loading || m.err != nil {
+		t.Fatal("R should clear the error and start reconnecting")
+	}
+
+	msg := cmd()
+	if connect, ok := msg.(connectMsg); !ok || connect.err != nil {
+		t.Fatalf("reconnect should issue a successful connect, got %#v", msg)
+	}
+	newModel, cmd = m.Update(msg)
+	m = newModel.(Model)
+	if m.view != ViewBoard || m.err != nil || m.notifyFailures != 0 {
+		t.Errorf("board should be healthy after reconnecting, got view %v err %v", m.view, m.err)
+	}
+	if cmd == nil {
+		t.Error("a successful reconnect should refetch work items")
+	}
+	if !m.notificationsEnabled || m.knownRevisions[1] != 3 || !m.lastNotifyCheck.Equal(lastCheck) {
+		t.Error("a reconnect should keep the notification tracking, so changes during the outage are notified")
+	}
+}
+
+func TestBoardReconnectFails(t *testing.T) {
+	m := setupBoardModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	newModel, _ := m.Update(cmd())
+	m = newModel.(Model)
+	if m.err == nil || m.view != ViewBoard {
+		t.Errorf("a failed reconnect should stay on the board with an error, got view %v err %v", m.view, m.err)
+	}
+}
+
+// childOf returns a relation linking a work item to its parent
+func childOf(parentID int) azdo.WorkItemRelation {
+	return azdo.WorkItemRelation{
+		Rel: "System.LinkTypes.Hierarchy-Reverse",
+		URL: "https://dev.azure.com/testorg/testproject/_apis/wit/workItems/" + strconv.Itoa(parentID),
+	}
+}
+
+func TestMissingParentIDs(t *testing.T) {
+	items := []azdo.WorkItem{
+		{ID: 1, Relations: []azdo.WorkItemRelation{childOf(100)}},
+		{ID: 2, Relations: []azdo.WorkItemRelation{childOf(1)}},   // parent is on the board
+		{ID: 3, Relations: []azdo.WorkItemRelation{childOf(100)}}, // duplicate parent
+		{ID: 4, Relations: []azdo.WorkItemRelation{childOf(200)}}, // parent already fetched
+		{ID: 5, Relations: []azdo.WorkItemRelation{childOf(300)}},
+		{ID: 6},
+	}
+
+	got := missingParentIDs(items, map[int]string{200: "Known"})
+	if len(got) != 2 || got[0] != 100 || got[1] != 300 {
+		t.Errorf("missingParentIDs() = %v, want [100 300]", got)
+	}
+}
+
+func TestBoardParentColumn(t *testing.T) {
+	m := setupBoardModel()
+	m.width = 200
+	m.workItems[0].Relations = []azdo.WorkItemRelation{childOf(42)}
+	m.workItems[1].Relations = []azdo.WorkItemRelation{childOf(1)}
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ids") != "42" {
+			t.Errorf("ids = %q, want only the parent missing from the board", r.URL.Query().Get("ids"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(azdo.WorkItemListResponse{Value: []azdo.WorkItem{{ID: 42, Fields: azdo.WorkItemFields{Title: "Epic"}}}})
+	})
+
+	cmd := m.fetchParentTitles()
+	if cmd == nil {
+		t.Fatal("expected a lookup for the missing parent")
+	}
+	newModel, _ := m.Update(cmd())
+	m = newModel.(Model)
+
+	var firstRow, secondRow string
+	for _, line := range strings.Split(m.viewBoard(), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#1 ") {
+			firstRow = line
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "#2 ") {
+			secondRow = line
+		}
+	}
+	if !strings.Contains(firstRow, "#42 Epic") {
+		t.Errorf("row should show the fetched parent, got %q", firstRow)
+	}
+	if !strings.Contains(secondRow, "#1 First Item") {
+		t.Errorf("row should show a parent that is on the board, got %q", secondRow)
+	}
+	if m.fetchParentTitles() != nil {
+		t.Error("known parents should not be fetched again")
+	}
+}
+
+func TestBoardParentTitleMissesAreNotRetried(t *testing.T) {
+	m := setupBoardModel()
+	m.workItems[0].Relations = []azdo.WorkItemRelation{childOf(42)}
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// A deleted parent fails the whole batch
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "TF401232: Work item 42 does not exist"}`))
+	})
+
+	newModel, _ := m.Update(m.fetchParentTitles()())
+	m = newModel.(Model)
+	if m.fetchParentTitles() != nil {
+		t.Error("a parent that couldn't be found should not be fetched on every load")
+	}
+	if got := m.parentLabel(m.workItems[0]); got != "#42" {
+		t.Errorf("parentLabel() = %q, want the bare ID", got)
+	}
+
+	// A refresh looks the parent up again
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = newModel.(Model)
+	if m.fetchParentTitles() == nil {
+		t.Error("r should forget the missed parents")
+	}
+}
+
+func TestBoardPageKeysMoveCursorLocally(t *testing.T) {
+	m := setupBoardModel()
+	m.height = 17 // five rows fit on screen
+	m.workItems = nil
+	for i := 1; i <= 12; i++ {
+		m.workItems = append(m.workItems, azdo.WorkItem{ID: i})
+	}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	m = newModel.(Model)
+	if cmd != nil || m.loading {
+		t.Error("Expected pgdown to page locally without an API call")
+	}
+	if m.cursor != 5 {
+		t.Errorf("cursor = %d after pgdown, want 5", m.cursor)
+	}
+
+	for range 3 {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+		m = newModel.(Model)
+	}
+	if m.cursor != 11 {
+		t.Errorf("cursor = %d, want it to stop at the last item", m.cursor)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	m = newModel.(Model)
+	if m.cursor != 6 {
+		t.Errorf("cursor = %d after pgup, want 6", m.cursor)
+	}
+	for range 2 {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+		m = newModel.(Model)
+	}
+	if m.cursor != 0 {
+		t.Errorf("cursor = %d, want it to stop at the first item", m.cursor)
+	}
+}
+
+func TestBoardGoToLastAcrossPages(t *testing.T) {
+	var requestedIDs []string
+	m := setupBoardModel()
+	m.appConfig.MaxWorkItems = 10
+	m.hasMoreData = true
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/wiql") {
+			refs := make([]azdo.WorkItemRef, 25)
+			for i := range refs {
+				refs[i] = azdo.WorkItemRef{ID: i + 1}
+			}
+			_ = json.NewEncoder(w).Encode(azdo.WorkItemQueryResult{WorkItems: refs})
+			return
+		}
+		ids := r.URL.Query().Get("ids")
+		requestedIDs = append(requestedIDs, ids)
+		var items []azdo.WorkItem
+		for _, id := range strings.Split(ids, ",") {
+			n, _ := strconv.Atoi(id)
+			items = append(items, azdo.WorkItem{ID: n})
+		}
+		_ = json.NewEncoder(w).Encode(azdo.WorkItemListResponse{Count: len(items), Value: items})
+	})
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	m = newModel.(Model)
+	if cmd == nil || !m.loading {
+		t.Fatal("Expected G to fetch the last page")
+	}
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+
+	if len(requestedIDs) != 1 || requestedIDs[0] != "21,22,23,24,25" {
+		t.Errorf("Expected only the final page to be fetched, got %v", requestedIDs)
+	}
+	if m.apiPage != 2 || m.hasMoreData {
+		t.Errorf("apiPage = %d, hasMoreData = %v; want the last page", m.apiPage, m.hasMoreData)
+	}
+	if m.cursor != 4 || m.workItems[m.cursor].ID != 25 {
+		t.Errorf("cursor = %d, want it on the final item", m.cursor)
+	}
+
+	// On the last page G just moves the cursor
+	m.cursor = 0
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	m = newModel.(Model)
+	if cmd != nil || m.cursor != 4 {
+		t.Errorf("Expected G on the last page to move locally, cursor = %d", m.cursor)
+	}
+
+	// g returns to the first page
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	m = newModel.(Model)
+	if cmd == nil || !m.loading {
+		t.Error("Expected g to fetch the first page")
+	}
+}
+
+func TestBoardSortModesDriveOrderBy(t *testing.T) {
+	want := []string{
+		"ORDER BY [System.CreatedDate] DESC",
+		"ORDER BY [Microsoft.VSTS.Common.Priority] ASC",
+		"ORDER BY [System.Id] ASC",
+		"ORDER BY [System.Title] ASC",
+		"ORDER BY [System.ChangedDate] DESC",
+	}
+	var wiql string
+	m := setupBoardModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		wiql = body["query"]
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(azdo.WorkItemQueryResult{})
+	})
+
+	for i, order := range want {
+		newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+		m = newModel.(Model)
+		if cmd == nil || !m.loading {
+			t.Fatalf("Expected s to refetch the board")
+		}
+		newModel, _ = m.Update(cmd())
+		m = newModel.(Model)
+		if !strings.HasSuffix(wiql, order) {
+			t.Errorf("sort mode %d (%s): expected %q, got: %s", m.sortMode, boardSorts[m.sortMode].label, order, wiql)
+		}
+		if m.sortMode != (i+1)%len(boardSorts) {
+			t.Errorf("sortMode = %d after %d presses", m.sortMode, i+1)
+		}
+	}
+}
+
+func TestPadToWidth(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"ab", 4, "ab  "},
+		{"漢字", 6, "漢字  "},
+		{"🔗2", 5, "🔗2  "},
+		{"toolong", 4, "toolong"},
+	}
+	for _, tt := range tests {
+		if got := padToWidth(tt.in, tt.width); got != tt.want {
+			t.Errorf("padToWidth(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestBoardColumnsAlignWithWideCharacters(t *testing.T) {
+	m := setupBoardModel()
+	m.width = 200
+	m.workItems[0].Fields.Title = "ログイン画面のエラーを修正する必要があります"
+	m.workItems[0].Fields.Tags = "🔥 hot; 緊急"
+	m.workItems[1].Fields.Title = "Plain ASCII title"
+
+	var columns []int
+	for _, line := range strings.Split(m.View(), "\n") {
+		for _, state := range []string{"Active", "New"} {
+			if idx := strings.Index(line, " "+state+" "); idx >= 0 && (strings.Contains(line, "#1 ") || strings.Contains(line, "#2 ")) {
+				columns = append(columns, lipgloss.Width(line[:idx]))
+			}
+		}
+	}
+	if len(columns) != 2 {
+		t.Fatalf("Expected to find both rows' state column, got %v", columns)
+	}
+	if columns[0] != columns[1] {
+		t.Errorf("State column starts at %d and %d; want rows aligned", columns[0], columns[1])
+	}
+}
+
+func TestAssigneeLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		assignee *azdo.IdentityRef
+		unique   bool
+		want     string
+	}{
+		{"unassigned", nil, false, "(unassigned)"},
+		{"display name", &azdo.IdentityRef{DisplayName: "Jane Doe", UniqueName: "jane@example.com"}, false, "Jane Doe"},
+		{"unique name", &azdo.IdentityRef{DisplayName: "Jane Doe", UniqueName: "jane@example.com"}, true, "jane@example.com"},
+		{"missing unique name falls back", &azdo.IdentityRef{DisplayName: "Jane Doe"}, true, "Jane Doe"},
+		{"missing display name falls back", &azdo.IdentityRef{UniqueName: "jane@example.com"}, false, "jane@example.com"},
+		{"empty identity", &azdo.IdentityRef{}, true, "(unassigned)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wi := azdo.WorkItem{Fields: azdo.WorkItemFields{AssignedTo: tt.assignee}}
+			if got := assigneeLabel(wi, tt.unique); got != tt.want {
+				t.Errorf("assigneeLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoardToggleUniqueNames(t *testing.T) {
+	m := setupBoardModel()
+	m.width = 200
+	m.workItems[0].Fields.AssignedTo = &azdo.IdentityRef{DisplayName: "Jane Doe", UniqueName: "jane@example.com"}
+
+	view := m.View()
+	if !strings.Contains(view, "Jane Doe") || strings.Contains(view, "jane@example.com") {
+		t.Fatal("Expected the board to show the assignee's display name by default")
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	m = updated.(Model)
+	if !m.showUniqueNames {
+		t.Fatal("Expected u to switch to unique names")
+	}
+	view = m.View()
+	if !strings.Contains(view, "jane@example.com") || strings.Contains(view, "Jane Doe") {
+		t.Error("Expected the board to show the assignee's unique name after toggling")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	m = updated.(Model)
+	if m.showUniqueNames || !strings.Contains(m.View(), "Jane Doe") {
+		t.Error("Expected u to switch back to display names")
+	}
+}
+
+func TestDetailShowsAssigneeDisplayNameHint(t *testing.T) {
+	m := setupDetailModel()
+	m.selectedItem.Fields.AssignedTo = &azdo.IdentityRef{DisplayName: "Jane Doe", UniqueName: "jane@example.com"}
+	m.detailInputs[2].SetValue("jane@example.com")
+
+	if !strings.Contains(m.View(), "Jane Doe") {
+		t.Error("Expected the detail view to show the assignee's display name")
+	}
+	m.showUniqueNames = true
+	if strings.Contains(m.View(), "Jane Doe") {
+		t.Error("Expected only the unique name when unique names are toggled on")
+	}
+}
+
+func TestBoardAddChildOpensCreateRelatedForSelectedItem(t *testing.T) {
+	m := setupBoardModel()
+	m.cursor = 1
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}})
+	m = updated.(Model)
+	if !m.creatingRelated || !m.createRelatedAsChild {
+		t.Fatal("Expected + to open the create child form")
+	}
+	if m.createRelatedTarget != 2 {
+		t.Errorf("Expected the selected item #2 as parent, got #%d", m.createRelatedTarget)
+	}
+	if m.workItemTypes[m.createRelatedType] != "Task" {
+		t.Errorf("Expected Task as the default type, got %s", m.workItemTypes[m.createRelatedType])
+	}
+	if m.view != ViewBoard {
+		t.Error("Expected to stay on the board")
+	}
+	if !strings.Contains(m.View(), "Create New Child of #2") {
+		t.Error("Expected the board to show the create child form")
+	}
+
+	// Typing goes into the form rather than triggering board keys
+	for _, r := range "sub" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	if m.createRelatedTitle != "sub" || m.sortMode != 0 {
+		t.Errorf("Expected typed keys in the title, got %q (sort mode %d)", m.createRelatedTitle, m.sortMode)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.creatingRelated || !m.loading || cmd == nil {
+		t.Error("Expected enter to close the form and start creating the child")
+	}
+}
+
+func TestBoardCreateRelatedResultRefreshesBoard(t *testing.T) {
+	m := setupBoardModel()
+	updated, cmd := m.Update(createRelatedMsg{item: &azdo.WorkItem{ID: 9}, asChild: true})
+	m = updated.(Model)
+	if m.message != "Created child #9" {
+		t.Errorf("Unexpected message %q", m.message)
+	}
+	if !m.loading || cmd == nil {
+		t.Error("Expected the board to be refetched")
+	}
+}
+
+func TestBoardSaveQuery(t *testing.T) {
+	var saved map[string]string
+	m := setupBoardModel()
+	m.username = "me@example.com"
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&saved)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(azdo.SavedQuery{ID: "q1", Name: saved["name"]})
+	})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'Q'}})
+	m = updated.(Model)
+	if !m.savingQuery {
+		t.Fatal("Expected Q to open the save query prompt")
+	}
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("My")},
+		{Type: tea.KeySpace, Runes: []rune{' '}},
+		{Type: tea.KeyRunes, Runes: []rune("items")},
+	} {
+		updated, _ = m.Update(key)
+		m = updated.(Model)
+	}
+	if !strings.Contains(m.View(), "Save board query to My Queries as: My items_") {
+		t.Error("Expected the prompt to show the typed name")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.savingQuery || cmd == nil {
+		t.Fatal("Expected enter to close the prompt and save the query")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if saved["name"] != "My items" || !strings.Contains(saved["wiql"], "[System.AssignedTo] = 'me@example.com'") {
+		t.Errorf("Expected the board's filter to be saved, got %v", saved)
+	}
+	if m.message != `Saved query "My items" to My Queries` {
+		t.Errorf("Unexpected message %q", m.message)
+	}
+}
+
+func TestTitleWidthFollowsTerminalWidth(t *testing.T) {
+	m := setupBoardModel()
+	if got := m.titleWidth(); got != 35 {
+		t.Errorf("titleWidth() before the terminal size is known = %d, want 35", got)
+	}
+
+	fixed := normalStyle.GetHorizontalFrameSize()
+	for i, col := range boardColumns {
+		if i != titleColumn {
+			fixed += col.width
+		}
+	}
+	m.width = fixed + 60
+	if got := m.titleWidth(); got != 60 {
+		t.Errorf("titleWidth() = %d, want the 60 columns left over", got)
+	}
+	m.width = 100
+	if got := m.titleWidth(); got != minTitleWidth {
+		t.Errorf("titleWidth() on a narrow terminal = %d, want the minimum %d", got, minTitleWidth)
+	}
+	m.width = 1000
+	if got := m.titleWidth(); got != maxTitleWidth {
+		t.Errorf("titleWidth() on a huge terminal = %d, want the maximum %d", got, maxTitleWidth)
+	}
+}
+
+func TestBoardResizeTitleChangesTruncation(t *testing.T) {
+	m := setupBoardModel()
+	m.width = 200
+	m.appConfig.TitleWidth = 30
+	m.workItems[0].Fields.Title = strings.Repeat("x", 100)
+
+	// Number of title characters shown in item #1's row
+	shownTitle := func(m Model) int {
+		for _, line := range strings.Split(m.View(), "\n") {
+			if strings.Contains(line, "#1 ") {
+				return strings.Count(line, "x")
+			}
+		}
+		t.Fatal("Expected a row for item #1")
+		return 0
+	}
+
+	// One column is the gap and one is the "…"
+	if got := shownTitle(m); got != 28 {
+		t.Fatalf("Shown title = %d characters, want 28", got)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{']'}})
+	m = updated.(Model)
+	if m.appConfig.TitleWidth != 35 {
+		t.Errorf("Expected ] to widen the title column to 35, got %d", m.appConfig.TitleWidth)
+	}
+	if got := shownTitle(m); got != 33 {
+		t.Errorf("Shown title after widening = %d characters, want 33", got)
+	}
+
+	for range 10 {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'['}})
+		m = updated.(Model)
+	}
+	if m.appConfig.TitleWidth != minTitleWidth {
+		t.Errorf("Expected [ to stop at the minimum width, got %d", m.appConfig.TitleWidth)
+	}
+	if got := shownTitle(m); got != minTitleWidth-2 {
+		t.Errorf("Shown title after narrowing = %d characters, want %d", got, minTitleWidth-2)
+	}
+}
+
+func TestBoardRemovedItems(t *testing.T) {
+	m := setupBoardModel()
+	m.width = 200
+	m.workItems[1].Fields.State = "Removed"
+
+	view := m.View()
+	if !strings.Contains(view, removedMarker+"#2") {
+		t.Errorf("Expected the Removed item to be marked, got:\n%s", view)
+	}
+	if strings.Contains(view, removedMarker+"#1") {
+		t.Error("Expected active items to be unmarked")
+	}
+
+	// Narrow terminals mark the title instead
+	m.width = 60
+	if view := m.View(); !strings.Contains(view, removedMarker+"Second Item") {
+		t.Errorf("Expected the compact row to be marked, got:\n%s", view)
+	}
+}
+
+func TestWorkItemFilterHideRemoved(t *testing.T) {
+	m := setupBoardModel()
+	if slices.Contains(m.workItemFilter().ExcludeStates, "Removed") {
+		t.Error("Expected Removed items to be shown by default")
+	}
+
+	m.appConfig.HideRemoved = true
+	if got := m.workItemFilter().ExcludeStates; !slices.Equal(got, []string{"Removed"}) {
+		t.Errorf("Expected only Removed to be excluded, got %v", got)
+	}
+
+	// Already excluded as a done state; not added twice or appended to the config's list
+	m.hideCompleted = true
+	m.appConfig.DoneStates = []string{"Closed", "removed"}
+	if got := m.workItemFilter().ExcludeStates; len(got) != 2 {
+		t.Errorf("Expected Removed not to be duplicated, got %v", got)
+	}
+	m.appConfig.DoneStates = []string{"Closed"}
+	if got := m.workItemFilter().ExcludeStates; !slices.Equal(got, []string{"Closed", "Removed"}) {
+		t.Errorf("Expected Closed and Removed to be excluded, got %v", got)
+	}
+	if len(m.appConfig.DoneStates) != 1 {
+		t.Errorf("Expected the configured done states to be left alone, got %v", m.appConfig.DoneStates)
+	}
+}
+
+func TestBoardBacklogURL(t *testing.T) {
+	m := setupBoardModel()
+	m.workItems = append(m.workItems, azdo.WorkItem{ID: 3, Fields: azdo.WorkItemFields{Title: "Story", WorkItemType: "User Story"}})
+
+	// A bug has no backlog level of its own, so the team's default board opens
+	if got, want := m.backlogURL(), "https://dev.azure.com/testorg/testproject/_boards/board/t/testproject%20Team"; got != want {
+		t.Errorf("backlogURL() = %q, want %q", got, want)
+	}
+
+	m.cursor = 2
+	m.client.Team = "Web"
+	if got, want := m.backlogURL(), "https://dev.azure.com/testorg/testproject/_boards/board/t/Web/Stories"; got != want {
+		t.Errorf("backlogURL() = %q, want %q", got, want)
+	}
+}
+
+func TestBlockedIndicator(t *testing.T) {
+	m := setupBoardModel()
+	m.width = 200
+	m.workItems[0].Fields.Tags = "backend; Blocked"
+	m.workItems = append(m.workItems, azdo.WorkItem{
+		ID:        3,
+		Fields:    azdo.WorkItemFields{Title: "Third Item", State: "New", WorkItemType: "Task"},
+		Relations: []azdo.WorkItemRelation{{Rel: "System.LinkTypes.Dependency-Reverse", URL: "https://example.com/workItems/1"}},
+	})
+
+	view := m.View()
+	if !strings.Contains(view, blockedMarker+"#1") {
+		t.Errorf("Expected the item tagged Blocked to be marked, got:\n%s", view)
+	}
+	if strings.Contains(view, blockedMarker+"#2") || strings.Contains(view, blockedMarker+"#3") {
+		t.Error("Expected unblocked items to be unmarked")
+	}
+
+	// A dependency link only blocks once its link type is configured
+	m.appConfig.BlockedLinkType = "System.LinkTypes.Dependency-Reverse"
+	if view := m.View(); !strings.Contains(view, blockedMarker+"#3") {
+		t.Errorf("Expected the item with a predecessor to be marked, got:\n%s", view)
+	}
+
+	m.width = 60
+	if view := m.View(); !strings.Contains(view, blockedMarker+"First Item") {
+		t.Errorf("Expected the compact row to be marked, got:\n%s", view)
+	}
+
+	// The detail header shows it too
+	m.view = ViewDetail
+	m.selectedItem = &m.workItems[0]
+	if view := m.View(); !strings.Contains(view, blockedMarker+"Blocked") {
+		t.Errorf("Expected the blocked detail header, got:\n%s", view)
+	}
+	m.selectedItem = &m.workItems[1]
+	if view := m.View(); strings.Contains(view, blockedMarker) {
+		t.Errorf("Expected no indicator on an unblocked item, got:\n%s", view)
+	}
+}
@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toggleSelected adds or removes a work item from the bulk selection
+func (m Model) toggleSelected(id int) Model {
+	if m.selectedIDs == nil {
+		m.selectedIDs = make(map[int]bool)
+	}
+	if m.selectedIDs[id] {
+		delete(m.selectedIDs, id)
+	} else {
+		m.selectedIDs[id] = true
+	}
+	return m
+}
+
+// selectedWorkItemIDs returns the selected work item IDs in ascending order
+func (m Model) selectedWorkItemIDs() []int {
+	ids := make([]int, 0, len(m.selectedIDs))
+	for id := range m.selectedIDs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// addTag appends tag to a "tag1; tag2" string, keeping the existing tags.
+// The string is returned unchanged if the tag is already present (case-insensitively).
+func addTag(existing, tag string) string {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return existing
+	}
+	tags := parseTags(existing)
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return existing
+		}
+	}
+	return joinTags(append(tags, tag))
+}
+
+// bulkAddTag issues one command per selected work item that adds tag to it
+func (m Model) bulkAddTag(ids []int, tag string) tea.Cmd {
+	cmds := make([]tea.Cmd, 0, len(ids))
+	for _, id := range ids {
+		cmds = append(cmds, m.addTagToWorkItem(id, tag))
+	}
+	return tea.Batch(cmds...)
+}
+
+// addTagToWorkItem fetches a work item's current tags, merges tag into them and saves the result
+func (m Model) addTagToWorkItem(id int, tag string) tea.Cmd {
+	return func() tea.Msg {
+		wi, err := m.client.GetWorkItemWithRelations(id)
+		if err != nil {
+			return bulkResultMsg{id: id, err: err}
+		}
+		merged := addTag(wi.Fields.Tags, tag)
+		if merged == wi.Fields.Tags {
+			// Already tagged; nothing to write
+			return bulkResultMsg{id: id, item: wi}
+		}
+		updated, err := m.client.UpdateTags(id, merged)
+		return bulkResultMsg{id: id, item: updated, err: err}
+	}
+}
+
+// handleBulkResult records one item's outcome and reports the totals once all have arrived
+func (m Model) handleBulkResult(msg bulkResultMsg) (tea.Model, tea.Cmd) {
+	m.bulkDone++
+	if msg.err != nil {
+		m.bulkFailed++
+		m.bulkLastErr = fmt.Errorf("#%d: %w", msg.id, msg.err)
+	} else if msg.item != nil {
+		// Show the new tags without refetching the board
+		for i := range m.workItems {
+			if m.workItems[i].ID == msg.id {
+				m.workItems[i].Fields.Tags = msg.item.Fields.Tags
+				m.workItems[i].Rev = msg.item.Rev
+				break
+			}
+		}
+	}
+	if m.bulkDone < m.bulkPending {
+		progress := bulkProgressMsg{done: m.bulkDone, failed: m.bulkFailed, total: m.bulkPending}
+		return m, func() tea.Msg { return progress }
+	}
+
+	m.loading = false
+	succeeded := m.bulkDone - m.bulkFailed
+	m.message = fmt.Sprintf("Tagged %d of %d items", succeeded, m.bulkPending)
+	if m.bulkFailed > 0 {
+		m.err = fmt.Errorf("%d update(s) failed, last error: %v", m.bulkFailed, m.bulkLastErr)
+	}
+	m.bulkPending = 0
+	m.bulkDone = 0
+	m.bulkFailed = 0
+	m.bulkLastErr = nil
+	m.bulkProgress = bulkProgressMsg{}
+	return m, nil
+}
+
+// bulkRunning reports whether a bulk action is waiting for per-item results
+func (m Model) bulkRunning() bool {
+	return m.loading && m.bulkPending > 0
+}
+
+// renderBulkProgress renders how far the running bulk action has got
+func (m Model) renderBulkProgress() string {
+	text := fmt.Sprintf("⏳ %s: %d of %d completed", m.bulkLabel, m.bulkProgress.done, m.bulkPending)
+	if m.bulkProgress.failed > 0 {
+		text += fmt.Sprintf(" (%d failed)", m.bulkProgress.failed)
+	}
+	return staleStyle.Render(text)
+}
+
+// updateBulkTagPrompt handles key presses while entering a tag for the selected items
+func (m Model) updateBulkTagPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.bulkTagging = false
+		m.bulkTagInput = ""
+		return m, nil
+	case "enter":
+		tag := strings.TrimSpace(m.bulkTagInput)
+		m.bulkTagging = false
+		m.bulkTagInput = ""
+		ids := m.selectedWorkItemIDs()
+		if tag == "" || len(ids) == 0 {
+			return m, nil
+		}
+		m.loading = true
+		m.err = nil
+		m.message = ""
+		m.bulkPending = len(ids)
+		m.bulkDone = 0
+		m.bulkFailed = 0
+		m.bulkLastErr = nil
+		m.bulkLabel = "Tagging"
+		m.bulkProgress = bulkProgressMsg{total: len(ids)}
+		return m.confirmWrite(fmt.Sprintf("Add tag %q to %d items?", tag, len(ids)), m.bulkAddTag(ids, tag))
+	case "backspace":
+		if len(m.bulkTagInput) > 0 {
+			runes := []rune(m.bulkTagInput)
+			m.bulkTagInput = string(runes[:len(runes)-1])
+		}
+		return m, nil
+	case " ":
+		m.bulkTagInput += " "
+		return m, nil
+	}
+	// ";" separates tags, so it can't be part of one
+	if msg.Type == tea.KeyRunes {
+		m.bulkTagInput += strings.ReplaceAll(string(msg.Runes), ";", "")
+	}
+	return m, nil
+}
@@ -0,0 +1,225 @@
+package tui
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestAddTag(t *testing.T) {
+	tests := []struct {
+		existing, tag, want string
+	}{
+		{"", "release", "release"},
+		{"backend", "release", "backend; release"},
+		{"backend; ui", "release", "backend; ui; release"},
+		{"backend; Release", "release", "backend; Release"},
+		{"backend", "  ", "backend"},
+	}
+	for _, tt := range tests {
+		if got := addTag(tt.existing, tt.tag); got != tt.want {
+			t.Errorf("addTag(%q, %q) = %q, want %q", tt.existing, tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestBoardToggleSelection(t *testing.T) {
+	m := setupBoardModel()
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = newModel.(Model)
+	if !m.selectedIDs[1] {
+		t.Fatalf("Expected item 1 to be selected, got %v", m.selectedIDs)
+	}
+	if view := m.View(); !strings.Contains(view, "1 selected") || !strings.Contains(view, "● #1") {
+		t.Errorf("Expected the selection to be shown, got:\n%s", view)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = newModel.(Model)
+	if len(m.selectedIDs) != 0 {
+		t.Errorf("Expected item 1 to be deselected, got %v", m.selectedIDs)
+	}
+
+	m = m.toggleSelected(1).toggleSelected(2)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if len(m.selectedIDs) != 0 {
+		t.Errorf("Expected esc to clear the selection, got %v", m.selectedIDs)
+	}
+}
+
+func TestBoardBulkTagRequiresSelection(t *testing.T) {
+	m := setupBoardModel()
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	m = newModel.(Model)
+	if m.bulkTagging {
+		t.Error("Expected no tag prompt without a selection")
+	}
+}
+
+func TestBoardBulkTagOneUpdatePerItem(t *testing.T) {
+	existing := map[int]string{1: "backend", 2: "ui; release"}
+	var mu sync.Mutex
+	patches := make(map[int]string)
+	m := setupBoardModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var id int
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/workitems/1"):
+			id = 1
+		case strings.HasSuffix(r.URL.Path, "/workitems/2"):
+			id = 2
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			return
+		}
+		tags := existing[id]
+		if r.Method == "PATCH" {
+			var ops []azdo.CreateWorkItemOp
+			_ = json.NewDecoder(r.Body).Decode(&ops)
+			mu.Lock()
+			if _, dup := patches[id]; dup {
+				t.Errorf("Expected one update for #%d", id)
+			}
+			if len(ops) == 1 {
+				tags, _ = ops[0].Value.(string)
+				patches[id] = tags
+			}
+			mu.Unlock()
+		}
+		_ = json.NewEncoder(w).Encode(azdo.WorkItem{ID: id, Fields: azdo.WorkItemFields{Tags: tags}})
+	})
+	m = m.toggleSelected(1).toggleSelected(2)
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	m = newModel.(Model)
+	if !m.bulkTagging {
+		t.Fatal("Expected the tag prompt to open")
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ops;")})
+	m = newModel.(Model)
+	if m.bulkTagInput != "ops" {
+		t.Errorf("bulkTagInput = %q, want \";\" dropped", m.bulkTagInput)
+	}
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd == nil || !m.loading {
+		t.Fatal("Expected the bulk update to start")
+	}
+
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatalf("Expected one command per selected item, got %#v", batch)
+	}
+	for _, c := range batch {
+		newModel, _ = m.Update(c())
+		m = newModel.(Model)
+	}
+
+	if patches[1] != "backend; ops" || patches[2] != "ui; release; ops" {
+		t.Errorf("Expected merged tags in each update, got %v", patches)
+	}
+	if m.loading || m.err != nil {
+		t.Errorf("Expected the bulk update to finish cleanly, loading=%v err=%v", m.loading, m.err)
+	}
+	if m.message != "Tagged 2 of 2 items" {
+		t.Errorf("message = %q", m.message)
+	}
+	if m.workItems[0].Fields.Tags != "backend; ops" {
+		t.Errorf("Expected the board to show the new tags, got %q", m.workItems[0].Fields.Tags)
+	}
+}
+
+func TestBulkResultReportsFailures(t *testing.T) {
+	m := setupBoardModel()
+	m.loading = true
+	m.bulkPending = 2
+
+	newModel, _ := m.Update(bulkResultMsg{id: 1, err: &azdo.APIError{StatusCode: 400, Body: "bad"}})
+	m = newModel.(Model)
+	if !m.loading {
+		t.Error("Expected to keep loading until every item reports")
+	}
+	newModel, _ = m.Update(bulkResultMsg{id: 2, item: &azdo.WorkItem{ID: 2}})
+	m = newModel.(Model)
+	if m.loading {
+		t.Error("Expected loading to stop once every item reported")
+	}
+	if m.message != "Tagged 1 of 2 items" {
+		t.Errorf("message = %q", m.message)
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "1 update(s) failed") || !strings.Contains(m.err.Error(), "#1") {
+		t.Errorf("Expected the failure to be reported, got %v", m.err)
+	}
+}
+
+func TestBoardHelpAdaptsToSelection(t *testing.T) {
+	m := setupBoardModel()
+	m.width = 400
+
+	help := m.boardHelpText()
+	if strings.Contains(help, "T: tag selected") {
+		t.Error("Expected no bulk actions in the help without a selection")
+	}
+	if !strings.Contains(help, "space: select") || !strings.Contains(help, "c/n: create") {
+		t.Errorf("Expected the default bindings, got %q", help)
+	}
+
+	m = m.toggleSelected(1)
+	m = m.toggleSelected(2)
+	help = m.boardHelpText()
+	if !strings.Contains(help, "2 selected") || !strings.Contains(help, "T: tag selected") || !strings.Contains(help, "esc: clear selection") {
+		t.Errorf("Expected the bulk actions with a selection, got %q", help)
+	}
+	if strings.Contains(help, "c/n: create") {
+		t.Error("Expected single-item bindings to be hidden while items are selected")
+	}
+	if !strings.Contains(m.View(), "T: tag selected") {
+		t.Error("Expected the board footer to show the bulk help")
+	}
+}
+
+func TestBulkProgressAdvances(t *testing.T) {
+	m := setupBoardModel()
+	m.loading = true
+	m.bulkPending = 3
+	m.bulkLabel = "Tagging"
+	m.bulkProgress = bulkProgressMsg{total: 3}
+	if view := m.View(); !strings.Contains(view, "Tagging: 0 of 3 completed") || !strings.Contains(view, "First Item") {
+		t.Fatalf("Expected the board and the starting progress, got:\n%s", view)
+	}
+
+	newModel, cmd := m.Update(bulkResultMsg{id: 1, item: &azdo.WorkItem{ID: 1}})
+	m = newModel.(Model)
+	first := cmd()
+	newModel, _ = m.Update(first)
+	m = newModel.(Model)
+	if !strings.Contains(m.View(), "Tagging: 1 of 3 completed") {
+		t.Errorf("Expected progress after the first result, got %+v", m.bulkProgress)
+	}
+
+	newModel, cmd = m.Update(bulkResultMsg{id: 2, err: &azdo.APIError{StatusCode: 400, Body: "bad"}})
+	m = newModel.(Model)
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	// A late progress message doesn't move the count back
+	newModel, _ = m.Update(first)
+	m = newModel.(Model)
+	if !strings.Contains(m.View(), "Tagging: 2 of 3 completed (1 failed)") {
+		t.Errorf("Expected progress with the failure, got %+v", m.bulkProgress)
+	}
+
+	newModel, _ = m.Update(bulkResultMsg{id: 3, item: &azdo.WorkItem{ID: 3}})
+	m = newModel.(Model)
+	if strings.Contains(m.View(), "Tagging:") {
+		t.Error("Expected the progress to disappear once the action finished")
+	}
+}
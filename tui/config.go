@@ -13,6 +13,26 @@ import (
 func (m Model) updateConfig(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.promptingPassphrase {
+			return m.updatePassphrasePrompt(msg)
+		}
+
+		// Handle clear credentials confirmation
+		if m.confirmingClearCredentials {
+			switch msg.String() {
+			case "y", "Y":
+				m.confirmingClearCredentials = false
+				return m.clearCredentials(), nil
+			case "n", "N", "esc":
+				m.confirmingClearCredentials = false
+			}
+			return m, nil
+		}
+
+		if m.wizardActive {
+			return m.updateWizard(msg)
+		}
+
 		switch msg.String() {
 		case "tab", "down":
 			m.configFocus = (m.configFocus + 1) % len(m.configInputs)
@@ -24,40 +44,14 @@ func (m Model) updateConfig(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, m.updateConfigFocus()
 		case "enter":
-			if m.configInputs[0].Value() != "" && m.configInputs[1].Value() != "" && m.configInputs[2].Value() != "" && m.configInputs[3].Value() != "" && m.configInputs[4].Value() != "" && m.configInputs[5].Value() != "" {
-				org := m.configInputs[0].Value()
-				project := m.configInputs[1].Value()
-				team := m.configInputs[2].Value()
-				areaPath := m.configInputs[3].Value()
-				pat := m.configInputs[4].Value()
-				username := m.configInputs[5].Value()
-
-				m.client = azdo.NewClient(org, project, team, areaPath, pat)
-				m.username = username
-				m.loading = true
-
-				// Save credentials to keychain (skipped in Docker)
-				if isRunningInDocker() {
-					m.keychainMessage = "Running in Docker - credentials and config not saved"
-				} else if err := SaveCredentials(org, project, team, areaPath, pat, username); err != nil {
-					m.keychainMessage = "Warning: Could not save to keychain"
-				} else {
-					m.keychainMessage = "Credentials saved to keychain"
-				}
-
-				return m, m.connect()
+			if !m.credentialsComplete() {
+				break
 			}
+			return m.submitConfig()
 		case "ctrl+d":
-			// Clear stored credentials
-			_ = ClearCredentials()
-			m.configInputs[0].SetValue("")
-			m.configInputs[1].SetValue("")
-			m.configInputs[2].SetValue("")
-			m.configInputs[3].SetValue("")
-			m.configInputs[4].SetValue("")
-			m.configInputs[5].SetValue("")
-			m.keychainLoaded = false
-			m.keychainMessage = "Credentials cleared from keychain"
+			// Ask before clearing stored credentials (logout)
+			m.confirmingClearCredentials = true
+			m.err = nil
 			return m, nil
 		case "ctrl+f":
 			// Open config file screen
@@ -72,6 +66,182 @@ func (m Model) updateConfig(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// submitConfig connects with the entered credentials, asking for the passphrase first
+// when the file credential store is used
+func (m Model) submitConfig() (tea.Model, tea.Cmd) {
+	if m.appConfig.usesFileCredentials() {
+		// Ask for the passphrase that encrypts (or unlocks) the PAT
+		m.promptingPassphrase = true
+		m.passphraseInput.SetValue("")
+		m.err = nil
+		return m, m.passphraseInput.Focus()
+	}
+	return m.startConnect("")
+}
+
+// updateWizard handles the first-run setup wizard, which asks for one config field per step.
+// enter validates the field and moves on; esc goes back, or leaves the wizard on the first step.
+func (m Model) updateWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		if strings.TrimSpace(m.configInputs[m.configFocus].Value()) == "" && !m.configFieldOptional(m.configFocus) {
+			m.err = fmt.Errorf("%s is required", configLabels[m.configFocus])
+			return m, nil
+		}
+		m.err = nil
+		if m.configFocus < len(m.configInputs)-1 {
+			m.configFocus++
+			return m, m.updateConfigFocus()
+		}
+		m.wizardActive = false
+		return m.submitConfig()
+	case "esc":
+		m.err = nil
+		if m.configFocus == 0 {
+			// Skip the wizard and show every field at once
+			m.wizardActive = false
+			return m, nil
+		}
+		m.configFocus--
+		return m, m.updateConfigFocus()
+	}
+
+	var cmd tea.Cmd
+	m.configInputs[m.configFocus], cmd = m.configInputs[m.configFocus].Update(msg)
+	return m, cmd
+}
+
+// clearCredentials deletes the stored credentials (keychain or file) and blanks the inputs.
+// When the delete fails the inputs are kept, since the credentials are still stored.
+func (m Model) clearCredentials() Model {
+	var err error
+	if m.appConfig.usesFileCredentials() {
+		err = fileCredentialClearer()
+	} else {
+		err = credentialClearer()
+	}
+	if err != nil {
+		m.err = fmt.Errorf("could not clear saved credentials: %w", err)
+		return m
+	}
+	m.fileCreds = nil
+	for i := range m.configInputs {
+		m.configInputs[i].SetValue("")
+	}
+	m.username = ""
+	m.keychainLoaded = false
+	m.keychainFailed = false
+	if m.appConfig.usesFileCredentials() {
+		m.keychainMessage = "Credentials file cleared"
+	} else {
+		m.keychainMessage = "Credentials cleared from keychain"
+	}
+	return m
+}
+
+// credentialsComplete reports whether every config field needed to connect is filled in
+func (m Model) credentialsComplete() bool {
+	for i, input := range m.configInputs {
+		if input.Value() == "" && !m.configFieldOptional(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// configFieldOptional reports whether config input i may be left empty. The Area Path may
+// when a Team is set, since the board is then scoped to the team's areas, and the PAT may
+// when an encrypted one is saved in the credentials file.
+func (m Model) configFieldOptional(i int) bool {
+	switch i {
+	case 3:
+		return strings.TrimSpace(m.configInputs[2].Value()) != ""
+	case 4:
+		return m.appConfig.usesFileCredentials() && m.fileCreds != nil
+	}
+	return false
+}
+
+// updatePassphrasePrompt handles input while asking for the credentials file passphrase
+func (m Model) updatePassphrasePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.promptingPassphrase = false
+		m.passphraseInput.Blur()
+		return m, nil
+	case "enter":
+		passphrase := m.passphraseInput.Value()
+		if passphrase == "" {
+			m.err = fmt.Errorf("passphrase is required")
+			return m, nil
+		}
+		if m.configInputs[4].Value() == "" && m.fileCreds != nil {
+			pat, err := m.fileCreds.unlock(passphrase)
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.configInputs[4].SetValue(pat)
+		}
+		m.promptingPassphrase = false
+		m.passphraseInput.Blur()
+		m.err = nil
+		return m.startConnect(passphrase)
+	}
+
+	var cmd tea.Cmd
+	m.passphraseInput, cmd = m.passphraseInput.Update(msg)
+	return m, cmd
+}
+
+// startConnect builds the client from the config inputs, saves the credentials, and connects.
+// passphrase encrypts the PAT when using the file credential store.
+func (m Model) startConnect(passphrase string) (tea.Model, tea.Cmd) {
+	org := m.configInputs[0].Value()
+	project := m.configInputs[1].Value()
+	team := m.configInputs[2].Value()
+	areaPath := m.configInputs[3].Value()
+	pat := m.configInputs[4].Value()
+	username := m.configInputs[5].Value()
+
+	hc, err := azdo.NewHTTPClient(m.appConfig.ProxyURL)
+	if err != nil {
+		m.err = fmt.Errorf("proxy_url: %w", err)
+		return m, nil
+	}
+	m.client = azdo.NewClientWithHTTP(org, project, team, areaPath, pat, hc)
+	m.client.BaseURL = m.appConfig.ServerURL
+	if debugLogFile != nil {
+		m.client.SetDebugLog(debugLogFile)
+	}
+	m.username = username
+	m.cache = loadOfflineCache(org, project)
+	m.loading = true
+	// New credentials start a new session, with fresh notification tracking
+	m.knownRevisions = nil
+
+	// Save credentials (skipped in Docker)
+	m.keychainFailed = false
+	if isRunningInDocker() {
+		m.keychainMessage = "Running in Docker - credentials and config not saved"
+	} else if m.appConfig.usesFileCredentials() {
+		creds := storedCredentials{org: org, project: project, team: team, areaPath: areaPath, pat: pat, username: username}
+		if err := saveFileCredentials(creds, passphrase); err != nil {
+			m.keychainMessage = "Warning: Could not save credentials file"
+			m.keychainFailed = true
+		} else {
+			m.keychainMessage = "Credentials saved to encrypted file"
+		}
+	} else if err := SaveCredentials(org, project, team, areaPath, pat, username); err != nil {
+		m.keychainMessage = "Warning: Could not save to keychain"
+		m.keychainFailed = true
+	} else {
+		m.keychainMessage = "Credentials saved to keychain"
+	}
+
+	return m, m.connect()
+}
+
 func (m *Model) updateConfigFocus() tea.Cmd {
 	cmds := make([]tea.Cmd, len(m.configInputs))
 	for i := range m.configInputs {
@@ -92,6 +262,19 @@ func (m *Model) updateConfigInputs(msg tea.Msg) tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
+// configLabels names the config inputs, in order
+var configLabels = []string{"Organization", "Project", "Team", "Area Path", "Personal Access Token", "Username"}
+
+// configWizardHints explains each config input during the first-run setup wizard
+var configWizardHints = []string{
+	"The name in your Azure DevOps URL: https://dev.azure.com/<organization>\n(the collection name, e.g. DefaultCollection, when server_url is set).",
+	"The project that holds your boards, as listed on your organization's home page.",
+	"The team whose board and sprints you work in. The default team is \"<Project> Team\".",
+	"The area path for new work items, e.g. Project\\Team. See Project settings > Team configuration > Areas.\nLeave it empty to use the team's own area paths.",
+	"Create one under User settings > Personal access tokens with the Work Items (Read & write) scope.\nIt is stored securely and never shown.",
+	"The email you sign in with. It is used for \"my items\" filtering, assign to me, and notifications.",
+}
+
 func (m Model) viewConfig() string {
 	var b strings.Builder
 
@@ -99,9 +282,11 @@ func (m Model) viewConfig() string {
 	b.WriteString(title)
 	b.WriteString("\n\n")
 
-	labels := []string{"Organization", "Project", "Team", "Area Path", "Personal Access Token", "Username"}
+	if m.wizardActive {
+		return m.viewWizard(&b)
+	}
 
-	for i, label := range labels {
+	for i, label := range configLabels {
 		style := labelStyle
 		if i == m.configFocus {
 			style = style.Foreground(lipgloss.Color("229"))
@@ -112,8 +297,19 @@ func (m Model) viewConfig() string {
 		b.WriteString("\n\n")
 	}
 
+	if m.promptingPassphrase {
+		prompt := "Passphrase (encrypts your PAT in " + credentialsFileName + ")"
+		if m.fileCreds != nil && m.configInputs[4].Value() == "" {
+			prompt = "Passphrase (unlocks your saved PAT)"
+		}
+		b.WriteString(labelStyle.Foreground(lipgloss.Color("229")).Render(prompt))
+		b.WriteString("\n")
+		b.WriteString(m.passphraseInput.View())
+		b.WriteString("\n\n")
+	}
+
 	if m.err != nil {
-		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString(m.renderError())
 		b.WriteString("\n\n")
 	}
 
@@ -123,11 +319,69 @@ func (m Model) viewConfig() string {
 	}
 
 	if m.keychainMessage != "" {
-		b.WriteString(successStyle.Render("🔐 " + m.keychainMessage))
+		b.WriteString(m.renderKeychainMessage())
+		b.WriteString("\n\n")
+	}
+
+	if m.confirmingClearCredentials {
+		b.WriteString(errorStyle.Render("Clear saved credentials? This signs you out."))
+		b.WriteString("\n\n")
+	}
+
+	switch {
+	case m.confirmingClearCredentials:
+		b.WriteString(helpStyle.Render("y: clear credentials • n/esc: cancel"))
+	case m.promptingPassphrase:
+		b.WriteString(helpStyle.Render("enter: connect • esc: cancel • ctrl+c: quit"))
+	case m.appConfig.usesFileCredentials():
+		b.WriteString(helpStyle.Render("tab/↑↓: navigate • enter: connect • ctrl+d: logout (clear credentials file) • ctrl+f: settings • ctrl+c: quit"))
+	default:
+		b.WriteString(helpStyle.Render("tab/↑↓: navigate • enter: connect • ctrl+d: logout (clear keychain) • ctrl+f: settings • ctrl+c: quit"))
+	}
+
+	return boxStyle.Render(b.String())
+}
+
+// renderKeychainMessage renders the credentials status, as an error when it reports a failure
+func (m Model) renderKeychainMessage() string {
+	style := successStyle
+	if m.keychainFailed {
+		style = errorStyle
+	}
+	return style.Render("🔐 " + m.keychainMessage)
+}
+
+// viewWizard renders the current step of the first-run setup wizard
+func (m Model) viewWizard(b *strings.Builder) string {
+	step := m.configFocus
+	b.WriteString(helpStyle.Render(fmt.Sprintf("First-time setup • step %d of %d", step+1, len(m.configInputs))))
+	b.WriteString("\n\n")
+	b.WriteString(labelStyle.Foreground(lipgloss.Color("229")).Render(configLabels[step]))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(configWizardHints[step]))
+	b.WriteString("\n\n")
+	b.WriteString(m.configInputs[step].View())
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		b.WriteString(m.renderError())
+		b.WriteString("\n\n")
+	}
+
+	if m.keychainMessage != "" {
+		b.WriteString(m.renderKeychainMessage())
 		b.WriteString("\n\n")
 	}
 
-	b.WriteString(helpStyle.Render("tab/↑↓: navigate • enter: connect • ctrl+d: clear keychain • ctrl+f: settings • ctrl+c: quit"))
+	next := "enter: next"
+	if step == len(m.configInputs)-1 {
+		next = "enter: connect"
+	}
+	back := "esc: back"
+	if step == 0 {
+		back = "esc: skip setup"
+	}
+	b.WriteString(helpStyle.Render(next + " • " + back + " • ctrl+c: quit"))
 
 	return boxStyle.Render(b.String())
 }
@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	tea "github.com/charmbracelet/bubbletea"
@@ -19,18 +20,132 @@ type AppConfig struct {
 	// General settings
 	DefaultShowAll      bool `toml:"default_show_all"`     // Default value for "show all" toggle on board
 	EnableNotifications bool `toml:"enable_notifications"` // Enable sound notifications for work item changes
+	HideCompleted       bool `toml:"hide_completed"`       // Hide items in a "done" state on the board by default
+	MuteSound           bool `toml:"mute_sound"`           // Start with notification sounds muted
+	QuickDelete         bool `toml:"quick_delete"`         // Confirm board deletes with y/n instead of typing the title
+	ConfirmWrites       bool `toml:"confirm_writes"`       // Ask y/n before every change is sent to Azure DevOps
+	ConfirmEsc          bool `toml:"confirm_esc"`          // Need esc twice to leave the create and detail views (ctrl+b leaves at once)
+
+	// Comment settings
+	CommentMode         string `toml:"comment_mode"`          // How comments are posted: "comments" (default, comments API) or "history" (System.History)
+	CommentsNewestFirst bool   `toml:"comments_newest_first"` // List comments newest first (default oldest first)
+
+	// Credential settings
+	CredentialStore string `toml:"credential_store"` // Where to save credentials: "keychain" (default) or "file" (passphrase-encrypted PAT)
+
+	// Network settings
+	ProxyURL  string `toml:"proxy_url"`  // Explicit HTTP proxy (empty uses HTTP_PROXY/HTTPS_PROXY from the environment)
+	ServerURL string `toml:"server_url"` // Azure DevOps Server URL for on-premises collections (empty uses dev.azure.com)
+
+	// Notification settings
+	NotificationSound string `toml:"notification_sound"` // Custom sound file to play (empty uses the system sound)
+	NotificationScope string `toml:"notification_scope"` // Which changes notify: "assigned" (default), "mentioned" or "both"
 
 	// Display settings
 	MaxWorkItems int `toml:"max_work_items"` // Maximum work items to fetch (default 50)
+	StaleDays    int `toml:"stale_days"`     // Highlight items unchanged for this many days (0 disables)
+	CompactWidth int `toml:"compact_width"`  // Use one-line board rows below this terminal width (default 80, negative disables)
+	TitleWidth   int `toml:"title_width"`    // Board title column width (0 sizes it to the terminal)
+
+	DateFormat string `toml:"date_format"` // Go layout for dates, e.g. "Jan 02" (default) or "2006-01-02"
+	TimeFormat string `toml:"time_format"` // Go layout for times of day, e.g. "15:04" (default) or "3:04PM"
+
+	ShowUniqueNames bool `toml:"show_unique_names"` // Show assignees by unique name (email) instead of display name
+	HideRemoved     bool `toml:"hide_removed"`      // Leave items in the Removed state off the board (default shows them dimmed)
+
+	BlockedLinkType string `toml:"blocked_link_type"` // Link type that marks an item blocked, e.g. "System.LinkTypes.Dependency-Reverse" (the Blocked tag and CMMI field always do)
+
+	// Filter settings
+	DoneStates []string `toml:"done_states"` // States considered completed when hiding completed items
 }
 
+// Comment mechanisms accepted by the comment_mode config option
+const (
+	commentModeComments = "comments"
+	commentModeHistory  = "history"
+)
+
 // DefaultConfig returns a new AppConfig with default values
 func DefaultConfig() AppConfig {
 	return AppConfig{
 		DefaultShowAll:      false,
 		EnableNotifications: true, // Enable by default
 		MaxWorkItems:        50,
+		CompactWidth:        defaultCompactWidth,
+		DateFormat:          defaultDateFormat,
+		TimeFormat:          defaultTimeFormat,
+		DoneStates:          defaultDoneStates(),
+	}
+}
+
+// usesHistoryComments reports whether comments are posted as System.History updates
+func (c AppConfig) usesHistoryComments() bool {
+	return strings.EqualFold(c.CommentMode, commentModeHistory)
+}
+
+// Notification scopes accepted by the notification_scope config option
+const (
+	notifyScopeAssigned  = "assigned"  // changes to items assigned to you
+	notifyScopeMentioned = "mentioned" // new @mentions of you in comments
+	notifyScopeBoth      = "both"
+)
+
+// notifyScope returns the configured notification scope, or assigned if it isn't recognised
+func (c AppConfig) notifyScope() string {
+	switch scope := strings.ToLower(c.NotificationScope); scope {
+	case notifyScopeMentioned, notifyScopeBoth:
+		return scope
 	}
+	return notifyScopeAssigned
+}
+
+// usesFileCredentials reports whether credentials are kept in the encrypted file store
+func (c AppConfig) usesFileCredentials() bool {
+	return strings.EqualFold(c.CredentialStore, credentialStoreFile)
+}
+
+// maxWorkItemsLimit is the largest page size accepted for max_work_items
+const maxWorkItemsLimit = 500
+
+// defaultCompactWidth is the terminal width below which the board uses compact rows
+const defaultCompactWidth = 80
+
+// Default layouts for date_format and time_format
+const (
+	defaultDateFormat = "Jan 02"
+	defaultTimeFormat = "15:04"
+)
+
+// validLayout reports whether layout is a usable Go time layout, i.e. it formats at
+// least one part of the time rather than printing the same text for every time
+func validLayout(layout string) bool {
+	if strings.TrimSpace(layout) == "" {
+		return false
+	}
+	sample := time.Date(2001, time.November, 23, 21, 17, 39, 0, time.UTC)
+	return sample.Format(layout) != layout
+}
+
+// dateLayout returns the configured date layout, or the default if it isn't valid
+func (c AppConfig) dateLayout() string {
+	if validLayout(c.DateFormat) {
+		return c.DateFormat
+	}
+	return defaultDateFormat
+}
+
+// dateTimeLayout returns the configured date and time layouts joined for timestamps
+func (c AppConfig) dateTimeLayout() string {
+	timeLayout := defaultTimeFormat
+	if validLayout(c.TimeFormat) {
+		timeLayout = c.TimeFormat
+	}
+	return c.dateLayout() + ", " + timeLayout
+}
+
+// defaultDoneStates returns the states treated as completed when none are configured
+func defaultDoneStates() []string {
+	return []string{"Closed", "Done", "Removed"}
 }
 
 // getConfigDir returns the appropriate config directory for the current OS
@@ -106,9 +221,24 @@ func LoadConfigFile() (AppConfig, error) {
 	}
 
 	// Apply defaults for any zero values (in case config file is from older version)
-	if config.MaxWorkItems == 0 {
+	if config.MaxWorkItems <= 0 {
 		config.MaxWorkItems = 50
 	}
+	if config.MaxWorkItems > maxWorkItemsLimit {
+		config.MaxWorkItems = maxWorkItemsLimit
+	}
+	if config.CompactWidth == 0 {
+		config.CompactWidth = defaultCompactWidth
+	}
+	if len(config.DoneStates) == 0 {
+		config.DoneStates = defaultDoneStates()
+	}
+	if !validLayout(config.DateFormat) {
+		config.DateFormat = defaultDateFormat
+	}
+	if !validLayout(config.TimeFormat) {
+		config.TimeFormat = defaultTimeFormat
+	}
 
 	return config, nil
 }
@@ -140,6 +270,42 @@ func SaveConfigFile(config AppConfig) error {
 	return os.WriteFile(configPath, buf.Bytes(), 0600)
 }
 
+// debugEnvVar enables Azure DevOps request/response logging when set to a non-empty value
+const debugEnvVar = "BORED_DEBUG"
+
+// openDebugLog opens the debug log file in the config directory for appending.
+// The log is never written to stdout so it can't corrupt the TUI.
+func openDebugLog() (*os.File, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(configDir, 0750); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(filepath.Join(configDir, "debug.log"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600) // #nosec G304 -- path is built from the config dir
+}
+
+// debugLogFile is the debug log shared by every client, nil when debug logging is off
+var debugLogFile *os.File
+
+// OpenDebugLog opens the debug log when BORED_DEBUG is set and returns a function that
+// closes it. Debug logging is best-effort, so a log that can't be opened is left off.
+func OpenDebugLog() (closeLog func()) {
+	if os.Getenv(debugEnvVar) == "" {
+		return func() {}
+	}
+	f, err := openDebugLog()
+	if err != nil {
+		return func() {}
+	}
+	debugLogFile = f
+	return func() {
+		debugLogFile = nil
+		_ = f.Close()
+	}
+}
+
 // GetConfigFilePath returns the config file path for display purposes
 func GetConfigFilePath() string {
 	path, err := getConfigFilePath()
@@ -224,9 +390,12 @@ func (m Model) saveConfigFile() (tea.Model, tea.Cmd) {
 	// Parse MaxWorkItems from input
 	maxItemsStr := strings.TrimSpace(m.configFileInputs[0].Value())
 	if maxItemsStr != "" {
-		if val, err := strconv.Atoi(maxItemsStr); err == nil && val > 0 {
-			m.appConfig.MaxWorkItems = val
+		val, err := parseMaxWorkItems(maxItemsStr)
+		if err != nil {
+			m.appConfigMessage = fmt.Sprintf("Error: %v", err)
+			return m, nil
 		}
+		m.appConfig.MaxWorkItems = val
 	}
 
 	// Save to file (skipped in Docker)
@@ -245,6 +414,30 @@ func (m Model) saveConfigFile() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// saveAppConfig writes the config file in the background, for settings changed
+// outside the settings screen. The change applies to this session even if saving fails.
+func (m Model) saveAppConfig() tea.Cmd {
+	if isRunningInDocker() {
+		return nil
+	}
+	config := m.appConfig
+	return func() tea.Msg {
+		return configSavedMsg{err: SaveConfigFile(config)}
+	}
+}
+
+// parseMaxWorkItems validates a Max Work Items value entered in the settings screen
+func parseMaxWorkItems(s string) (int, error) {
+	val, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("max work items must be a whole number, got %q", s)
+	}
+	if val < 1 || val > maxWorkItemsLimit {
+		return 0, fmt.Errorf("max work items must be between 1 and %d, got %d", maxWorkItemsLimit, val)
+	}
+	return val, nil
+}
+
 // viewConfigFile renders the config file screen
 func (m Model) viewConfigFile() string {
 	var b strings.Builder
@@ -266,7 +459,7 @@ func (m Model) viewConfigFile() string {
 	}{
 		{"Default Show All", "Show all work items by default (not just yours)"},
 		{"Enable Notifications", "Play sound when assigned work items change"},
-		{"Max Work Items", "Maximum number of work items to fetch"},
+		{"Max Work Items", "Maximum number of work items to fetch per page (1-500)"},
 	}
 
 	for i, setting := range settings {
@@ -946,3 +946,157 @@ func TestConfigInputFocusCycle(t *testing.T) {
 		t.Errorf("Focus should cycle to 5, got %d", m.configFocus)
 	}
 }
+
+func TestHideCompletedConfigRoundTrip(t *testing.T) {
+	config := DefaultConfig()
+	config.HideCompleted = true
+	config.DoneStates = []string{"Closed", "Resolved"}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(config); err != nil {
+		t.Fatalf("Failed to encode config: %v", err)
+	}
+
+	var loaded AppConfig
+	if _, err := toml.Decode(buf.String(), &loaded); err != nil {
+		t.Fatalf("Failed to decode config: %v", err)
+	}
+
+	if !loaded.HideCompleted {
+		t.Error("HideCompleted should survive a TOML round-trip")
+	}
+	if len(loaded.DoneStates) != 2 || loaded.DoneStates[1] != "Resolved" {
+		t.Errorf("DoneStates = %v, want [Closed Resolved]", loaded.DoneStates)
+	}
+}
+
+func TestDefaultConfigDoneStates(t *testing.T) {
+	config := DefaultConfig()
+
+	if config.HideCompleted {
+		t.Error("HideCompleted should default to false")
+	}
+	want := []string{"Closed", "Done", "Removed"}
+	if len(config.DoneStates) != len(want) {
+		t.Fatalf("DoneStates = %v, want %v", config.DoneStates, want)
+	}
+	for i, state := range want {
+		if config.DoneStates[i] != state {
+			t.Errorf("DoneStates[%d] = %q, want %q", i, config.DoneStates[i], state)
+		}
+	}
+}
+
+func TestParseMaxWorkItems(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{input: "50", want: 50},
+		{input: "1", want: 1},
+		{input: "500", want: 500},
+		{input: "abc", wantErr: true},
+		{input: "12.5", wantErr: true},
+		{input: "0", wantErr: true},
+		{input: "-10", wantErr: true},
+		{input: "501", wantErr: true},
+		{input: "100000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseMaxWorkItems(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMaxWorkItems(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseMaxWorkItems(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveConfigFileRejectsInvalidMaxWorkItems(t *testing.T) {
+	for _, input := range []string{"lots", "0", "-5", "9999"} {
+		m := NewModel()
+		m.view = ViewConfigFile
+		m.appConfig.MaxWorkItems = 50
+		m.configFileInputs[0].SetValue(input)
+
+		newModel, _ := m.saveConfigFile()
+		m = newModel.(Model)
+		if m.appConfig.MaxWorkItems != 50 {
+			t.Errorf("input %q: MaxWorkItems = %d, want unchanged", input, m.appConfig.MaxWorkItems)
+		}
+		if !contains(m.appConfigMessage, "Error") || !contains(m.appConfigMessage, "max work items") {
+			t.Errorf("input %q: message = %q, want a validation error", input, m.appConfigMessage)
+		}
+	}
+
+	m := NewModel()
+	m.configFileInputs[0].SetValue("200")
+	newModel, _ := m.saveConfigFile()
+	if got := newModel.(Model).appConfig.MaxWorkItems; got != 200 {
+		t.Errorf("MaxWorkItems = %d, want a valid value to be applied", got)
+	}
+}
+
+func TestValidLayout(t *testing.T) {
+	tests := []struct {
+		layout string
+		want   bool
+	}{
+		{"Jan 02", true},
+		{"2006-01-02", true},
+		{"15:04", true},
+		{"3:04PM", true},
+		{"", false},
+		{"  ", false},
+		{"yyyy-mm-dd", false},
+	}
+	for _, tt := range tests {
+		if got := validLayout(tt.layout); got != tt.want {
+			t.Errorf("validLayout(%q) = %v, want %v", tt.layout, got, tt.want)
+		}
+	}
+}
+
+func TestCommentDateFormats(t *testing.T) {
+	m := NewModel()
+	m.view = ViewDetail
+	m.width = 100
+	m.height = 50
+	m.client = azdo.NewClient("testorg", "testproject", "testteam", "", "pat")
+	m.selectedItem = &azdo.WorkItem{
+		ID:     123,
+		Fields: azdo.WorkItemFields{Title: "Test", State: "Active", WorkItemType: "Bug"},
+	}
+	m.comments = []azdo.Comment{
+		{ID: 1, Text: "Test comment", CreatedDate: "2024-03-05T14:30:00Z", CreatedBy: azdo.IdentityRef{DisplayName: "User"}},
+	}
+
+	m.appConfig = DefaultConfig()
+	m.commentsExpanded = true
+	if output := m.viewDetail(); !contains(output, "User - Mar 05, 14:30") {
+		t.Errorf("Expected the default timestamp, got:\n%s", output)
+	}
+
+	m.appConfig.DateFormat = "2006-01-02"
+	m.appConfig.TimeFormat = "3:04PM"
+	if output := m.viewDetail(); !contains(output, "User - 2024-03-05, 2:30PM") {
+		t.Errorf("Expected the custom timestamp, got:\n%s", output)
+	}
+	m.commentsExpanded = false
+	if output := m.viewDetail(); !contains(output, "Latest: User (2024-03-05)") {
+		t.Errorf("Expected the custom date in the summary, got:\n%s", output)
+	}
+
+	// Layouts without any date or time verbs fall back to the defaults
+	m.appConfig.DateFormat = "dd/mm/yyyy"
+	m.appConfig.TimeFormat = "hh:mm"
+	m.commentsExpanded = true
+	if output := m.viewDetail(); !contains(output, "User - Mar 05, 14:30") {
+		t.Errorf("Expected invalid layouts to fall back to the defaults, got:\n%s", output)
+	}
+}
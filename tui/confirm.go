@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pendingWrite is a write to Azure DevOps held back until the user confirms it
+type pendingWrite struct {
+	description string  // question shown in the prompt (e.g. "Save changes to #42?")
+	cmd         tea.Cmd // command that performs the write
+}
+
+// confirmWrite dispatches a write, or holds it for a y/n answer when confirm_writes is on.
+// Callers set loading before calling; it is cleared again while the write waits.
+// Deletes and unlinks already ask for confirmation and don't go through here.
+func (m Model) confirmWrite(description string, cmd tea.Cmd) (Model, tea.Cmd) {
+	if !m.appConfig.ConfirmWrites || cmd == nil {
+		return m, cmd
+	}
+	m.pendingWrite = &pendingWrite{description: description, cmd: cmd}
+	m.loading = false
+	return m, nil
+}
+
+// updatePendingWrite handles key presses while a write waits for confirmation
+func (m Model) updatePendingWrite(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		cmd := m.pendingWrite.cmd
+		m.pendingWrite = nil
+		m.loading = true
+		m.err = nil
+		return m, cmd
+	case "n", "N", "esc":
+		m.pendingWrite = nil
+		m.bulkPending = 0 // a cancelled bulk action never starts
+		m.message = "Cancelled"
+	}
+	return m, nil
+}
+
+// renderPendingWrite renders the confirmation prompt for a pending write
+func (m Model) renderPendingWrite() string {
+	promptStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("214")).
+		Padding(0, 1)
+	return promptStyle.Render(m.pendingWrite.description + "\n\ny: confirm • n/esc: cancel")
+}
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestConfirmWritesPromptsBeforeSave(t *testing.T) {
+	m := setupDetailModel()
+	m.appConfig.ConfirmWrites = true
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+	if cmd != nil {
+		t.Fatal("Expected ctrl+s to wait for confirmation instead of saving")
+	}
+	if m.pendingWrite == nil || m.loading {
+		t.Fatal("Expected a pending write and no loading indicator")
+	}
+	if !strings.Contains(m.View(), "Save changes to #1?") {
+		t.Error("Expected the confirmation prompt in the view")
+	}
+
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("Expected y to dispatch the save")
+	}
+	if m.pendingWrite != nil || !m.loading {
+		t.Error("Expected the pending write to be cleared and loading to start")
+	}
+}
+
+func TestConfirmWritesCancel(t *testing.T) {
+	m := setupDetailModel()
+	m.appConfig.ConfirmWrites = true
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if cmd != nil || m.pendingWrite != nil {
+		t.Error("Expected esc to drop the pending write without dispatching it")
+	}
+	if m.view != ViewDetail {
+		t.Error("Expected esc to cancel the write rather than leave the detail view")
+	}
+	if m.message != "Cancelled" {
+		t.Errorf("Expected a cancelled message, got %q", m.message)
+	}
+}
+
+func TestConfirmWritesOffDispatchesImmediately(t *testing.T) {
+	m := setupDetailModel()
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+	if cmd == nil || m.pendingWrite != nil {
+		t.Error("Expected ctrl+s to save straight away when confirm_writes is off")
+	}
+}
+
+func TestConfirmWritesOtherKeysKeepPrompt(t *testing.T) {
+	m := setupBoardModel()
+	m.appConfig.ConfirmWrites = true
+	m.username = "me@example.com"
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	m = updated.(Model)
+	if m.pendingWrite == nil {
+		t.Fatal("Expected assign to me to wait for confirmation")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	m = updated.(Model)
+	if cmd != nil || m.pendingWrite == nil || m.cursor != 0 {
+		t.Error("Expected other keys to be ignored while the prompt is open")
+	}
+}
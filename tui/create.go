@@ -2,8 +2,11 @@ package tui
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/laupski/bored/azdo"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -35,7 +38,7 @@ func (m Model) updateCreate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			if m.createInputs[0].Value() != "" {
 				m.loading = true
-				return m, m.createWorkItem()
+				return m.confirmWrite(fmt.Sprintf("Create %s %q?", m.workItemTypes[m.createType], m.createInputs[0].Value()), m.createWorkItem())
 			}
 		}
 	}
@@ -44,6 +47,37 @@ func (m Model) updateCreate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// startClone opens the create form pre-filled from an existing work item
+func (m Model) startClone(wi *azdo.WorkItem) Model {
+	m.view = ViewCreate
+	m.createFocus = 0
+	m.err = nil
+	m.message = ""
+
+	m.createInputs[0].SetValue("Copy of " + wi.Fields.Title)
+	m.createInputs[1].SetValue(wi.Fields.Description)
+	m.createInputs[2].SetValue("")
+	if wi.Fields.Priority != nil && *wi.Fields.Priority > 0 {
+		m.createInputs[2].SetValue(strconv.Itoa(*wi.Fields.Priority))
+	}
+	m.createInputs[3].SetValue(m.username)
+	m.createInputs[0].Focus()
+	for i := 1; i < len(m.createInputs); i++ {
+		m.createInputs[i].Blur()
+	}
+
+	m.createType = 0
+	for i, t := range m.workItemTypes {
+		if t == wi.Fields.WorkItemType {
+			m.createType = i
+			break
+		}
+	}
+	m.cloneAreaPath = wi.Fields.AreaPath
+	m.cloneTags = wi.Fields.Tags
+	return m
+}
+
 func (m *Model) updateCreateFocus() tea.Cmd {
 	cmds := make([]tea.Cmd, len(m.createInputs))
 	for i := range m.createInputs {
@@ -59,14 +93,26 @@ func (m *Model) updateCreateFocus() tea.Cmd {
 func (m *Model) updateCreateInputs(msg tea.Msg) tea.Cmd {
 	cmds := make([]tea.Cmd, len(m.createInputs))
 	for i := range m.createInputs {
-		m.createInputs[i], cmds[i] = m.createInputs[i].Update(msg)
+		key := msg
+		if i == 2 {
+			// Priority is a whole number
+			var ok bool
+			if key, ok = numericKey(msg, m.createInputs[i].Value(), false); !ok {
+				continue
+			}
+		}
+		m.createInputs[i], cmds[i] = m.createInputs[i].Update(key)
 	}
 	return tea.Batch(cmds...)
 }
 
+// createFieldRefs are the field reference names of the free-text create inputs, in order
+var createFieldRefs = []string{"System.Title", "System.Description"}
+
 func (m Model) viewCreate() string {
 	var b strings.Builder
 
+	b.WriteString(m.renderConnectionBanner())
 	title := titleStyle.Render("✨ Create Work Item")
 	b.WriteString(title)
 	b.WriteString("\n\n")
@@ -81,6 +127,10 @@ func (m Model) viewCreate() string {
 		b.WriteString(style.Render(label))
 		b.WriteString("\n")
 		b.WriteString(m.createInputs[i].View())
+		if i == m.createFocus && i < len(createFieldRefs) {
+			b.WriteString("\n")
+			b.WriteString(renderCharCount(m.createInputs[i].Value(), createFieldRefs[i]))
+		}
 		b.WriteString("\n\n")
 	}
 
@@ -102,8 +152,22 @@ func (m Model) viewCreate() string {
 	b.WriteString(strings.Join(types, " "))
 	b.WriteString("\n\n")
 
+	// Show area path and tags carried over from a cloned item
+	if m.cloneAreaPath != "" {
+		b.WriteString(labelStyle.Render("Area Path"))
+		b.WriteString("\n")
+		b.WriteString(normalStyle.Foreground(lipgloss.Color("39")).Render(m.cloneAreaPath))
+		b.WriteString("\n\n")
+	}
+	if m.cloneTags != "" {
+		b.WriteString(labelStyle.Render("Tags"))
+		b.WriteString("\n")
+		b.WriteString(normalStyle.Foreground(lipgloss.Color("39")).Render(m.cloneTags))
+		b.WriteString("\n\n")
+	}
+
 	// Show configured area path
-	if m.client != nil && m.client.AreaPath != "" {
+	if m.cloneAreaPath == "" && m.client != nil && m.client.AreaPath != "" {
 		b.WriteString(labelStyle.Render("Area Path"))
 		b.WriteString("\n")
 		b.WriteString(normalStyle.Foreground(lipgloss.Color("39")).Render(m.client.AreaPath))
@@ -111,7 +175,12 @@ func (m Model) viewCreate() string {
 	}
 
 	if m.err != nil {
-		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString(m.renderError())
+		b.WriteString("\n\n")
+	}
+
+	if m.confirmingEsc {
+		b.WriteString(staleStyle.Render(m.message))
 		b.WriteString("\n\n")
 	}
 
@@ -120,7 +189,16 @@ func (m Model) viewCreate() string {
 		b.WriteString("\n\n")
 	}
 
-	b.WriteString(helpStyle.Render("tab/↑↓: navigate • ←→: change type • enter: create • esc: cancel"))
+	b.WriteString(helpStyle.Render("tab/↑↓: navigate • ←→: change type • enter: create • " + m.escHelp("cancel")))
 
 	return boxStyle.Render(b.String())
 }
+
+// escHelp describes leaving the create or detail view with esc. With confirm_esc
+// on, esc has to be pressed twice and ctrl+b leaves at once.
+func (m Model) escHelp(action string) string {
+	if m.appConfig.ConfirmEsc {
+		return "esc twice/ctrl+b: board"
+	}
+	return "esc: " + action
+}
@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Credential store names accepted by the credential_store config option
+const (
+	credentialStoreKeychain = "keychain"
+	credentialStoreFile     = "file"
+)
+
+const (
+	credentialsFileName     = "credentials.json"
+	credentialKDFIterations = 600000 // PBKDF2-SHA256 iterations used to derive the PAT key
+	credentialSaltSize      = 16
+	credentialKeySize       = 32 // AES-256
+)
+
+// errWrongPassphrase is returned when the stored PAT can't be decrypted
+var errWrongPassphrase = errors.New("wrong passphrase or corrupted credentials file")
+
+// credentialsFile is the on-disk format of the file credential store.
+// Only the PAT is secret, so it is the only field that is encrypted.
+type credentialsFile struct {
+	Organization string `json:"organization"`
+	Project      string `json:"project"`
+	Team         string `json:"team"`
+	AreaPath     string `json:"area_path"`
+	Username     string `json:"username"`
+	Salt         []byte `json:"salt"`
+	Nonce        []byte `json:"nonce"`
+	EncryptedPAT []byte `json:"encrypted_pat"`
+}
+
+// credentialsFilePath returns the path of the file credential store
+func credentialsFilePath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, credentialsFileName), nil
+}
+
+// newCredentialsFile builds a credentials file with the PAT encrypted under passphrase
+func newCredentialsFile(creds storedCredentials, passphrase string) (credentialsFile, error) {
+	if passphrase == "" {
+		return credentialsFile{}, fmt.Errorf("a passphrase is required to encrypt the PAT")
+	}
+	salt := make([]byte, credentialSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return credentialsFile{}, err
+	}
+	gcm, err := credentialCipher(passphrase, salt)
+	if err != nil {
+		return credentialsFile{}, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return credentialsFile{}, err
+	}
+
+	return credentialsFile{
+		Organization: creds.org,
+		Project:      creds.project,
+		Team:         creds.team,
+		AreaPath:     creds.areaPath,
+		Username:     creds.username,
+		Salt:         salt,
+		Nonce:        nonce,
+		EncryptedPAT: gcm.Seal(nil, nonce, []byte(creds.pat), nil),
+	}, nil
+}
+
+// unlock decrypts the stored PAT with passphrase
+func (f credentialsFile) unlock(passphrase string) (string, error) {
+	gcm, err := credentialCipher(passphrase, f.Salt)
+	if err != nil {
+		return "", err
+	}
+	if len(f.Nonce) != gcm.NonceSize() {
+		return "", errWrongPassphrase
+	}
+	pat, err := gcm.Open(nil, f.Nonce, f.EncryptedPAT, nil)
+	if err != nil {
+		return "", errWrongPassphrase
+	}
+	return string(pat), nil
+}
+
+// credentialCipher derives an AES-GCM cipher from a passphrase and salt
+func credentialCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, credentialKDFIterations, credentialKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// writeCredentialsFile saves a credentials file to path, readable only by the user
+func writeCredentialsFile(path string, f credentialsFile) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// readCredentialsFile loads a credentials file from path
+func readCredentialsFile(path string) (credentialsFile, error) {
+	var f credentialsFile
+	data, err := os.ReadFile(path) // #nosec G304 -- path is built from the config dir
+	if err != nil {
+		return f, err
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// saveFileCredentials encrypts and saves credentials to the file store
+func saveFileCredentials(creds storedCredentials, passphrase string) error {
+	if isRunningInDocker() {
+		return nil
+	}
+	path, err := credentialsFilePath()
+	if err != nil {
+		return err
+	}
+	f, err := newCredentialsFile(creds, passphrase)
+	if err != nil {
+		return err
+	}
+	return writeCredentialsFile(path, f)
+}
+
+// loadFileCredentials loads the file store; found is false when nothing is saved
+func loadFileCredentials() (f credentialsFile, found bool, err error) {
+	if isRunningInDocker() {
+		return credentialsFile{}, false, nil
+	}
+	path, err := credentialsFilePath()
+	if err != nil {
+		return credentialsFile{}, false, err
+	}
+	f, err = readCredentialsFile(path)
+	if os.IsNotExist(err) {
+		return credentialsFile{}, false, nil
+	}
+	if err != nil {
+		return credentialsFile{}, false, err
+	}
+	return f, true, nil
+}
+
+// clearFileCredentials removes the file store
+func clearFileCredentials() error {
+	if isRunningInDocker() {
+		return nil
+	}
+	path, err := credentialsFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
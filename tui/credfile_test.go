@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testStoredCredentials() storedCredentials {
+	return storedCredentials{
+		org:      "myorg",
+		project:  "MyProject",
+		team:     "MyTeam",
+		areaPath: "MyProject\\MyTeam",
+		pat:      "s3cr3t-pat-value",
+		username: "me@example.com",
+	}
+}
+
+func TestCredentialsFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bored", credentialsFileName)
+
+	f, err := newCredentialsFile(testStoredCredentials(), "correct horse")
+	if err != nil {
+		t.Fatalf("newCredentialsFile failed: %v", err)
+	}
+	if err := writeCredentialsFile(path, f); err != nil {
+		t.Fatalf("writeCredentialsFile failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading credentials file failed: %v", err)
+	}
+	if bytes.Contains(data, []byte("s3cr3t-pat-value")) {
+		t.Error("credentials file should not contain the plaintext PAT")
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode().Perm() != 0600 {
+		t.Errorf("credentials file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	loaded, err := readCredentialsFile(path)
+	if err != nil {
+		t.Fatalf("readCredentialsFile failed: %v", err)
+	}
+	if loaded.Organization != "myorg" || loaded.AreaPath != "MyProject\\MyTeam" || loaded.Username != "me@example.com" {
+		t.Errorf("loaded = %+v, want the saved fields", loaded)
+	}
+	pat, err := loaded.unlock("correct horse")
+	if err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+	if pat != "s3cr3t-pat-value" {
+		t.Errorf("unlock() = %q, want the original PAT", pat)
+	}
+}
+
+func TestCredentialsFileWrongPassphrase(t *testing.T) {
+	f, err := newCredentialsFile(testStoredCredentials(), "correct horse")
+	if err != nil {
+		t.Fatalf("newCredentialsFile failed: %v", err)
+	}
+	if _, err := f.unlock("battery staple"); err != errWrongPassphrase {
+		t.Errorf("unlock with wrong passphrase error = %v, want errWrongPassphrase", err)
+	}
+
+	f.EncryptedPAT[0] ^= 0xff
+	if _, err := f.unlock("correct horse"); err != errWrongPassphrase {
+		t.Errorf("unlock of tampered data error = %v, want errWrongPassphrase", err)
+	}
+}
+
+func TestCredentialsFileRequiresPassphrase(t *testing.T) {
+	if _, err := newCredentialsFile(testStoredCredentials(), ""); err == nil {
+		t.Error("expected an error encrypting without a passphrase")
+	}
+}
+
+func TestConfigFileStorePromptsForPassphrase(t *testing.T) {
+	f, err := newCredentialsFile(testStoredCredentials(), "correct horse")
+	if err != nil {
+		t.Fatalf("newCredentialsFile failed: %v", err)
+	}
+
+	m := NewModel()
+	m.appConfig.CredentialStore = credentialStoreFile
+	m.fileCreds = &f
+	m.configInputs[0].SetValue(f.Organization)
+	m.configInputs[1].SetValue(f.Project)
+	m.configInputs[2].SetValue(f.Team)
+	m.configInputs[3].SetValue(f.AreaPath)
+	m.configInputs[5].SetValue(f.Username)
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if !m.promptingPassphrase {
+		t.Fatal("enter should ask for the passphrase with the file store")
+	}
+	if !contains(m.viewConfig(), "unlocks your saved PAT") {
+		t.Error("config screen should show the passphrase prompt")
+	}
+
+	// A wrong passphrase keeps the prompt open
+	m.passphraseInput.SetValue("battery staple")
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd != nil || !m.promptingPassphrase || m.err == nil {
+		t.Error("wrong passphrase should show an error without connecting")
+	}
+
+	m.passphraseInput.SetValue("correct horse")
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd == nil || !m.loading || m.promptingPassphrase {
+		t.Fatal("correct passphrase should connect")
+	}
+	if m.configInputs[4].Value() != "s3cr3t-pat-value" {
+		t.Errorf("PAT input = %q, want the decrypted PAT", m.configInputs[4].Value())
+	}
+	if m.client == nil || m.client.Organization != "myorg" {
+		t.Error("client should be built from the loaded credentials")
+	}
+}
@@ -0,0 +1,216 @@
+package tui
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// importColumns are the CSV columns read by the import; type and title are required
+var importColumns = []string{"type", "title", "description", "priority", "assignee", "tags"}
+
+// importRow is one work item to create from a CSV row
+type importRow struct {
+	line         int // line in the file, for error messages
+	workItemType string
+	title        string
+	description  string
+	priority     int
+	assignee     string
+	tags         string
+}
+
+// parseImportCSV reads work items to create from CSV with a header row naming the columns
+// (in any order, case-insensitively). Every row is checked before anything is created:
+// the type must be one of types, the title is required and the priority must be 1-4
+// (blank means 2). All problems are returned together.
+func parseImportCSV(r io.Reader, types []string) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("the file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		for _, col := range importColumns {
+			if name == col {
+				columns[col] = i
+			}
+		}
+	}
+	var missing []string
+	for _, col := range importColumns[:2] {
+		if _, ok := columns[col]; !ok {
+			missing = append(missing, col)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required column(s): %s", strings.Join(missing, ", "))
+	}
+
+	var rows []importRow
+	var problems []string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue // a row of empty cells
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(col string) string {
+			if i, ok := columns[col]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := importRow{
+			line:        line,
+			title:       field("title"),
+			description: field("description"),
+			priority:    2,
+			assignee:    field("assignee"),
+			tags:        field("tags"),
+		}
+		if row.title == "" {
+			problems = append(problems, fmt.Sprintf("line %d: title is required", line))
+		}
+		wiType, ok := allowedValue(types, field("type"))
+		if field("type") == "" || !ok {
+			problems = append(problems, fmt.Sprintf("line %d: unknown type %q", line, field("type")))
+		}
+		row.workItemType = wiType
+		if p := field("priority"); p != "" {
+			priority, err := strconv.Atoi(p)
+			if err != nil || priority < 1 || priority > 4 {
+				problems = append(problems, fmt.Sprintf("line %d: priority %q isn't 1-4", line, p))
+			}
+			row.priority = priority
+		}
+		rows = append(rows, row)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%d problem(s) in the file: %s", len(problems), strings.Join(problems, "; "))
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the file has no work items")
+	}
+	return rows, nil
+}
+
+// readImportFile reads and checks the work items in a CSV file
+func readImportFile(path string, types []string) ([]importRow, error) {
+	f, err := os.Open(path) // #nosec G304 -- the user chooses the file to import
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return parseImportCSV(f, types)
+}
+
+// importWorkItems creates the work item for the first row, then adds its tags.
+// The result carries the remaining rows, so rows are imported one at a time in file order.
+func (m Model) importWorkItems(rows []importRow) tea.Cmd {
+	if len(rows) == 0 {
+		return nil
+	}
+	row, rest := rows[0], rows[1:]
+	return func() tea.Msg {
+		item, err := m.client.CreateWorkItemWithAssignee(row.workItemType, row.title, row.description, row.priority, row.assignee)
+		if err == nil && row.tags != "" {
+			// Tags aren't part of the create call; the item exists even if tagging fails
+			if _, tagErr := m.client.UpdateTags(item.ID, row.tags); tagErr != nil {
+				err = fmt.Errorf("created #%d but couldn't add tags: %w", item.ID, tagErr)
+			}
+		}
+		return importResultMsg{line: row.line, err: err, rest: rest}
+	}
+}
+
+// handleImportResult records one row's outcome and reports the totals once all rows have finished
+func (m Model) handleImportResult(msg importResultMsg) (tea.Model, tea.Cmd) {
+	m.bulkDone++
+	if msg.err != nil {
+		m.bulkFailed++
+		m.importErrors = append(m.importErrors, fmt.Sprintf("line %d: %v", msg.line, msg.err))
+	}
+	if m.bulkDone < m.bulkPending {
+		progress := bulkProgressMsg{done: m.bulkDone, failed: m.bulkFailed, total: m.bulkPending}
+		return m, tea.Batch(func() tea.Msg { return progress }, m.importWorkItems(msg.rest))
+	}
+
+	m.loading = false
+	m.message = fmt.Sprintf("Imported %d of %d work items", m.bulkDone-m.bulkFailed, m.bulkPending)
+	if len(m.importErrors) > 0 {
+		m.err = fmt.Errorf("%d row(s) failed: %s", len(m.importErrors), strings.Join(m.importErrors, "; "))
+	}
+	m.bulkPending = 0
+	m.bulkDone = 0
+	m.bulkFailed = 0
+	m.bulkProgress = bulkProgressMsg{}
+	m.importErrors = nil
+	// Show the new items
+	return m, m.fetchWorkItemsPage(m.apiPage)
+}
+
+// updateImportPrompt handles key presses while entering the CSV file to import
+func (m Model) updateImportPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.importing = false
+		m.importPathInput = ""
+		return m, nil
+	case "enter":
+		path := strings.TrimSpace(m.importPathInput)
+		if path == "" {
+			return m, nil
+		}
+		m.importing = false
+		m.importPathInput = ""
+		rows, err := readImportFile(path, m.workItemTypes)
+		if err != nil {
+			m.err = fmt.Errorf("can't import %s: %w", path, err)
+			return m, nil
+		}
+		m.loading = true
+		m.err = nil
+		m.message = ""
+		m.bulkLabel = "Importing"
+		m.bulkPending = len(rows)
+		m.bulkDone = 0
+		m.bulkFailed = 0
+		m.bulkProgress = bulkProgressMsg{total: len(rows)}
+		m.importErrors = nil
+		return m.confirmWrite(fmt.Sprintf("Create %d work items from %s?", len(rows), path), m.importWorkItems(rows))
+	case "backspace":
+		if len(m.importPathInput) > 0 {
+			runes := []rune(m.importPathInput)
+			m.importPathInput = string(runes[:len(runes)-1])
+		}
+		return m, nil
+	case " ":
+		m.importPathInput += " "
+		return m, nil
+	}
+	if msg.Type == tea.KeyRunes {
+		m.importPathInput += string(msg.Runes)
+	}
+	return m, nil
+}
@@ -0,0 +1,171 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var testImportTypes = []string{"Bug", "Task", "User Story"}
+
+func TestParseImportCSV(t *testing.T) {
+	input := "Title,Type,Priority,Assignee,Tags,Description\n" +
+		"Fix login,bug,1,pat@example.com,\"auth; urgent\",Users can't log in\n" +
+		",,,,,\n" +
+		"Write docs,Task,,,,\n"
+
+	rows, err := parseImportCSV(strings.NewReader(input), testImportTypes)
+	if err != nil {
+		t.Fatalf("parseImportCSV failed: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows with the blank one skipped, got %d", len(rows))
+	}
+	want := importRow{line: 2, workItemType: "Bug", title: "Fix login", description: "Users can't log in", priority: 1, assignee: "pat@example.com", tags: "auth; urgent"}
+	if rows[0] != want {
+		t.Errorf("rows[0] = %+v, want %+v", rows[0], want)
+	}
+	if rows[1].line != 4 || rows[1].workItemType != "Task" || rows[1].priority != 2 {
+		t.Errorf("Expected the second row to default to priority 2, got %+v", rows[1])
+	}
+}
+
+func TestParseImportCSVValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"empty", "", []string{"the file is empty"}},
+		{"missing columns", "Description,Tags\nx,y\n", []string{"missing required column(s): type, title"}},
+		{"no rows", "Type,Title\n", []string{"no work items"}},
+		{
+			"bad rows",
+			"Type,Title,Priority\nEpic,Plan,2\nBug,,1\nTask,Docs,9\n",
+			[]string{"3 problem(s)", `line 2: unknown type "Epic"`, "line 3: title is required", `line 4: priority "9" isn't 1-4`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseImportCSV(strings.NewReader(tt.input), testImportTypes)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected %q in %q", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestImportResultsAggregateErrors(t *testing.T) {
+	m := setupBoardModel()
+	m.loading = true
+	m.bulkLabel = "Importing"
+	m.bulkPending = 3
+	m.bulkProgress = bulkProgressMsg{total: 3}
+
+	newModel, _ := m.Update(importResultMsg{line: 2})
+	m = newModel.(Model)
+	newModel, _ = m.Update(importResultMsg{line: 3, err: errors.New("API error 400: bad assignee")})
+	m = newModel.(Model)
+	if !m.loading || m.err != nil {
+		t.Fatal("Expected to wait for every row before reporting")
+	}
+	newModel, cmd := m.Update(importResultMsg{line: 4, err: errors.New("API error 500: oops")})
+	m = newModel.(Model)
+
+	if m.loading || cmd == nil {
+		t.Error("Expected the import to finish and refresh the board")
+	}
+	if m.message != "Imported 1 of 3 work items" {
+		t.Errorf("message = %q", m.message)
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "2 row(s) failed") ||
+		!strings.Contains(m.err.Error(), "line 3: API error 400: bad assignee") ||
+		!strings.Contains(m.err.Error(), "line 4: API error 500: oops") {
+		t.Errorf("Expected each failed row to be reported, got %v", m.err)
+	}
+	if m.importErrors != nil || m.bulkPending != 0 {
+		t.Error("Expected the import state to be reset")
+	}
+}
+
+func TestBoardImportCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "items.csv")
+	if err := os.WriteFile(path, []byte("type,title,tags\nBug,Imported bug,triage\nTask,Imported task,\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var created []string
+	var tagged []string
+	m := setupBoardModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == "POST" && strings.Contains(r.URL.Path, "/workitems/$"):
+			created = append(created, r.URL.Path)
+			_ = json.NewEncoder(w).Encode(azdo.WorkItem{ID: 100 + len(created)})
+		case r.Method == "PATCH":
+			tagged = append(tagged, r.URL.Path)
+			_ = json.NewEncoder(w).Encode(azdo.WorkItem{ID: 101})
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'U'}})
+	m = newModel.(Model)
+	if !m.importing {
+		t.Fatal("Expected U to open the import prompt")
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(path)})
+	m = newModel.(Model)
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd == nil || m.bulkPending != 2 {
+		t.Fatalf("Expected the import to start, err=%v", m.err)
+	}
+
+	// Rows are created one at a time, each result starting the next row
+	for i := range 2 {
+		var result importResultMsg
+		switch msg := cmd().(type) {
+		case importResultMsg:
+			result = msg
+		case tea.BatchMsg:
+			for _, c := range msg {
+				if r, ok := c().(importResultMsg); ok {
+					result = r
+				}
+			}
+		}
+		mu.Lock()
+		if len(created) != i+1 {
+			t.Errorf("Expected row %d to be created before the next starts, got %v", i+1, created)
+		}
+		mu.Unlock()
+		newModel, cmd = m.Update(result)
+		m = newModel.(Model)
+	}
+	if len(created) != 2 || len(tagged) != 1 {
+		t.Errorf("Expected 2 creates and 1 tag update, got %v and %v", created, tagged)
+	}
+	if m.message != "Imported 2 of 2 work items" || m.err != nil {
+		t.Errorf("message = %q, err = %v", m.message, m.err)
+	}
+}
@@ -4,12 +4,17 @@ import (
 	"fmt"
 
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/atotto/clipboard"
 	"github.com/laupski/bored/azdo"
 
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -134,6 +139,20 @@ func stripHTMLTags(text string, orgURL string) string {
 func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Only browsing is possible while showing the offline cache; comment
+		// scrolling keys are allowed only while they scroll
+		if m.offline && !offlineDetailKeys[msg.String()] &&
+			!(m.commentsExpanded && (msg.String() == "ctrl+n" || msg.String() == "ctrl+p")) {
+			m.message = offlineReadOnlyMessage
+			return m, nil
+		}
+
+		// Collapse every expanded section at once and return to field editing
+		if msg.String() == "ctrl+k" && !m.creatingRelated && !m.linkingExisting && !m.addingHyperlink && !m.tagEditing {
+			m.collapseDetailSections()
+			return m, m.updateDetailFocus()
+		}
+
 		// Handle planning edit mode
 		if m.planningExpanded {
 			fieldCount := len(m.planningFields)
@@ -155,7 +174,8 @@ func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, m.updatePlanningFocus()
 			case "enter":
 				// Save planning fields dynamically
-				return m, m.savePlanningFieldsDynamic()
+				cmd := m.savePlanningFieldsDynamic()
+				return m.confirmWrite(fmt.Sprintf("Save planning fields on #%d?", m.selectedItem.ID), cmd)
 			}
 			// Update the focused planning input
 			cmd := m.updatePlanningInputs(msg)
@@ -184,7 +204,8 @@ func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 					displayOrder := m.getIterationDisplayOrder()
 					if m.iterationCursor < len(displayOrder) {
 						m.loading = true
-						return m, m.updateIteration(m.selectedItem.ID, displayOrder[m.iterationCursor].Path)
+						iteration := displayOrder[m.iterationCursor]
+						return m.confirmWrite(fmt.Sprintf("Move #%d to %s?", m.selectedItem.ID, iteration.Name), m.updateIteration(m.selectedItem.ID, iteration.Path))
 					}
 				}
 				return m, nil
@@ -192,64 +213,145 @@ func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		// Handle create related mode input
-		if m.creatingRelated {
+		// Handle state menu
+		if m.stateMenuExpanded {
+			choices := m.stateMenuChoices()
 			switch msg.String() {
-			case "esc":
-				m.creatingRelated = false
-				m.createRelatedTitle = ""
+			case "esc", "alt+m":
+				m.stateMenuExpanded = false
+				return m, nil
+			case "up", "k":
+				if m.stateMenuCursor > 0 {
+					m.stateMenuCursor--
+				}
+				return m, nil
+			case "down", "j":
+				if m.stateMenuCursor < len(choices)-1 {
+					m.stateMenuCursor++
+				}
 				return m, nil
 			case "enter":
-				if m.createRelatedTitle != "" {
-					wiType := "Task"
-					if m.createRelatedType < len(m.workItemTypes) {
-						wiType = m.workItemTypes[m.createRelatedType]
+				if m.stateMenuCursor < len(choices) {
+					state := choices[m.stateMenuCursor]
+					if strings.EqualFold(state, m.selectedItem.Fields.State) {
+						m.stateMenuExpanded = false
+						return m, nil
 					}
 					m.loading = true
-					m.creatingRelated = false
-					return m, m.createRelatedItem(m.selectedItem.ID, m.createRelatedAsChild, m.createRelatedTitle, wiType, m.createRelatedAssignee)
+					return m.confirmWrite(fmt.Sprintf("Set the state of #%d to %s?", m.selectedItem.ID, state), m.updateState(m.selectedItem.ID, state))
 				}
 				return m, nil
-			case "tab":
-				// Toggle between title and assignee fields
-				m.createRelatedFocus = (m.createRelatedFocus + 1) % 2
+			}
+			return m, nil
+		}
+
+		// Handle area selection mode
+		if m.activityExpanded {
+			switch msg.String() {
+			case "esc", "alt+a":
+				m.activityExpanded = false
 				return m, nil
-			case "left":
-				if m.createRelatedType > 0 {
-					m.createRelatedType--
-				} else {
-					m.createRelatedType = len(m.workItemTypes) - 1
+			case "up", "k":
+				if m.activityCursor > 0 {
+					m.activityCursor--
+				}
+				return m, nil
+			case "down", "j":
+				if m.activityCursor < len(m.activities)-1 {
+					m.activityCursor++
 				}
 				return m, nil
-			case "right":
-				m.createRelatedType = (m.createRelatedType + 1) % len(m.workItemTypes)
+			case "enter":
+				if m.activityCursor < len(m.activities) {
+					m.loading = true
+					activity := m.activities[m.activityCursor]
+					return m.confirmWrite(fmt.Sprintf("Set the activity of #%d to %s?", m.selectedItem.ID, activity), m.updateActivity(m.selectedItem.ID, activity))
+				}
+				return m, nil
+			}
+			return m, nil
+		}
+
+		if m.areaExpanded {
+			switch msg.String() {
+			case "esc", "ctrl+o":
+				m.areaExpanded = false
+				return m, nil
+			case "up":
+				if m.areaCursor > 0 {
+					m.areaCursor--
+				}
+				return m, nil
+			case "down":
+				if m.areaCursor < len(m.areas)-1 {
+					m.areaCursor++
+				}
+				return m, nil
+			case "enter":
+				if m.areaCursor < len(m.areas) {
+					m.loading = true
+					area := m.areas[m.areaCursor]
+					return m.confirmWrite(fmt.Sprintf("Move #%d to area %s?", m.selectedItem.ID, area), m.updateArea(m.selectedItem.ID, area))
+				}
+				return m, nil
+			}
+			return m, nil
+		}
+
+		// Handle create related mode input
+		if m.creatingRelated {
+			return m.updateCreateRelated(msg)
+		}
+
+		// Handle link existing item mode input
+		if m.linkingExisting {
+			switch msg.String() {
+			case "esc":
+				m.linkingExisting = false
+				m.linkExistingID = ""
+				return m, nil
+			case "enter":
+				targetID, err := strconv.Atoi(strings.TrimSpace(m.linkExistingID))
+				if err != nil || targetID <= 0 {
+					m.err = fmt.Errorf("invalid work item ID: %q", m.linkExistingID)
+					return m, nil
+				}
+				if targetID == m.selectedItem.ID {
+					m.err = fmt.Errorf("cannot link work item #%d to itself", targetID)
+					return m, nil
+				}
+				m.err = nil
+				m.loading = true
+				m.linkingExisting = false
+				m.linkExistingID = ""
+				relation := "parent"
+				if m.linkExistingAsChild {
+					relation = "child"
+				}
+				return m.confirmWrite(fmt.Sprintf("Link #%d as a %s of #%d?", targetID, relation, m.selectedItem.ID), m.linkExistingItem(m.selectedItem.ID, targetID, m.linkExistingAsChild))
+			case "tab":
+				// Toggle between linking as child and as parent
+				m.linkExistingAsChild = !m.linkExistingAsChild
 				return m, nil
 			case "backspace":
-				if m.createRelatedFocus == 0 && len(m.createRelatedTitle) > 0 {
-					m.createRelatedTitle = m.createRelatedTitle[:len(m.createRelatedTitle)-1]
-				} else if m.createRelatedFocus == 1 && len(m.createRelatedAssignee) > 0 {
-					m.createRelatedAssignee = m.createRelatedAssignee[:len(m.createRelatedAssignee)-1]
+				if len(m.linkExistingID) > 0 {
+					m.linkExistingID = m.linkExistingID[:len(m.linkExistingID)-1]
 				}
 				return m, nil
 			default:
-				// Add character to the focused field
-				if len(msg.String()) == 1 {
-					if m.createRelatedFocus == 0 {
-						m.createRelatedTitle += msg.String()
-					} else {
-						m.createRelatedAssignee += msg.String()
-					}
-				} else if msg.String() == "space" {
-					if m.createRelatedFocus == 0 {
-						m.createRelatedTitle += " "
-					} else {
-						m.createRelatedAssignee += " "
-					}
+				// Only accept digits for the work item ID
+				if key := msg.String(); len(key) == 1 && key[0] >= '0' && key[0] <= '9' {
+					m.linkExistingID += key
 				}
 				return m, nil
 			}
 		}
 
+		// Handle tag editor input
+		if m.tagEditing {
+			return m.updateTagEditor(msg)
+		}
+
 		// Handle add hyperlink mode input
 		if m.addingHyperlink {
 			switch msg.String() {
@@ -262,7 +364,7 @@ func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.hyperlinkURL != "" {
 					m.loading = true
 					m.addingHyperlink = false
-					return m, m.addHyperlink(m.selectedItem.ID, m.hyperlinkURL, m.hyperlinkComment)
+					return m.confirmWrite(fmt.Sprintf("Add link %s to #%d?", m.hyperlinkURL, m.selectedItem.ID), m.addHyperlink(m.selectedItem.ID, m.hyperlinkURL, m.hyperlinkComment))
 				}
 				return m, nil
 			case "tab":
@@ -307,10 +409,40 @@ func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Arrow keys scroll a detail view taller than the terminal one line at a time;
+		// tab still moves between fields
+		if key := msg.String(); (key == "up" || key == "down") && !m.commentsExpanded && !m.relatedExpanded && !m.hyperlinksExpanded {
+			if maxScroll := m.detailMaxScroll(); maxScroll > 0 {
+				if key == "down" {
+					m.detailScroll = min(m.detailScroll+1, maxScroll)
+				} else {
+					m.detailScroll = max(m.detailScroll-1, 0)
+				}
+				return m, nil
+			}
+		}
+
 		switch msg.String() {
+		case "pgdown":
+			// Scroll the whole detail view when no section is capturing navigation
+			if !m.commentsExpanded && !m.relatedExpanded && !m.hyperlinksExpanded {
+				m.detailScroll += m.detailPageHeight()
+				if maxScroll := m.detailMaxScroll(); m.detailScroll > maxScroll {
+					m.detailScroll = maxScroll
+				}
+			}
+			return m, nil
+		case "pgup":
+			if !m.commentsExpanded && !m.relatedExpanded && !m.hyperlinksExpanded {
+				m.detailScroll -= m.detailPageHeight()
+				if m.detailScroll < 0 {
+					m.detailScroll = 0
+				}
+			}
+			return m, nil
 		case "tab", "down":
 			if !m.commentsExpanded && !m.relatedExpanded && !m.hyperlinksExpanded {
-				m.detailFocus = (m.detailFocus + 1) % len(m.detailInputs)
+				m.detailFocus = m.nextEditableField(m.detailFocus, 1)
 				return m, m.updateDetailFocus()
 			} else if m.relatedExpanded {
 				// Navigate through related items
@@ -330,10 +462,7 @@ func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		case "shift+tab", "up":
 			if !m.commentsExpanded && !m.relatedExpanded && !m.hyperlinksExpanded {
-				m.detailFocus--
-				if m.detailFocus < 0 {
-					m.detailFocus = len(m.detailInputs) - 1
-				}
+				m.detailFocus = m.nextEditableField(m.detailFocus, -1)
 				return m, m.updateDetailFocus()
 			} else if m.relatedExpanded {
 				// Navigate through related items
@@ -357,14 +486,111 @@ func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			return m, nil
+		case "alt+1", "alt+2", "alt+3", "alt+4", "alt+5":
+			// Jump straight to a field; plain digits keep typing into the focused input
+			if !m.commentsExpanded && !m.relatedExpanded && !m.hyperlinksExpanded {
+				field := int(msg.String()[len("alt+")] - '1')
+				if m.detailFieldReadOnly(field) {
+					m.message = fmt.Sprintf("%s is read-only for %s", detailLabels[field], m.selectedItem.Fields.WorkItemType)
+					return m, nil
+				}
+				if field < len(m.detailInputs) {
+					m.detailFocus = field
+					return m, m.updateDetailFocus()
+				}
+			}
+			return m, nil
+		case "ctrl+y", "alt+y":
+			// Copy a #ID mention (ctrl+y) or the mention with a markdown web link (alt+y)
+			webURL := ""
+			if msg.String() == "alt+y" && m.client != nil {
+				webURL = m.client.WorkItemWebURL(m.selectedItem.ID)
+			}
+			link := buildMentionLink(m.selectedItem, webURL)
+			if err := clipboard.WriteAll(link); err != nil {
+				m.err = fmt.Errorf("failed to write clipboard: %w", err)
+			} else {
+				m.err = nil
+				m.message = fmt.Sprintf("Copied %s", link)
+			}
+			return m, nil
+		case "alt+c":
+			// Copy the whole comment thread as plain text, e.g. for status write-ups,
+			// loading any pages not fetched yet first
+			if m.loadingMoreComments {
+				// A page for the same token is in flight; copying now would append it twice
+				m.message = "Still loading comments, try again in a moment"
+				return m, nil
+			}
+			if m.commentsToken != "" && !m.offline {
+				m.loading = true
+				m.loadingMoreComments = true
+				return m, m.fetchRemainingComments(m.selectedItem.ID, m.commentsToken)
+			}
+			return m.copyComments(), nil
+		case "alt+r":
+			// Pick the reason recorded with a state change, fetching the type's reasons on first use
+			if m.stateReasons == nil {
+				m.loading = true
+				return m, m.fetchStateReasons(m.selectedItem)
+			}
+			m.pendingReason = nextReason(m.stateReasons, m.pendingReason)
+			return m, nil
+		case "alt+s", "alt+S":
+			// Move the State to the next (alt+s) or previous (alt+S) state the item can change to
+			if m.detailFieldReadOnly(1) {
+				m.message = fmt.Sprintf("State is read-only for %s", m.selectedItem.Fields.WorkItemType)
+				return m, nil
+			}
+			step := 1
+			if msg.String() == "alt+S" {
+				step = -1
+			}
+			if m.workflowType != m.selectedItem.Fields.WorkItemType {
+				m.loading = true
+				m.pendingStateStep = step
+				return m, m.fetchWorkflowStates(m.selectedItem.Fields.WorkItemType)
+			}
+			return m.stepState(step), nil
+		case "f5":
+			// Reload the item, its comments and related items after outside changes
+			m.loading = true
+			m.err = nil
+			m.commentScroll = 0
+			m.commentsToken = ""
+			m.loadingMoreComments = false
+			id := m.selectedItem.ID
+			return m, tea.Batch(m.refreshWorkItem(id), m.fetchComments(id), m.fetchRelatedItems(id))
+		case "ctrl+d":
+			// Clone the current item into a pre-filled create form
+			if m.selectedItem != nil {
+				m = m.startClone(m.selectedItem)
+			}
+			return m, nil
 		case "ctrl+s":
 			// Save changes to title/state/assignee/tags
+			original := detailFieldValues(m.selectedItem)
+			for i, value := range original {
+				if m.detailFieldReadOnly(i) && m.detailInputs[i].Value() != value {
+					m.err = fmt.Errorf("%s is read-only for %s", detailLabels[i], m.selectedItem.Fields.WorkItemType)
+					return m, nil
+				}
+			}
 			title := m.detailInputs[0].Value()
 			state := m.detailInputs[1].Value()
+			// Catch typos in the State before the server rejects them
+			if m.stateValuesType == m.selectedItem.Fields.WorkItemType {
+				valid, ok := allowedValue(m.stateValues, state)
+				if !ok {
+					m.err = fmt.Errorf("%q isn't a %s state; use one of: %s", state, m.selectedItem.Fields.WorkItemType, strings.Join(m.stateValues, ", "))
+					return m, nil
+				}
+				state = valid
+			}
 			assignedTo := m.detailInputs[2].Value()
 			tags := m.detailInputs[3].Value()
 			m.loading = true
-			return m, m.updateWorkItem(m.selectedItem.ID, title, state, assignedTo, tags)
+			return m.confirmWrite(fmt.Sprintf("Save changes to #%d?", m.selectedItem.ID), m.updateWorkItem(m.selectedItem.ID, title, state, m.reasonToSave(state), assignedTo, tags))
 		case "enter":
 			// If related items expanded, navigate to selected item
 			if m.relatedExpanded {
@@ -383,10 +609,22 @@ func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			}
+			// On the tags field, open the tag editor
+			if m.detailFocus == 3 {
+				m.tagEditing = true
+				m.tagList = parseTags(m.detailInputs[3].Value())
+				m.tagCursor = len(m.tagList) - 1
+				m.tagInput = ""
+				m.err = nil
+				if m.availableTags == nil {
+					return m, m.fetchTags()
+				}
+				return m, nil
+			}
 			// If on comment field and there's text, add the comment
 			if m.detailFocus == 4 && m.detailInputs[4].Value() != "" {
 				m.loading = true
-				return m, m.addComment(m.selectedItem.ID, m.detailInputs[4].Value())
+				return m.confirmWrite(fmt.Sprintf("Add comment to #%d?", m.selectedItem.ID), m.addComment(m.selectedItem.ID, m.detailInputs[4].Value()))
 			}
 			return m, nil
 		case "ctrl+r":
@@ -431,7 +669,13 @@ func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else if m.hyperlinksExpanded && !m.addingHyperlink && m.hyperlinkCursor < len(m.hyperlinks) {
 				// Remove the selected hyperlink
 				m.loading = true
-				return m, m.removeHyperlink(m.selectedItem.ID, m.hyperlinks[m.hyperlinkCursor].URL)
+				url := m.hyperlinks[m.hyperlinkCursor].URL
+				return m.confirmWrite(fmt.Sprintf("Remove link %s from #%d?", url, m.selectedItem.ID), m.removeHyperlink(m.selectedItem.ID, url))
+			}
+		case "z":
+			// Undo the last unlink (only in related mode, otherwise let "z" pass through to input)
+			if m.relatedExpanded && !m.confirmingDelete {
+				return m.undoLast()
 			}
 		case "y":
 			// Confirm delete (only when confirming)
@@ -458,18 +702,38 @@ func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.planningExpanded = false
 			}
 			return m, nil
+		case "alt+o":
+			// Switch between oldest and newest comments first, reloading them in the new order
+			m.commentsNewestFirst = !m.commentsNewestFirst
+			m.commentScroll = 0
+			m.loadingMoreComments = false
+			if m.commentsNewestFirst {
+				m.message = "Comments: newest first"
+			} else {
+				m.message = "Comments: oldest first"
+			}
+			return m, m.fetchComments(m.selectedItem.ID)
+		case "alt+h":
+			// Switch comments between the cleaned-up text and the raw HTML
+			m.rawMode = !m.rawMode
+			if m.rawMode {
+				m.message = "Comments: raw HTML"
+			} else {
+				m.message = "Comments: rendered"
+			}
+			return m, nil
 		case "ctrl+n":
 			// Scroll comments down when expanded, or create child when in related mode
 			if m.commentsExpanded && m.commentScroll < len(m.comments)-1 {
 				m.commentScroll++
+				// Load the next page once the last loaded comments come into view
+				if m.commentsToken != "" && !m.loadingMoreComments && m.commentScroll+commentsVisible >= len(m.comments) {
+					m.loadingMoreComments = true
+					return m, m.fetchMoreComments(m.selectedItem.ID, m.commentsToken)
+				}
 			} else if m.relatedExpanded && !m.creatingRelated {
 				// Start creating a child item
-				m.creatingRelated = true
-				m.createRelatedAsChild = true
-				m.createRelatedTitle = ""
-				m.createRelatedType = 0
-				m.createRelatedAssignee = m.username
-				m.createRelatedFocus = 0
+				m = m.startCreateRelated(m.selectedItem.ID, true)
 			}
 			return m, nil
 		case "ctrl+p":
@@ -478,12 +742,7 @@ func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.commentScroll--
 			} else if m.relatedExpanded && !m.creatingRelated {
 				// Start creating a parent item
-				m.creatingRelated = true
-				m.createRelatedAsChild = false
-				m.createRelatedTitle = ""
-				m.createRelatedType = 0
-				m.createRelatedAssignee = m.username
-				m.createRelatedFocus = 0
+				m = m.startCreateRelated(m.selectedItem.ID, false)
 			}
 			return m, nil
 		case "ctrl+t":
@@ -496,8 +755,8 @@ func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.relatedExpanded = false
 				m.planningExpanded = false
 				m.hyperlinksExpanded = false
-				// Find current iteration in list to set cursor
-				for i, iter := range m.iterations {
+				// Find current iteration in the displayed list to set cursor
+				for i, iter := range m.getIterationDisplayOrder() {
 					if iter.Path == m.selectedItem.Fields.IterationPath {
 						m.iterationCursor = i
 						break
@@ -511,6 +770,53 @@ func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.iterationExpanded = false
 			}
 			return m, nil
+		case "alt+t":
+			// Move the item into the current sprint without opening the dropdown
+			return m.moveToCurrentSprint()
+		case "alt+m":
+			// Open a menu of the states the item can change to
+			if m.detailFieldReadOnly(1) {
+				m.message = fmt.Sprintf("State is read-only for %s", m.selectedItem.Fields.WorkItemType)
+				return m, nil
+			}
+			m.collapseDetailSections()
+			m.stateMenuExpanded = true
+			m.stateMenuCursor = m.currentStateIndex()
+			// States depend on the work item type
+			if m.workflowType != m.selectedItem.Fields.WorkItemType {
+				return m, m.fetchWorkflowStates(m.selectedItem.Fields.WorkItemType)
+			}
+			return m, nil
+		case "alt+a":
+			// Open activity selection on Tasks
+			if !hasActivity(*m.selectedItem) {
+				return m, nil
+			}
+			m.collapseDetailSections()
+			m.activityExpanded = true
+			m.activityCursor = max(slices.Index(m.activities, m.selectedItem.Fields.Activity), 0)
+			// Allowed values depend on the work item type
+			if m.activitiesType != m.selectedItem.Fields.WorkItemType {
+				m.activities = nil
+				return m, m.fetchActivities(m.selectedItem.Fields.WorkItemType)
+			}
+			return m, nil
+		case "ctrl+o":
+			// Open area selection (ctrl+o to move the item to another area)
+			m.areaExpanded = true
+			// Auto-collapse other sections
+			m.commentsExpanded = false
+			m.relatedExpanded = false
+			m.iterationExpanded = false
+			m.planningExpanded = false
+			m.hyperlinksExpanded = false
+			// Start on the current area
+			m.areaCursor = max(slices.Index(m.areas, m.selectedItem.Fields.AreaPath), 0)
+			// Fetch areas if not already loaded
+			if len(m.areas) == 0 {
+				return m, m.fetchAreas()
+			}
+			return m, nil
 		case "ctrl+l":
 			// Toggle hyperlinks section (ctrl+l for Links/PRs)
 			m.hyperlinksExpanded = !m.hyperlinksExpanded
@@ -524,6 +830,13 @@ func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		case "a":
+			// Link an existing item when in related mode
+			if m.relatedExpanded && !m.creatingRelated && !m.confirmingDelete {
+				m.linkingExisting = true
+				m.linkExistingAsChild = true
+				m.linkExistingID = ""
+				return m, nil
+			}
 			// Add hyperlink when in hyperlinks expanded mode (only when not in any input mode)
 			if m.hyperlinksExpanded && !m.addingHyperlink && !m.creatingRelated && !m.confirmingDelete {
 				m.addingHyperlink = true
@@ -565,6 +878,54 @@ func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// collapseDetailSections collapses all expandable sections of the detail view
+func (m *Model) collapseDetailSections() {
+	m.commentsExpanded = false
+	m.relatedExpanded = false
+	m.planningExpanded = false
+	m.iterationExpanded = false
+	m.hyperlinksExpanded = false
+	m.areaExpanded = false
+	m.activityExpanded = false
+	m.stateMenuExpanded = false
+}
+
+// detailLabels are the labels of the detail inputs
+var detailLabels = []string{"Title", "State", "Assigned To", "Tags", "Add Comment"}
+
+// detailFieldRefs are the reference names of the detail inputs; Add Comment isn't a field
+var detailFieldRefs = []string{"System.Title", "System.State", "System.AssignedTo", "System.Tags", ""}
+
+// detailFieldReadOnly reports whether detail input i edits a field the item's type marks read-only
+func (m Model) detailFieldReadOnly(i int) bool {
+	if i < 0 || i >= len(detailFieldRefs) || m.selectedItem == nil || m.readOnlyFieldsType != m.selectedItem.Fields.WorkItemType {
+		return false
+	}
+	return m.readOnlyFields[detailFieldRefs[i]]
+}
+
+// firstEditableField returns the first detail input of the selected item that isn't read-only
+func (m Model) firstEditableField() int {
+	if m.detailFieldReadOnly(0) {
+		return m.nextEditableField(0, 1)
+	}
+	return 0
+}
+
+// nextEditableField returns the first detail input after from, moving by step (+1/-1) and
+// wrapping around, that isn't read-only
+func (m Model) nextEditableField(from, step int) int {
+	n := len(m.detailInputs)
+	i := from
+	for range n {
+		i = (i + step + n) % n
+		if !m.detailFieldReadOnly(i) {
+			return i
+		}
+	}
+	return from
+}
+
 func (m *Model) updateDetailFocus() tea.Cmd {
 	cmds := make([]tea.Cmd, len(m.detailInputs))
 	for i := range m.detailInputs {
@@ -574,17 +935,83 @@ func (m *Model) updateDetailFocus() tea.Cmd {
 			m.detailInputs[i].Blur()
 		}
 	}
+	// Load the type's states the first time the State field is focused
+	if m.detailFocus == 1 && m.client != nil && m.selectedItem != nil && m.stateValuesType != m.selectedItem.Fields.WorkItemType {
+		cmds = append(cmds, m.fetchStateValues(m.selectedItem.Fields.WorkItemType))
+	}
 	return tea.Batch(cmds...)
 }
 
+// allowedValue matches v case-insensitively against a field's allowed values and returns
+// the value as the server spells it. Any value is accepted when values is empty.
+func allowedValue(values []string, v string) (string, bool) {
+	if len(values) == 0 {
+		return v, true
+	}
+	for _, value := range values {
+		if strings.EqualFold(value, strings.TrimSpace(v)) {
+			return value, true
+		}
+	}
+	return v, false
+}
+
 func (m *Model) updateDetailInputs(msg tea.Msg) tea.Cmd {
+	state := m.detailInputs[1].Value()
 	cmds := make([]tea.Cmd, len(m.detailInputs))
 	for i := range m.detailInputs {
 		m.detailInputs[i], cmds[i] = m.detailInputs[i].Update(msg)
 	}
+	m.dropReasonOnStateRevert(state)
 	return tea.Batch(cmds...)
 }
 
+// dropReasonOnStateRevert clears the picked reason when the State input has moved from before
+// back to the saved state, since a reason can only be saved with a state change
+func (m *Model) dropReasonOnStateRevert(before string) {
+	state := m.detailInputs[1].Value()
+	if state != before && m.selectedItem != nil && strings.EqualFold(state, m.selectedItem.Fields.State) {
+		m.pendingReason = ""
+	}
+}
+
+// reasonToSave returns the picked reason when state changes the item's state, and "" otherwise
+func (m Model) reasonToSave(state string) string {
+	if strings.EqualFold(state, m.selectedItem.Fields.State) {
+		return ""
+	}
+	return m.pendingReason
+}
+
+// detailFieldValues returns the values shown in the Title, State, Assigned To and Tags inputs
+func detailFieldValues(wi *azdo.WorkItem) []string {
+	assignedTo := ""
+	if wi.Fields.AssignedTo != nil {
+		assignedTo = wi.Fields.AssignedTo.UniqueName
+	}
+	return []string{wi.Fields.Title, wi.Fields.State, assignedTo, wi.Fields.Tags}
+}
+
+// applyRefreshedItem shows a re-fetched copy of the open work item.
+// Inputs the user has edited keep their unsaved values.
+func (m Model) applyRefreshedItem(wi *azdo.WorkItem) Model {
+	oldValues := detailFieldValues(m.selectedItem)
+	for i, value := range detailFieldValues(wi) {
+		if m.detailInputs[i].Value() == oldValues[i] {
+			m.detailInputs[i].SetValue(value)
+		}
+	}
+	m.selectedItem = wi
+	for i := range m.workItems {
+		if m.workItems[i].ID == wi.ID {
+			m.workItems[i] = *wi
+			break
+		}
+	}
+	m.message = fmt.Sprintf("Refreshed #%d", wi.ID)
+	return m
+}
+
 // navigateToWorkItem switches the detail view to a different work item
 func (m Model) navigateToWorkItem(wi *azdo.WorkItem) (tea.Model, tea.Cmd) {
 	m.selectedItem = wi
@@ -598,6 +1025,10 @@ func (m Model) navigateToWorkItem(wi *azdo.WorkItem) (tea.Model, tea.Cmd) {
 	m.detailInputs[3].SetValue(wi.Fields.Tags)
 	m.detailInputs[4].SetValue("")
 	m.comments = nil
+	m.commentsToken = ""
+	m.stateReasons = nil
+	m.pendingReason = ""
+	m.loadingMoreComments = false
 	m.parentItem = nil
 	m.childItems = nil
 	m.relatedExpanded = false
@@ -606,46 +1037,111 @@ func (m Model) navigateToWorkItem(wi *azdo.WorkItem) (tea.Model, tea.Cmd) {
 	m.commentScroll = 0
 	m.iterationExpanded = false
 	m.iterationCursor = 0
+	m.areaExpanded = false
+	m.areaCursor = 0
+	m.activityExpanded = false
+	m.activityCursor = 0
+	m.stateMenuExpanded = false
+	m.stateMenuCursor = 0
+	m.tagEditing = false
 	m.hyperlinks = nil
 	m.hyperlinksExpanded = false
 	m.hyperlinkCursor = 0
-	m.detailFocus = 0
+	m.detailFocus = m.firstEditableField()
+	m.detailScroll = 0
 	m.err = nil
 	m.message = ""
+	focusCmd := m.updateDetailFocus()
 
 	// Fetch comments, related items, and hyperlinks for the new work item
-	return m, tea.Batch(m.fetchComments(wi.ID), m.fetchRelatedItems(wi.ID), m.fetchHyperlinks(wi.ID))
+	return m, tea.Batch(focusCmd, m.fetchComments(wi.ID), m.fetchRelatedItems(wi.ID), m.fetchHyperlinks(wi.ID), m.fetchReadOnlyFields(wi))
 }
 
+// viewDetail renders the detail view, clipping it to the terminal height with a
+// scrollable viewport when the content does not fit
 func (m Model) viewDetail() string {
 	if m.selectedItem == nil {
 		return "No work item selected"
 	}
 
+	content := m.renderDetail()
+	pageHeight := m.detailPageHeight()
+	totalLines := lipgloss.Height(content)
+	if m.height <= 0 || totalLines <= m.height {
+		return content
+	}
+
+	vp := viewport.New(lipgloss.Width(content), pageHeight)
+	vp.SetContent(content)
+	vp.SetYOffset(m.detailScroll)
+
+	indicator := fmt.Sprintf("lines %d-%d of %d • ↑↓ pgup/pgdn: scroll • tab: next field", vp.YOffset+1, vp.YOffset+vp.VisibleLineCount(), totalLines)
+	return vp.View() + "\n" + helpStyle.UnsetMarginTop().Render(indicator)
+}
+
+// detailPageHeight returns the number of content lines visible in the detail viewport
+func (m Model) detailPageHeight() int {
+	if m.height <= 1 {
+		return 1
+	}
+	// Reserve the last line for the scroll indicator
+	return m.height - 1
+}
+
+// detailMaxScroll returns the largest valid scroll offset for the detail view
+func (m Model) detailMaxScroll() int {
+	if m.selectedItem == nil || m.height <= 0 {
+		return 0
+	}
+	maxScroll := lipgloss.Height(m.renderDetail()) - m.detailPageHeight()
+	if maxScroll < 0 {
+		return 0
+	}
+	return maxScroll
+}
+
+// renderDetail renders the full, unclipped detail view
+func (m Model) renderDetail() string {
 	var b strings.Builder
 
 	wi := m.selectedItem
 
 	// Header
+	b.WriteString(m.renderConnectionBanner())
 	header := titleStyle.Render(fmt.Sprintf("📝 %s #%d", wi.Fields.WorkItemType, wi.ID))
 	b.WriteString(header)
+	if wi.IsBlocked(m.appConfig.BlockedLinkType) {
+		b.WriteString(" ")
+		b.WriteString(errorStyle.Render(blockedMarker + "Blocked"))
+	}
 	b.WriteString("\n\n")
 
 	// Editable fields with helper text
-	labels := []string{"Title", "State", "Assigned To", "Tags", "Add Comment"}
 	hints := []string{
 		"",
 		"(New, Active, Resolved, Closed, Done)",
 		"(email address)",
-		"(semicolon-separated: tag1; tag2)",
+		"(semicolon-separated: tag1; tag2 • enter: tag editor)",
 		"",
 	}
+	if m.stateValuesType == wi.Fields.WorkItemType && len(m.stateValues) > 0 {
+		hints[1] = "(" + strings.Join(m.stateValues, ", ") + ")"
+	}
+	// The input holds the unique name that gets saved; show the display name alongside it
+	if a := wi.Fields.AssignedTo; !m.showUniqueNames && a != nil && a.DisplayName != "" && a.DisplayName != a.UniqueName {
+		hints[2] = fmt.Sprintf("(email address • %s)", a.DisplayName)
+	}
 
 	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true)
+	readOnlyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 
-	for i, label := range labels {
+	for i, label := range detailLabels {
+		readOnly := m.detailFieldReadOnly(i)
 		style := labelStyle
-		if i == m.detailFocus {
+		if readOnly {
+			style = readOnlyStyle.Bold(true)
+			hints[i] = "(read-only)"
+		} else if i == m.detailFocus {
 			style = style.Foreground(lipgloss.Color("229"))
 		}
 		b.WriteString(style.Render(label))
@@ -654,7 +1150,32 @@ func (m Model) viewDetail() string {
 			b.WriteString(hintStyle.Render(hints[i]))
 		}
 		b.WriteString("\n")
-		b.WriteString(m.detailInputs[i].View())
+		if readOnly {
+			// Shown without the input's cursor since it can't be edited
+			b.WriteString(readOnlyStyle.Render(m.detailInputs[i].Value()))
+		} else if i == 3 && m.tagEditing {
+			b.WriteString(m.renderTagEditor())
+		} else {
+			b.WriteString(m.detailInputs[i].View())
+		}
+		if i == 0 && m.detailFocus == 0 {
+			b.WriteString("\n")
+			b.WriteString(renderCharCount(m.detailInputs[0].Value(), "System.Title"))
+		}
+		if i == 1 {
+			if state := m.detailInputs[1].Value(); state != wi.Fields.State {
+				b.WriteString("\n")
+				b.WriteString(bannerStyle.Render(fmt.Sprintf("State: %s → %s", wi.Fields.State, state)))
+				b.WriteString(" ")
+				b.WriteString(hintStyle.Render("(unsaved • alt+s/alt+S: next/previous • ctrl+s: save)"))
+			}
+			b.WriteString("\n")
+			b.WriteString(m.renderReason(hintStyle))
+			if m.stateMenuExpanded {
+				b.WriteString("\n")
+				b.WriteString(m.renderStateMenu())
+			}
+		}
 		b.WriteString("\n\n")
 	}
 
@@ -664,9 +1185,61 @@ func (m Model) viewDetail() string {
 	b.WriteString(labelStyle.Render("Details"))
 	b.WriteString("\n")
 	b.WriteString(detailStyle.Render(fmt.Sprintf("Area Path: %s", wi.Fields.AreaPath)))
-	b.WriteString("\n")
+	if m.areaExpanded {
+		b.WriteString(" ")
+		b.WriteString(hintStyle.Render("(ctrl+o: collapse, ↑↓: select, enter: set)"))
+		b.WriteString("\n")
+		areaItemStyle := lipgloss.NewStyle().Padding(0, 1)
+		selectedAreaStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("229")).
+			Background(lipgloss.Color("57")).
+			Padding(0, 1)
+
+		if len(m.areas) == 0 {
+			b.WriteString(detailStyle.Render("Loading areas..."))
+			b.WriteString("\n")
+		}
+		// Keep the cursor in view when there are many areas
+		start, end := 0, len(m.areas)
+		if end > areasVisible {
+			start = min(max(m.areaCursor-areasVisible/2, 0), end-areasVisible)
+			end = start + areasVisible
+			b.WriteString(detailStyle.Render(fmt.Sprintf("Showing %d-%d of %d", start+1, end, len(m.areas))))
+			b.WriteString("\n")
+		}
+		for i := start; i < end; i++ {
+			area := m.areas[i]
+			style := areaItemStyle
+			if m.areaCursor == i {
+				style = selectedAreaStyle
+			}
+			// Mark current area
+			marker := "  "
+			if area == wi.Fields.AreaPath {
+				marker = "✓ "
+			}
+			b.WriteString(style.Render(marker + area))
+			b.WriteString("\n")
+		}
+	} else {
+		b.WriteString(" ")
+		b.WriteString(hintStyle.Render("(ctrl+o: change)"))
+		b.WriteString("\n")
+	}
 	b.WriteString(detailStyle.Render(fmt.Sprintf("Type: %s", wi.Fields.WorkItemType)))
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+	if hasActivity(*wi) {
+		b.WriteString(m.renderActivity(detailStyle, hintStyle))
+	}
+	if wi.Fields.Priority != nil {
+		b.WriteString(detailStyle.Render(fmt.Sprintf("Priority: %d", *wi.Fields.Priority)))
+		b.WriteString("\n")
+	}
+	if created := createdLabel(wi, m.appConfig.dateTimeLayout()); created != "" {
+		b.WriteString(detailStyle.Render(created))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
 
 	// Iteration section
 	iterationHeaderStyle := labelStyle
@@ -681,7 +1254,7 @@ func (m Model) viewDetail() string {
 	} else {
 		b.WriteString(labelStyle.Render("▶ Iteration"))
 		b.WriteString(" ")
-		b.WriteString(hintStyle.Render("(ctrl+t: change)"))
+		b.WriteString(hintStyle.Render("(ctrl+t: change, alt+t: current sprint)"))
 	}
 	b.WriteString("\n")
 
@@ -706,7 +1279,18 @@ func (m Model) viewDetail() string {
 			b.WriteString("\n")
 		} else {
 			displayOrder := m.getIterationDisplayOrder()
+			now := time.Now()
+			grouped := hasIterationGroups(m.iterations, now)
+			groupHeaderStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Bold(true)
 			for displayIdx, iter := range displayOrder {
+				// Section header when the timeframe changes
+				if grouped {
+					group := iterationGroup(iter, now)
+					if displayIdx == 0 || iterationGroup(displayOrder[displayIdx-1], now) != group {
+						b.WriteString(groupHeaderStyle.Render(iterationGroupLabels[group]))
+						b.WriteString("\n")
+					}
+				}
 				style := iterItemStyle
 				if m.iterationCursor == displayIdx {
 					style = selectedIterStyle
@@ -716,11 +1300,14 @@ func (m Model) viewDetail() string {
 				if iter.Path == wi.Fields.IterationPath {
 					marker = "✓ "
 				}
-				// Show timeframe if available
+				// Show timeframe and dates if available
 				timeFrame := ""
 				if iter.Attributes != nil && iter.Attributes.TimeFrame != "" {
 					timeFrame = fmt.Sprintf(" [%s]", iter.Attributes.TimeFrame)
 				}
+				if dates := formatIterationDates(iter.Attributes, m.appConfig.dateLayout()); dates != "" {
+					timeFrame += fmt.Sprintf(" (%s)", dates)
+				}
 				b.WriteString(style.Render(fmt.Sprintf("%s%s%s", marker, iter.Name, timeFrame)))
 				b.WriteString("\n")
 			}
@@ -782,7 +1369,7 @@ func (m Model) viewDetail() string {
 			parentInfo := fmt.Sprintf("⬆ Parent: %s #%d - %s [%s]",
 				m.parentItem.Fields.WorkItemType,
 				m.parentItem.ID,
-				truncateString(m.parentItem.Fields.Title, 40),
+				truncateWidth(m.parentItem.Fields.Title, 40),
 				m.parentItem.Fields.State)
 			b.WriteString(style.Render(parentInfo))
 			b.WriteString("\n")
@@ -797,44 +1384,38 @@ func (m Model) viewDetail() string {
 			childInfo := fmt.Sprintf("⬇ Child: %s #%d - %s [%s]",
 				child.Fields.WorkItemType,
 				child.ID,
-				truncateString(child.Fields.Title, 40),
+				truncateWidth(child.Fields.Title, 40),
 				child.Fields.State)
 			b.WriteString(style.Render(childInfo))
 			b.WriteString("\n")
 		}
 
 		// Show message when no related items exist (but section is expanded)
-		if relatedCount == 0 && !m.creatingRelated {
-			b.WriteString(detailStyle.Render("No parent or child items - use ctrl+n to add child or ctrl+p to add parent"))
+		if relatedCount == 0 && !m.creatingRelated && !m.linkingExisting {
+			b.WriteString(detailStyle.Render("No parent or child items - use ctrl+n to add child, ctrl+p to add parent, or a to link existing"))
 			b.WriteString("\n")
 		}
 
 		// Show create related form if active
 		if m.creatingRelated {
 			b.WriteString("\n")
-			createFormStyle := lipgloss.NewStyle().
+			b.WriteString(m.renderCreateRelatedForm())
+			b.WriteString("\n")
+		}
+
+		// Show link existing form if active
+		if m.linkingExisting {
+			b.WriteString("\n")
+			linkFormStyle := lipgloss.NewStyle().
 				Border(lipgloss.RoundedBorder()).
 				BorderForeground(lipgloss.Color("39")).
 				Padding(0, 1)
 			relationType := "Child"
-			if !m.createRelatedAsChild {
+			if !m.linkExistingAsChild {
 				relationType = "Parent"
 			}
-			wiType := "Task"
-			if m.createRelatedType < len(m.workItemTypes) {
-				wiType = m.workItemTypes[m.createRelatedType]
-			}
-			// Show cursor indicator on focused field
-			titleCursor := ""
-			assigneeCursor := ""
-			if m.createRelatedFocus == 0 {
-				titleCursor = "_"
-			} else {
-				assigneeCursor = "_"
-			}
-			formContent := fmt.Sprintf("Create New %s (%s)\nTitle: %s%s\nAssigned To: %s%s\n\n←/→: change type • tab: switch field",
-				relationType, wiType, m.createRelatedTitle, titleCursor, m.createRelatedAssignee, assigneeCursor)
-			b.WriteString(createFormStyle.Render(formContent))
+			formContent := fmt.Sprintf("Link Existing Item as %s\nWork Item ID: %s_\n\ntab: switch child/parent", relationType, m.linkExistingID)
+			b.WriteString(linkFormStyle.Render(formContent))
 			b.WriteString("\n")
 		}
 	}
@@ -931,7 +1512,7 @@ func (m Model) viewDetail() string {
 			} else {
 				commentCursor = "_"
 			}
-			formContent := fmt.Sprintf("Add External Link\nURL: %s%s\nComment (optional): %s%s\n\ntab: switch field • enter: save • esc: cancel",
+			formContent := fmt.Sprintf("Add External Link\nURL or vstfs:/// artifact URI: %s%s\nComment (optional): %s%s\n\ntab: switch field • enter: save • esc: cancel",
 				m.hyperlinkURL, urlCursor, m.hyperlinkComment, commentCursor)
 			b.WriteString(addFormStyle.Render(formContent))
 			b.WriteString("\n")
@@ -946,11 +1527,11 @@ func (m Model) viewDetail() string {
 	}
 
 	if m.commentsExpanded {
-		b.WriteString(commentHeaderStyle.Render(fmt.Sprintf("▼ Comments (%d)", len(m.comments))))
+		b.WriteString(commentHeaderStyle.Render(fmt.Sprintf("▼ Comments (%s)", m.commentCountLabel())))
 		b.WriteString(" ")
-		b.WriteString(hintStyle.Render("(ctrl+e: collapse, ctrl+n/p: scroll)"))
+		b.WriteString(hintStyle.Render(fmt.Sprintf("(ctrl+e: collapse, ctrl+n/p: scroll, alt+o: %s, alt+h: %s)", m.otherCommentOrder(), m.otherCommentMode())))
 	} else {
-		b.WriteString(labelStyle.Render(fmt.Sprintf("▶ Comments (%d)", len(m.comments))))
+		b.WriteString(labelStyle.Render(fmt.Sprintf("▶ Comments (%s)", m.commentCountLabel())))
 		b.WriteString(" ")
 		b.WriteString(hintStyle.Render("(ctrl+e: expand)"))
 	}
@@ -961,10 +1542,10 @@ func (m Model) viewDetail() string {
 		b.WriteString("\n")
 	} else if !m.commentsExpanded {
 		// Collapsed: show summary of most recent comment
-		lastComment := m.comments[len(m.comments)-1]
+		lastComment := m.newestComment()
 		dateStr := ""
 		if t, err := time.Parse(time.RFC3339, lastComment.CreatedDate); err == nil {
-			dateStr = t.Format("Jan 02")
+			dateStr = t.Format(m.appConfig.dateLayout())
 		}
 		summary := fmt.Sprintf("Latest: %s (%s)", lastComment.CreatedBy.DisplayName, dateStr)
 		b.WriteString(detailStyle.Render(summary))
@@ -977,8 +1558,8 @@ func (m Model) viewDetail() string {
 			Padding(0, 1).
 			MarginBottom(1)
 
-		// Show 5 comments starting from scroll position
-		maxVisible := 5
+		// Show commentsVisible comments starting from scroll position
+		maxVisible := commentsVisible
 		start := m.commentScroll
 		end := start + maxVisible
 		if end > len(m.comments) {
@@ -995,21 +1576,18 @@ func (m Model) viewDetail() string {
 		// Get the organization URL for mention links
 		orgURL := ""
 		if m.client != nil {
-			orgURL = fmt.Sprintf("https://dev.azure.com/%s", m.client.Organization)
+			orgURL = m.client.OrganizationURL()
 		}
 
 		for i := start; i < end; i++ {
 			c := m.comments[i]
 			dateStr := ""
 			if t, err := time.Parse(time.RFC3339, c.CreatedDate); err == nil {
-				dateStr = t.Format("Jan 02, 15:04")
+				dateStr = t.Format(m.appConfig.dateTimeLayout())
 			}
 			header := fmt.Sprintf("%s - %s", c.CreatedBy.DisplayName, dateStr)
-			// Process mentions and strip HTML tags
-			text := stripHTMLTags(c.Text, orgURL)
-			if len(text) > 200 {
-				text = text[:197] + "..."
-			}
+			text := m.commentText(c.Text, orgURL)
+			text = truncateWidth(text, 200)
 			b.WriteString(commentStyle.Render(fmt.Sprintf("%s\n%s", header, text)))
 			b.WriteString("\n")
 		}
@@ -1048,6 +1626,9 @@ func (m Model) viewDetail() string {
 		if wi.Fields.CompletedWork != nil {
 			planningParts = append(planningParts, fmt.Sprintf("Completed: %.1fh", *wi.Fields.CompletedWork))
 		}
+		if wi.Fields.Effort != nil {
+			planningParts = append(planningParts, fmt.Sprintf("Effort: %.1f", *wi.Fields.Effort))
+		}
 		if len(planningParts) == 0 {
 			b.WriteString(detailStyle.Render("No planning data"))
 		} else {
@@ -1081,10 +1662,14 @@ func (m Model) viewDetail() string {
 
 	// Error/success messages
 	if m.err != nil {
-		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString(m.renderError())
 		b.WriteString("\n")
 	}
-	if m.message != "" {
+	if m.confirmingEsc {
+		// The "press esc again" prompt is a warning, not a success
+		b.WriteString(staleStyle.Render(m.message))
+		b.WriteString("\n")
+	} else if m.message != "" {
 		b.WriteString(successStyle.Render(m.message))
 		b.WriteString("\n")
 	}
@@ -1096,40 +1681,50 @@ func (m Model) viewDetail() string {
 
 	b.WriteString("\n")
 	if m.commentsExpanded {
-		b.WriteString(helpStyle.Render("ctrl+e: collapse comments • ctrl+n/p: scroll • esc: back"))
+		b.WriteString(helpStyle.Render(fmt.Sprintf("ctrl+e: collapse comments • ctrl+n/p: scroll • alt+o: %s • alt+h: %s • %s", m.otherCommentOrder(), m.otherCommentMode(), m.escHelp("back"))))
 	} else if m.iterationExpanded {
-		b.WriteString(helpStyle.Render("ctrl+t: collapse • ↑↓: select • enter: set iteration • esc: back"))
+		b.WriteString(helpStyle.Render("ctrl+t: collapse • ↑↓: select • enter: set iteration • " + m.escHelp("back")))
+	} else if m.areaExpanded {
+		b.WriteString(helpStyle.Render("ctrl+o: collapse • ↑↓: select • enter: set area • " + m.escHelp("back")))
+	} else if m.activityExpanded {
+		b.WriteString(helpStyle.Render("alt+a: collapse • ↑↓: select • enter: set activity • " + m.escHelp("back")))
+	} else if m.stateMenuExpanded {
+		b.WriteString(helpStyle.Render("alt+m: collapse • ↑↓: select • enter: set state • " + m.escHelp("back")))
 	} else if m.addingHyperlink {
 		b.WriteString(helpStyle.Render("type URL • tab: switch field • enter: save • esc: cancel"))
 	} else if m.hyperlinksExpanded {
-		b.WriteString(helpStyle.Render("ctrl+l: collapse • a: add link • d: delete • ↑↓: select • esc: back"))
+		b.WriteString(helpStyle.Render("ctrl+l: collapse • a: add link • d: delete • ↑↓: select • " + m.escHelp("back")))
 	} else if m.creatingRelated {
 		b.WriteString(helpStyle.Render("type title • ←/→: change type • enter: create • esc: cancel"))
+	} else if m.linkingExisting {
+		b.WriteString(helpStyle.Render("type ID • tab: child/parent • enter: link • esc: cancel"))
 	} else if m.confirmingDelete {
 		confirmStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("196")).
 			Bold(true)
 		b.WriteString(confirmStyle.Render(fmt.Sprintf("Remove link to #%d? (y/n)", m.confirmDeleteTargetID)))
 	} else if m.relatedExpanded {
-		b.WriteString(helpStyle.Render("ctrl+r: collapse • ctrl+n: new child • ctrl+p: new parent • d: remove link • ↑↓: select • enter: open • esc: back"))
+		b.WriteString(helpStyle.Render("ctrl+r: collapse • ctrl+n: new child • ctrl+p: new parent • a: link existing • d: remove link • z: undo • ↑↓: select • enter: open • " + m.escHelp("back")))
 	} else if m.planningExpanded {
-		b.WriteString(helpStyle.Render("ctrl+g: collapse • ↑↓: navigate • enter: save • esc: back"))
+		b.WriteString(helpStyle.Render("ctrl+g: collapse • ↑↓: navigate • enter: save • " + m.escHelp("back")))
 	} else {
-		b.WriteString(helpStyle.Render("tab/↑↓: navigate • ctrl+s: save • ctrl+t: iteration • ctrl+e: comments • ctrl+r: related • ctrl+l: PRs • ctrl+g: planning • esc: back"))
+		b.WriteString(helpStyle.Render("tab/↑↓: navigate • alt+1-5: jump to field • ctrl+s: save • alt+s: next state • alt+m: state menu • ctrl+t: iteration • alt+t: current sprint • ctrl+e: comments • ctrl+r: related • ctrl+l: PRs • ctrl+g: planning • ctrl+y: copy #id • alt+c: copy comments • alt+r: reason • f5: refresh • ctrl+d: clone • ctrl+k: collapse all • " + m.escHelp("back")))
 	}
 
 	return boxStyle.Render(b.String())
 }
 
-// truncateString truncates a string to the specified length, adding "..." if truncated
-func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+// buildMentionLink returns the #ID mention that Azure DevOps auto-links in comments
+// and descriptions. When webURL is set, a markdown link to the item is appended.
+func buildMentionLink(wi *azdo.WorkItem, webURL string) string {
+	if wi == nil {
+		return ""
 	}
-	if maxLen <= 3 {
-		return s[:maxLen]
+	mention := fmt.Sprintf("#%d", wi.ID)
+	if webURL == "" {
+		return mention
 	}
-	return s[:maxLen-3] + "..."
+	return fmt.Sprintf("%s [%s](%s)", mention, wi.Fields.Title, webURL)
 }
 
 // updatePlanningFocus updates which planning input has focus
@@ -1149,44 +1744,127 @@ func (m *Model) updatePlanningFocus() tea.Cmd {
 func (m *Model) updatePlanningInputs(msg tea.Msg) tea.Cmd {
 	cmds := make([]tea.Cmd, len(m.planningInputs))
 	for i := range m.planningInputs {
-		m.planningInputs[i], cmds[i] = m.planningInputs[i].Update(msg)
+		key, ok := numericKey(msg, m.planningInputs[i].Value(), true)
+		if !ok {
+			continue
+		}
+		m.planningInputs[i], cmds[i] = m.planningInputs[i].Update(key)
 	}
 	return tea.Batch(cmds...)
 }
 
+// numericKey filters typed characters for a numeric input currently holding value,
+// keeping digits and, when allowDecimal is set, a single decimal point.
+// ok is false when none of the typed characters are allowed.
+func numericKey(msg tea.Msg, value string, allowDecimal bool) (tea.Msg, bool) {
+	key, isKey := msg.(tea.KeyMsg)
+	if !isKey || key.Type != tea.KeyRunes && key.Type != tea.KeySpace {
+		return msg, true
+	}
+	hasDot := strings.Contains(value, ".")
+	runes := make([]rune, 0, len(key.Runes))
+	for _, r := range key.Runes {
+		switch {
+		case r >= '0' && r <= '9':
+			runes = append(runes, r)
+		case r == '.' && allowDecimal && !hasDot:
+			runes = append(runes, r)
+			hasDot = true
+		}
+	}
+	if len(runes) == 0 {
+		return msg, false
+	}
+	key.Type = tea.KeyRunes
+	key.Runes = runes
+	return key, true
+}
+
 // savePlanningFieldsDynamic parses and saves the planning fields dynamically based on available fields
 func (m *Model) savePlanningFieldsDynamic() tea.Cmd {
 	if len(m.planningFields) == 0 {
 		return nil
 	}
 
+	fields, err := m.planningUpdates()
+	if err != nil {
+		m.err = err
+		return nil
+	}
+	m.err = nil
+
+	// Only update if at least one field has a value
+	if len(fields) == 0 {
+		return nil
+	}
+
+	m.loading = true
+	return m.updatePlanningDynamic(m.selectedItem.ID, fields)
+}
+
+// planningUpdates parses the planning inputs into a map of field reference names to values.
+// Any input that is not a valid number is reported in the returned error so that
+// a typo never results in a silently partial update.
+func (m Model) planningUpdates() (map[string]float64, error) {
 	fields := make(map[string]float64)
+	var problems []string
 
 	// Parse each field based on the dynamic field definitions
 	for i, field := range m.planningFields {
 		if i >= len(m.planningInputs) {
 			break
 		}
-		if v := m.planningInputs[i].Value(); v != "" {
-			var f float64
-			if _, err := fmt.Sscanf(v, "%f", &f); err == nil {
-				fields[field.ReferenceName] = f
-			}
+		v := strings.TrimSpace(m.planningInputs[i].Value())
+		if v == "" {
+			continue
 		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: invalid number", field.DisplayName))
+			continue
+		}
+		fields[field.ReferenceName] = f
 	}
 
-	// Only update if at least one field has a value
-	if len(fields) == 0 {
-		return nil
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(problems, "; "))
 	}
+	return fields, nil
+}
 
-	m.loading = true
-	return m.updatePlanningDynamic(m.selectedItem.ID, fields)
+// formatIterationDates formats an iteration's start and finish dates with layout (e.g. "Jan 01 – Jan 14")
+// Returns an empty string when the iteration has no dates
+func formatIterationDates(attrs *azdo.IterationAttributes, layout string) string {
+	if attrs == nil {
+		return ""
+	}
+	start, startErr := time.Parse(time.RFC3339, attrs.StartDate)
+	finish, finishErr := time.Parse(time.RFC3339, attrs.FinishDate)
+	switch {
+	case startErr == nil && finishErr == nil:
+		return fmt.Sprintf("%s – %s", start.UTC().Format(layout), finish.UTC().Format(layout))
+	case startErr == nil:
+		return "from " + start.UTC().Format(layout)
+	case finishErr == nil:
+		return "until " + finish.UTC().Format(layout)
+	}
+	return ""
 }
 
-// getIterationDisplayOrder returns iterations with current iteration first
+// getIterationDisplayOrder returns iterations in dropdown order
+// Iterations with a timeframe or dates are grouped into past, current, and future
+// sections (oldest first); otherwise the work item's current iteration comes first
 func (m Model) getIterationDisplayOrder() []azdo.Iteration {
-	if len(m.iterations) == 0 || m.selectedItem == nil {
+	if len(m.iterations) == 0 {
+		return m.iterations
+	}
+
+	now := time.Now()
+	if hasIterationGroups(m.iterations, now) {
+		return groupIterations(m.iterations, now)
+	}
+
+	if m.selectedItem == nil {
 		return m.iterations
 	}
 
@@ -1217,3 +1895,643 @@ func (m Model) getIterationDisplayOrder() []azdo.Iteration {
 
 	return result
 }
+
+// iterationGroupOrder is the order of the timeframe sections in the iteration dropdown
+// The empty group holds iterations with no timeframe or dates
+var iterationGroupOrder = []string{"past", "current", "future", ""}
+
+// iterationGroupLabels are the section headers shown in the iteration dropdown
+var iterationGroupLabels = map[string]string{
+	"past":    "Past",
+	"current": "Current",
+	"future":  "Future",
+	"":        "Unscheduled",
+}
+
+// iterationGroup returns the timeframe section of an iteration ("past", "current", "future")
+// It uses Attributes.TimeFrame, falling back to the dates, or "" when neither is set
+func iterationGroup(iter azdo.Iteration, now time.Time) string {
+	if iter.Attributes == nil {
+		return ""
+	}
+	if timeFrame := strings.ToLower(iter.Attributes.TimeFrame); timeFrame != "" {
+		return timeFrame
+	}
+	if finish, err := time.Parse(time.RFC3339, iter.Attributes.FinishDate); err == nil && finish.Before(now) {
+		return "past"
+	}
+	if start, err := time.Parse(time.RFC3339, iter.Attributes.StartDate); err == nil {
+		if start.After(now) {
+			return "future"
+		}
+		return "current"
+	}
+	return ""
+}
+
+// hasIterationGroups reports whether any iteration has a timeframe or dates
+func hasIterationGroups(iterations []azdo.Iteration, now time.Time) bool {
+	for _, iter := range iterations {
+		if iterationGroup(iter, now) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// groupIterations orders iterations by timeframe section, then by start date within a section
+func groupIterations(iterations []azdo.Iteration, now time.Time) []azdo.Iteration {
+	result := make([]azdo.Iteration, 0, len(iterations))
+	for _, group := range iterationGroupOrder {
+		var section []azdo.Iteration
+		for _, iter := range iterations {
+			if iterationGroup(iter, now) == group {
+				section = append(section, iter)
+			}
+		}
+		sort.SliceStable(section, func(i, j int) bool {
+			return iterationStart(section[i]).Before(iterationStart(section[j]))
+		})
+		result = append(result, section...)
+	}
+	return result
+}
+
+// iterationStart returns the start date of an iteration, or the zero time if unknown
+func iterationStart(iter azdo.Iteration) time.Time {
+	if iter.Attributes == nil {
+		return time.Time{}
+	}
+	start, _ := time.Parse(time.RFC3339, iter.Attributes.StartDate)
+	return start
+}
+
+// updateTagEditor handles key presses while the tag chip editor is open
+func (m Model) updateTagEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		// Close without touching the Tags field
+		m.tagEditing = false
+		m.tagInput = ""
+	case "left":
+		if m.tagCursor > 0 {
+			m.tagCursor--
+		}
+	case "right":
+		if m.tagCursor < len(m.tagList)-1 {
+			m.tagCursor++
+		}
+	case "tab":
+		// Autocomplete the typed text with the first suggestion
+		if suggestions := tagSuggestions(m.tagInput, m.availableTags, m.tagList); len(suggestions) > 0 {
+			m.tagInput = suggestions[0]
+		}
+	case "backspace":
+		if len(m.tagInput) > 0 {
+			runes := []rune(m.tagInput)
+			m.tagInput = string(runes[:len(runes)-1])
+		} else if m.tagCursor >= 0 && m.tagCursor < len(m.tagList) {
+			// Remove the selected tag
+			m.tagList = append(m.tagList[:m.tagCursor:m.tagCursor], m.tagList[m.tagCursor+1:]...)
+			if m.tagCursor >= len(m.tagList) {
+				m.tagCursor = len(m.tagList) - 1
+			}
+		}
+	case "enter", ";":
+		if strings.TrimSpace(m.tagInput) != "" {
+			m.tagList = parseTags(joinTags(append(m.tagList, m.tagInput)))
+			m.tagCursor = len(m.tagList) - 1
+			m.tagInput = ""
+			return m, nil
+		}
+		if msg.String() == "enter" {
+			// Nothing typed: apply the tags to the Tags field
+			m.detailInputs[3].SetValue(joinTags(m.tagList))
+			m.tagEditing = false
+			m.message = "Tags updated (ctrl+s: save)"
+		}
+	case " ":
+		m.tagInput += " "
+	default:
+		// ";" separates tags, so it can't be part of one
+		if msg.Type == tea.KeyRunes {
+			m.tagInput += strings.ReplaceAll(string(msg.Runes), ";", "")
+		}
+	}
+	return m, nil
+}
+
+// renderTagEditor renders the tag chips, the tag being typed, and autocomplete suggestions
+func (m Model) renderTagEditor() string {
+	chipStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Background(lipgloss.Color("238")).
+		Padding(0, 1)
+	selectedChipStyle := chipStyle.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true)
+
+	var b strings.Builder
+	if len(m.tagList) == 0 {
+		b.WriteString(hintStyle.Render("(no tags)"))
+	}
+	for i, tag := range m.tagList {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		if i == m.tagCursor && m.tagInput == "" {
+			b.WriteString(selectedChipStyle.Render(tag + " ✕"))
+		} else {
+			b.WriteString(chipStyle.Render(tag))
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("+ %s_", m.tagInput))
+	if suggestions := tagSuggestions(m.tagInput, m.availableTags, m.tagList); len(suggestions) > 0 {
+		b.WriteString(" ")
+		b.WriteString(hintStyle.Render(strings.Join(suggestions, ", ")))
+	}
+	b.WriteString("\n")
+	b.WriteString(hintStyle.Render("enter: add tag / apply • tab: complete • ←→: select • backspace: remove • esc: cancel"))
+	return b.String()
+}
+
+// maxTagSuggestions limits how many autocomplete suggestions are shown
+const maxTagSuggestions = 5
+
+// nextReason returns the reason after current, cycling back to none (the transition's default)
+func nextReason(reasons []string, current string) string {
+	for i, r := range reasons {
+		if r == current {
+			if i+1 < len(reasons) {
+				return reasons[i+1]
+			}
+			return ""
+		}
+	}
+	if len(reasons) > 0 {
+		return reasons[0]
+	}
+	return ""
+}
+
+// stateChoices returns the states an item in current can be saved with: current itself
+// and the states it can transition to, in the order the process defines them
+func stateChoices(states []azdo.WorkItemState, current string) []string {
+	var reachable []string
+	for _, st := range states {
+		if strings.EqualFold(st.Name, current) {
+			reachable = st.Transitions
+			break
+		}
+	}
+	var choices []string
+	for _, st := range states {
+		if strings.EqualFold(st.Name, current) || slices.Contains(reachable, st.Name) {
+			choices = append(choices, st.Name)
+		}
+	}
+	return choices
+}
+
+// stepState moves the State input step places through the states the saved state can change to,
+// wrapping around at either end
+func (m Model) stepState(step int) Model {
+	choices := stateChoices(m.workflowStates, m.selectedItem.Fields.State)
+	if len(choices) == 0 {
+		m.message = fmt.Sprintf("No workflow states found for %s", m.selectedItem.Fields.WorkItemType)
+		return m
+	}
+	i := slices.IndexFunc(choices, func(s string) bool {
+		return strings.EqualFold(s, m.detailInputs[1].Value())
+	})
+	if i < 0 {
+		// Start from the saved state when the input holds something else
+		i = max(slices.IndexFunc(choices, func(s string) bool {
+			return strings.EqualFold(s, m.selectedItem.Fields.State)
+		}), 0)
+	}
+	i = (i + step + len(choices)) % len(choices)
+	before := m.detailInputs[1].Value()
+	m.detailInputs[1].SetValue(choices[i])
+	m.dropReasonOnStateRevert(before)
+	m.err = nil
+	return m
+}
+
+// stateMenuChoices returns the states offered by the state menu: the item's state and the
+// states it can change to, or nil until the workflow of its type is loaded
+func (m Model) stateMenuChoices() []string {
+	if m.workflowType != m.selectedItem.Fields.WorkItemType {
+		return nil
+	}
+	return stateChoices(m.workflowStates, m.selectedItem.Fields.State)
+}
+
+// currentStateIndex returns the index of the item's state in the state menu, or 0
+func (m Model) currentStateIndex() int {
+	return max(slices.IndexFunc(m.stateMenuChoices(), func(s string) bool {
+		return strings.EqualFold(s, m.selectedItem.Fields.State)
+	}), 0)
+}
+
+// renderStateMenu renders the state menu, marking the item's current state
+func (m Model) renderStateMenu() string {
+	var b strings.Builder
+	itemStyle := lipgloss.NewStyle().Padding(0, 1)
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	choices := m.stateMenuChoices()
+	if choices == nil {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Render("Loading states..."))
+		b.WriteString("\n")
+	}
+	for i, state := range choices {
+		style := itemStyle
+		if m.stateMenuCursor == i {
+			style = selectedStyle
+		}
+		marker := "  "
+		if strings.EqualFold(state, m.selectedItem.Fields.State) {
+			marker = "✓ "
+		}
+		b.WriteString(style.Render(marker + state))
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderReason renders the item's reason, or the reason picked for the pending state change
+func (m Model) renderReason(hintStyle lipgloss.Style) string {
+	if m.pendingReason != "" {
+		return bannerStyle.Render("Reason: "+m.pendingReason) + " " + hintStyle.Render("(alt+r: next reason • saved with ctrl+s)")
+	}
+	reason := m.selectedItem.Fields.Reason
+	if reason == "" {
+		reason = "(none)"
+	}
+	return bannerStyle.Render("Reason: "+reason) + " " + hintStyle.Render("(alt+r: pick reason)")
+}
+
+// newestComment returns the most recent comment: it leads the list when newest first,
+// and ends it once every page is loaded. Otherwise it was fetched on its own.
+func (m Model) newestComment() azdo.Comment {
+	switch {
+	case m.commentsNewestFirst:
+		return m.comments[0]
+	case m.commentsToken != "" && m.latestComment != nil:
+		return *m.latestComment
+	}
+	return m.comments[len(m.comments)-1]
+}
+
+// copyComments copies the loaded comment thread to the clipboard as plain text
+func (m Model) copyComments() Model {
+	if len(m.comments) == 0 {
+		m.message = "No comments to copy"
+		return m
+	}
+	if err := writeClipboard(commentsToText(m.comments, m.appConfig.dateTimeLayout())); err != nil {
+		m.err = fmt.Errorf("failed to write clipboard: %w", err)
+	} else {
+		m.err = nil
+		m.message = fmt.Sprintf("Copied %d comments", len(m.comments))
+	}
+	return m
+}
+
+// commentsVisible is the number of comments shown at once in the expanded comments section
+const commentsVisible = 5
+
+// areasVisible is the number of area paths shown at once in the area picker
+const areasVisible = 10
+
+// commentCountLabel returns the number of loaded comments, with a "+" while more pages remain
+func (m Model) commentCountLabel() string {
+	if m.commentsToken != "" {
+		return fmt.Sprintf("%d+", len(m.comments))
+	}
+	return strconv.Itoa(len(m.comments))
+}
+
+// otherCommentOrder names the comment order alt+o switches to
+func (m Model) otherCommentOrder() string {
+	if m.commentsNewestFirst {
+		return "oldest first"
+	}
+	return "newest first"
+}
+
+// otherCommentMode names the comment rendering alt+h switches to
+func (m Model) otherCommentMode() string {
+	if m.rawMode {
+		return "rendered"
+	}
+	return "raw HTML"
+}
+
+// commentText is a comment body as shown in the detail view: with mentions, links and
+// HTML tags cleaned up, or exactly as sent in raw mode
+func (m Model) commentText(text, orgURL string) string {
+	if m.rawMode {
+		return strings.TrimSpace(text)
+	}
+	return stripHTMLTags(text, orgURL)
+}
+
+// commentsToText renders a comment thread as plain text with one block per comment:
+// the author and timestamp (formatted with layout) on the first line, then the HTML-stripped body
+func commentsToText(comments []azdo.Comment, layout string) string {
+	blocks := make([]string, 0, len(comments))
+	for _, c := range comments {
+		dateStr := c.CreatedDate
+		if t, err := time.Parse(time.RFC3339, c.CreatedDate); err == nil {
+			dateStr = t.Format(layout)
+		}
+		blocks = append(blocks, fmt.Sprintf("%s - %s\n%s", c.CreatedBy.DisplayName, dateStr, htmlToPlainText(c.Text)))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+var (
+	plainMentionRegex = regexp.MustCompile(`<a[^>]*data-vss-mention="[^"]*"[^>]*>(@[^<]*)</a>`)
+	plainLinkRegex    = regexp.MustCompile(`<a[^>]*href="([^"]+)"[^>]*>([^<]*)</a>`)
+	plainBreakRegex   = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>`)
+	plainTagRegex     = regexp.MustCompile(`<[^>]+>`)
+)
+
+// htmlToPlainText converts comment HTML to unstyled text, unlike stripHTMLTags which
+// highlights mentions and links for the terminal
+func htmlToPlainText(text string) string {
+	text = plainMentionRegex.ReplaceAllString(text, "$1")
+	text = plainLinkRegex.ReplaceAllStringFunc(text, func(match string) string {
+		sub := plainLinkRegex.FindStringSubmatch(match)
+		url, linkText := sub[1], sub[2]
+		if url == "#" || linkText == url {
+			return linkText
+		}
+		if linkText == "" {
+			return url
+		}
+		return linkText + " (" + url + ")"
+	})
+	text = plainBreakRegex.ReplaceAllString(text, "\n")
+	text = plainTagRegex.ReplaceAllString(text, "")
+	text = strings.NewReplacer("&nbsp;", " ", "&lt;", "<", "&gt;", ">", "&quot;", "\"", "&#39;", "'", "&amp;", "&").Replace(text)
+	return strings.TrimSpace(text)
+}
+
+// fieldLimits holds the maximum length of fields whose limits are known
+var fieldLimits = map[string]int{
+	"System.Title": 255,
+}
+
+// charCountWarnRatio is the fraction of a field's limit at which the count turns into a warning
+const charCountWarnRatio = 0.9
+
+// fieldLimit returns the maximum length of a field, or 0 when it isn't known
+func fieldLimit(referenceName string) int {
+	return fieldLimits[referenceName]
+}
+
+// renderCharCount renders the live length of a field value, warning as it nears the field's limit
+func renderCharCount(value, referenceName string) string {
+	count := utf8.RuneCountInString(value)
+	limit := fieldLimit(referenceName)
+	if limit == 0 {
+		return bannerStyle.Render(fmt.Sprintf("%d characters", count))
+	}
+
+	text := fmt.Sprintf("%d/%d characters", count, limit)
+	switch {
+	case count > limit:
+		return errorStyle.Render(text + " • over the limit, Azure DevOps will reject this")
+	case float64(count) >= float64(limit)*charCountWarnRatio:
+		return staleStyle.Render(text + " • approaching the limit")
+	}
+	return bannerStyle.Render(text)
+}
+
+// createdLabel describes who created a work item and when, in local time formatted with layout
+func createdLabel(wi *azdo.WorkItem, layout string) string {
+	by := ""
+	if wi.Fields.CreatedBy != nil {
+		by = wi.Fields.CreatedBy.DisplayName
+		if by == "" {
+			by = wi.Fields.CreatedBy.UniqueName
+		}
+	}
+	date := ""
+	if t, err := time.Parse(time.RFC3339, wi.Fields.CreatedDate); err == nil {
+		date = t.Local().Format(layout)
+	}
+	switch {
+	case by != "" && date != "":
+		return fmt.Sprintf("Created: %s by %s", date, by)
+	case date != "":
+		return "Created: " + date
+	case by != "":
+		return "Created by: " + by
+	}
+	return ""
+}
+
+// parseTags splits a "tag1; tag2" string into trimmed, de-duplicated tags
+// Tags are compared case-insensitively, keeping the first spelling seen
+func parseTags(s string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		tag := strings.TrimSpace(part)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// joinTags joins tags into the "tag1; tag2" format used by System.Tags
+func joinTags(tags []string) string {
+	return strings.Join(tags, "; ")
+}
+
+// tagSuggestions returns known tags starting with prefix that aren't already applied
+func tagSuggestions(prefix string, available, applied []string) []string {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" {
+		return nil
+	}
+	used := make(map[string]bool)
+	for _, tag := range applied {
+		used[strings.ToLower(tag)] = true
+	}
+	var suggestions []string
+	for _, tag := range available {
+		lower := strings.ToLower(tag)
+		if used[lower] || !strings.HasPrefix(lower, prefix) {
+			continue
+		}
+		suggestions = append(suggestions, tag)
+		if len(suggestions) == maxTagSuggestions {
+			break
+		}
+	}
+	return suggestions
+}
+
+// startCreateRelated opens the form for creating a child or parent of work item id
+func (m Model) startCreateRelated(id int, asChild bool) Model {
+	m.creatingRelated = true
+	m.createRelatedTarget = id
+	m.createRelatedAsChild = asChild
+	m.createRelatedTitle = ""
+	m.createRelatedType = 0
+	m.createRelatedAssignee = m.username
+	m.createRelatedFocus = 0
+	return m
+}
+
+// updateCreateRelated handles key presses in the create related item form
+func (m Model) updateCreateRelated(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.creatingRelated = false
+		m.createRelatedTitle = ""
+		return m, nil
+	case "enter":
+		if m.createRelatedTitle != "" {
+			wiType := "Task"
+			if m.createRelatedType < len(m.workItemTypes) {
+				wiType = m.workItemTypes[m.createRelatedType]
+			}
+			m.loading = true
+			m.creatingRelated = false
+			relation := "parent"
+			if m.createRelatedAsChild {
+				relation = "child"
+			}
+			return m.confirmWrite(fmt.Sprintf("Create %s %s %q of #%d?", wiType, relation, m.createRelatedTitle, m.createRelatedTarget),
+				m.createRelatedItem(m.createRelatedTarget, m.createRelatedAsChild, m.createRelatedTitle, wiType, m.createRelatedAssignee))
+		}
+		return m, nil
+	case "tab":
+		// Toggle between title and assignee fields
+		m.createRelatedFocus = (m.createRelatedFocus + 1) % 2
+		return m, nil
+	case "left":
+		if m.createRelatedType > 0 {
+			m.createRelatedType--
+		} else {
+			m.createRelatedType = len(m.workItemTypes) - 1
+		}
+		return m, nil
+	case "right":
+		m.createRelatedType = (m.createRelatedType + 1) % len(m.workItemTypes)
+		return m, nil
+	case "backspace":
+		if m.createRelatedFocus == 0 && len(m.createRelatedTitle) > 0 {
+			m.createRelatedTitle = m.createRelatedTitle[:len(m.createRelatedTitle)-1]
+		} else if m.createRelatedFocus == 1 && len(m.createRelatedAssignee) > 0 {
+			m.createRelatedAssignee = m.createRelatedAssignee[:len(m.createRelatedAssignee)-1]
+		}
+		return m, nil
+	default:
+		// Add character to the focused field
+		if len(msg.String()) == 1 {
+			if m.createRelatedFocus == 0 {
+				m.createRelatedTitle += msg.String()
+			} else {
+				m.createRelatedAssignee += msg.String()
+			}
+		} else if msg.String() == "space" {
+			if m.createRelatedFocus == 0 {
+				m.createRelatedTitle += " "
+			} else {
+				m.createRelatedAssignee += " "
+			}
+		}
+		return m, nil
+	}
+}
+
+// renderCreateRelatedForm renders the create related item form
+func (m Model) renderCreateRelatedForm() string {
+	createFormStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("39")).
+		Padding(0, 1)
+	relationType := "Child"
+	if !m.createRelatedAsChild {
+		relationType = "Parent"
+	}
+	wiType := "Task"
+	if m.createRelatedType < len(m.workItemTypes) {
+		wiType = m.workItemTypes[m.createRelatedType]
+	}
+	// Show cursor indicator on focused field
+	titleCursor := ""
+	assigneeCursor := ""
+	if m.createRelatedFocus == 0 {
+		titleCursor = "_"
+	} else {
+		assigneeCursor = "_"
+	}
+	formContent := fmt.Sprintf("Create New %s of #%d (%s)\nTitle: %s%s\nAssigned To: %s%s\n\n←/→: change type • tab: switch field",
+		relationType, m.createRelatedTarget, wiType, m.createRelatedTitle, titleCursor, m.createRelatedAssignee, assigneeCursor)
+	return createFormStyle.Render(formContent)
+}
+
+// hasActivity reports whether a work item's Activity field is shown in the detail view
+func hasActivity(wi azdo.WorkItem) bool {
+	return wi.Fields.WorkItemType == "Task" || wi.Fields.Activity != ""
+}
+
+// renderActivity renders the Activity line of the detail view, with its dropdown when open
+func (m Model) renderActivity(detailStyle, hintStyle lipgloss.Style) string {
+	var b strings.Builder
+	activity := m.selectedItem.Fields.Activity
+	if activity == "" {
+		activity = "(none)"
+	}
+	b.WriteString(detailStyle.Render(fmt.Sprintf("Activity: %s", activity)))
+	b.WriteString(" ")
+	if !m.activityExpanded {
+		b.WriteString(hintStyle.Render("(alt+a: change)"))
+		b.WriteString("\n")
+		return b.String()
+	}
+	b.WriteString(hintStyle.Render("(alt+a: collapse, ↑↓: select, enter: set)"))
+	b.WriteString("\n")
+	itemStyle := lipgloss.NewStyle().Padding(0, 1)
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57")).
+		Padding(0, 1)
+	if len(m.activities) == 0 {
+		if m.activitiesType == m.selectedItem.Fields.WorkItemType {
+			b.WriteString(detailStyle.Render("No activities are defined for this type"))
+		} else {
+			b.WriteString(detailStyle.Render("Loading activities..."))
+		}
+		b.WriteString("\n")
+	}
+	for i, value := range m.activities {
+		style := itemStyle
+		if m.activityCursor == i {
+			style = selectedStyle
+		}
+		marker := "  "
+		if value == m.selectedItem.Fields.Activity {
+			marker = "✓ "
+		}
+		b.WriteString(style.Render(marker + value))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -1,10 +1,21 @@
 package tui
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestParseMentions(t *testing.T) {
@@ -197,7 +208,7 @@ func TestStripHTMLTags(t *testing.T) {
 	}
 }
 
-func TestTruncateString(t *testing.T) {
+func TestTruncateWidth(t *testing.T) {
 	tests := []struct {
 		name   string
 		input  string
@@ -220,13 +231,13 @@ func TestTruncateString(t *testing.T) {
 			name:   "truncates with ellipsis",
 			input:  "This is a long string",
 			maxLen: 10,
-			want:   "This is...",
+			want:   "This is a…",
 		},
 		{
 			name:   "very short maxLen",
 			input:  "Hello",
 			maxLen: 3,
-			want:   "Hel",
+			want:   "He…",
 		},
 		{
 			name:   "empty string",
@@ -234,13 +245,31 @@ func TestTruncateString(t *testing.T) {
 			maxLen: 10,
 			want:   "",
 		},
+		{
+			name:   "emoji kept whole",
+			input:  "🚀🚀🚀 launch the rocket",
+			maxLen: 10,
+			want:   "🚀🚀🚀 la…",
+		},
+		{
+			name:   "CJK counted as double width",
+			input:  "漢字のタイトルです",
+			maxLen: 9,
+			want:   "漢字のタ…",
+		},
+		{
+			name:   "wide character not split at the boundary",
+			input:  "ab漢字xyz",
+			maxLen: 6,
+			want:   "ab漢…",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := truncateString(tt.input, tt.maxLen)
+			result := truncateWidth(tt.input, tt.maxLen)
 			if result != tt.want {
-				t.Errorf("truncateString(%q, %d) = %q, want %q", tt.input, tt.maxLen, result, tt.want)
+				t.Errorf("truncateWidth(%q, %d) = %q, want %q", tt.input, tt.maxLen, result, tt.want)
 			}
 		})
 	}
@@ -384,6 +413,25 @@ func TestUpdatePlanningInputsFromWorkItemDynamic(t *testing.T) {
 	}
 }
 
+func TestUpdatePlanningInputsFromWorkItemDynamicOtherFields(t *testing.T) {
+	m := NewModel()
+	var wi azdo.WorkItem
+	data := `{"id": 1, "fields": {"Microsoft.VSTS.Scheduling.Size": 13, "Microsoft.VSTS.Scheduling.StoryPoints": 2}}`
+	if err := json.Unmarshal([]byte(data), &wi); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	m.selectedItem = &wi
+	m.planningFields = []azdo.PlanningField{
+		{ReferenceName: "Microsoft.VSTS.Scheduling.Size", DisplayName: "Size"},
+		{ReferenceName: "Microsoft.VSTS.Scheduling.StoryPoints", DisplayName: "Story Points"},
+	}
+
+	m.updatePlanningInputsFromWorkItemDynamic()
+	if m.planningInputs[0].Value() != "13.0" || m.planningInputs[1].Value() != "2.0" {
+		t.Errorf("planning inputs = %q, %q, want every discovered field filled", m.planningInputs[0].Value(), m.planningInputs[1].Value())
+	}
+}
+
 func TestUpdatePlanningInputsFromWorkItemDynamicNilSelectedItem(t *testing.T) {
 	m := NewModel()
 	m.selectedItem = nil
@@ -462,8 +510,8 @@ func TestPlanningStateInitialization(t *testing.T) {
 	if m.planningFocus != 0 {
 		t.Errorf("planningFocus = %v, want %v", m.planningFocus, 0)
 	}
-	if len(m.planningInputs) != 4 {
-		t.Errorf("planningInputs length = %v, want %v", len(m.planningInputs), 4)
+	if len(m.planningInputs) != 5 {
+		t.Errorf("planningInputs length = %v, want %v", len(m.planningInputs), 5)
 	}
 	if m.planningFields != nil {
 		t.Error("planningFields should be nil initially")
@@ -510,3 +558,970 @@ func TestPlanningFieldsCount(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildMentionLink(t *testing.T) {
+	wi := &azdo.WorkItem{ID: 123, Fields: azdo.WorkItemFields{Title: "Fix login"}}
+
+	if got := buildMentionLink(wi, ""); got != "#123" {
+		t.Errorf("buildMentionLink() = %q, want %q", got, "#123")
+	}
+
+	url := "https://dev.azure.com/org/proj/_workitems/edit/123"
+	want := "#123 [Fix login](https://dev.azure.com/org/proj/_workitems/edit/123)"
+	if got := buildMentionLink(wi, url); got != want {
+		t.Errorf("buildMentionLink() = %q, want %q", got, want)
+	}
+
+	if got := buildMentionLink(nil, url); got != "" {
+		t.Errorf("buildMentionLink(nil) = %q, want empty", got)
+	}
+}
+
+func TestUpdatePlanningInputsFromWorkItemEffort(t *testing.T) {
+	effort := 13.0
+
+	m := NewModel()
+	m.selectedItem = &azdo.WorkItem{
+		ID:     321,
+		Fields: azdo.WorkItemFields{WorkItemType: "Product Backlog Item", Effort: &effort},
+	}
+
+	m.updatePlanningInputsFromWorkItem()
+	if m.planningInputs[4].Value() != "13.0" {
+		t.Errorf("planningInputs[4] = %v, want %v", m.planningInputs[4].Value(), "13.0")
+	}
+
+	m.planningFields = []azdo.PlanningField{
+		{ReferenceName: "Microsoft.VSTS.Scheduling.Effort", DisplayName: "Effort"},
+	}
+	m.updatePlanningInputsFromWorkItemDynamic()
+	if m.planningInputs[0].Value() != "13.0" {
+		t.Errorf("dynamic planningInputs[0] = %v, want %v", m.planningInputs[0].Value(), "13.0")
+	}
+}
+
+func TestPlanningUpdatesIncludesEffort(t *testing.T) {
+	m := NewModel()
+	m.planningFields = []azdo.PlanningField{
+		{ReferenceName: "Microsoft.VSTS.Scheduling.StoryPoints", DisplayName: "Story Points"},
+		{ReferenceName: "Microsoft.VSTS.Scheduling.Effort", DisplayName: "Effort"},
+	}
+	m.planningInputs[1].SetValue("8")
+
+	fields, err := m.planningUpdates()
+	if err != nil {
+		t.Fatalf("planningUpdates() error = %v", err)
+	}
+	if len(fields) != 1 {
+		t.Fatalf("planningUpdates() = %v, want only Effort", fields)
+	}
+	if fields["Microsoft.VSTS.Scheduling.Effort"] != 8 {
+		t.Errorf("Effort = %v, want 8", fields["Microsoft.VSTS.Scheduling.Effort"])
+	}
+}
+
+func TestPlanningUpdatesValidation(t *testing.T) {
+	fields := []azdo.PlanningField{
+		{ReferenceName: "Microsoft.VSTS.Scheduling.StoryPoints", DisplayName: "Story Points"},
+		{ReferenceName: "Microsoft.VSTS.Scheduling.RemainingWork", DisplayName: "Remaining Work (hours)"},
+	}
+
+	tests := []struct {
+		name      string
+		values    []string
+		wantErr   string
+		wantCount int
+	}{
+		{
+			name:      "valid numbers",
+			values:    []string{"5", "2.5"},
+			wantCount: 2,
+		},
+		{
+			name:      "blank inputs are skipped",
+			values:    []string{"", " 3 "},
+			wantCount: 1,
+		},
+		{
+			name:    "typo in story points",
+			values:  []string{"5o", "2"},
+			wantErr: "Story Points: invalid number",
+		},
+		{
+			name:    "multiple invalid inputs",
+			values:  []string{"abc", "1..2"},
+			wantErr: "Story Points: invalid number; Remaining Work (hours): invalid number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel()
+			m.planningFields = fields
+			for i, v := range tt.values {
+				m.planningInputs[i].SetValue(v)
+			}
+
+			got, err := m.planningUpdates()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("planningUpdates() error = %v, want %q", err, tt.wantErr)
+				}
+				if got != nil {
+					t.Errorf("planningUpdates() = %v, want no partial update", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("planningUpdates() unexpected error = %v", err)
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("planningUpdates() = %v, want %d fields", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestParseJoinTags(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   []string
+		joined string
+	}{
+		{name: "empty", input: "", want: nil, joined: ""},
+		{name: "single", input: "backend", want: []string{"backend"}, joined: "backend"},
+		{name: "trims whitespace", input: "  backend ;urgent  ", want: []string{"backend", "urgent"}, joined: "backend; urgent"},
+		{name: "drops empty entries", input: "a;; ;b;", want: []string{"a", "b"}, joined: "a; b"},
+		{name: "dedups case-insensitively", input: "Backend; backend; urgent; BACKEND", want: []string{"Backend", "urgent"}, joined: "Backend; urgent"},
+		{name: "keeps inner spaces", input: "needs review; v2", want: []string{"needs review", "v2"}, joined: "needs review; v2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseTags(tt.input)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("parseTags(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			joined := joinTags(got)
+			if joined != tt.joined {
+				t.Errorf("joinTags(%q) = %q, want %q", got, joined, tt.joined)
+			}
+			// Joining and re-parsing should be stable
+			if again := parseTags(joined); strings.Join(again, "|") != strings.Join(got, "|") {
+				t.Errorf("round trip of %q = %q, want %q", joined, again, got)
+			}
+		})
+	}
+}
+
+func TestTagSuggestions(t *testing.T) {
+	available := []string{"backend", "Bug-bash", "frontend", "blocked"}
+
+	got := tagSuggestions("b", available, []string{"Blocked"})
+	if strings.Join(got, ",") != "backend,Bug-bash" {
+		t.Errorf("tagSuggestions = %v, want prefix matches excluding applied tags", got)
+	}
+	if got := tagSuggestions("", available, nil); got != nil {
+		t.Errorf("tagSuggestions with empty prefix = %v, want none", got)
+	}
+}
+
+func TestFormatIterationDates(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs *azdo.IterationAttributes
+		want  string
+	}{
+		{name: "nil attributes", attrs: nil, want: ""},
+		{name: "no dates", attrs: &azdo.IterationAttributes{TimeFrame: "future"}, want: ""},
+		{
+			name:  "start and finish",
+			attrs: &azdo.IterationAttributes{StartDate: "2024-01-01T00:00:00Z", FinishDate: "2024-01-14T00:00:00Z"},
+			want:  "Jan 01 – Jan 14",
+		},
+		{name: "start only", attrs: &azdo.IterationAttributes{StartDate: "2024-03-04T00:00:00Z"}, want: "from Mar 04"},
+		{name: "invalid dates", attrs: &azdo.IterationAttributes{StartDate: "soon", FinishDate: "later"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatIterationDates(tt.attrs, "Jan 02"); got != tt.want {
+				t.Errorf("formatIterationDates() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetIterationDisplayOrderGrouped(t *testing.T) {
+	iter := func(name, timeFrame, start string) azdo.Iteration {
+		return azdo.Iteration{
+			ID:         name,
+			Name:       name,
+			Path:       "Project\\" + name,
+			Attributes: &azdo.IterationAttributes{TimeFrame: timeFrame, StartDate: start},
+		}
+	}
+	m := Model{
+		iterations: []azdo.Iteration{
+			iter("Sprint 5", "future", "2024-03-01T00:00:00Z"),
+			iter("Sprint 2", "past", "2024-01-15T00:00:00Z"),
+			{ID: "backlog", Name: "Backlog", Path: "Project\\Backlog"},
+			iter("Sprint 3", "current", "2024-02-01T00:00:00Z"),
+			iter("Sprint 1", "past", "2024-01-01T00:00:00Z"),
+			iter("Sprint 4", "future", "2024-02-15T00:00:00Z"),
+		},
+		selectedItem: &azdo.WorkItem{Fields: azdo.WorkItemFields{IterationPath: "Project\\Sprint 5"}},
+	}
+
+	var names []string
+	for _, it := range m.getIterationDisplayOrder() {
+		names = append(names, it.Name)
+	}
+	want := "Sprint 1,Sprint 2,Sprint 3,Sprint 4,Sprint 5,Backlog"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("getIterationDisplayOrder() = %s, want %s", got, want)
+	}
+}
+
+func TestIterationGroupFromDates(t *testing.T) {
+	now := time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		attrs *azdo.IterationAttributes
+		want  string
+	}{
+		{name: "no attributes", attrs: nil, want: ""},
+		{name: "timeframe wins", attrs: &azdo.IterationAttributes{TimeFrame: "Current", FinishDate: "2024-01-01T00:00:00Z"}, want: "current"},
+		{name: "finished", attrs: &azdo.IterationAttributes{StartDate: "2024-01-01T00:00:00Z", FinishDate: "2024-01-14T00:00:00Z"}, want: "past"},
+		{name: "in progress", attrs: &azdo.IterationAttributes{StartDate: "2024-02-01T00:00:00Z", FinishDate: "2024-02-14T00:00:00Z"}, want: "current"},
+		{name: "not started", attrs: &azdo.IterationAttributes{StartDate: "2024-03-01T00:00:00Z"}, want: "future"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := iterationGroup(azdo.Iteration{Attributes: tt.attrs}, now); got != tt.want {
+				t.Errorf("iterationGroup() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldLimit(t *testing.T) {
+	if got := fieldLimit("System.Title"); got != 255 {
+		t.Errorf("fieldLimit(System.Title) = %d, want 255", got)
+	}
+	if got := fieldLimit("System.Description"); got != 0 {
+		t.Errorf("fieldLimit(System.Description) = %d, want 0 for an unknown limit", got)
+	}
+}
+
+func TestRenderCharCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		ref   string
+		want  string
+		warn  string
+	}{
+		{"short title", "Fix bug", "System.Title", "7/255 characters", ""},
+		{"below threshold", strings.Repeat("a", 229), "System.Title", "229/255 characters", ""},
+		{"at threshold", strings.Repeat("a", 230), "System.Title", "230/255 characters", "approaching the limit"},
+		{"over limit", strings.Repeat("a", 256), "System.Title", "256/255 characters", "over the limit"},
+		{"multibyte counted as runes", "héllo", "System.Title", "5/255 characters", ""},
+		{"no known limit", "Some description", "System.Description", "16 characters", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderCharCount(tt.value, tt.ref)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("renderCharCount() = %q, want %q", got, tt.want)
+			}
+			if tt.warn != "" && !strings.Contains(got, tt.warn) {
+				t.Errorf("renderCharCount() = %q, want warning %q", got, tt.warn)
+			}
+			if tt.warn == "" && strings.Contains(got, "limit") {
+				t.Errorf("renderCharCount() = %q, want no warning", got)
+			}
+		})
+	}
+}
+
+func TestCommentsToText(t *testing.T) {
+	comments := []azdo.Comment{
+		{
+			Text:        `<div>Looks good, <a href="#" data-vss-mention="version:2.0,abc-123">@Jane Doe</a> please review</div>`,
+			CreatedBy:   azdo.IdentityRef{DisplayName: "John Smith"},
+			CreatedDate: "2024-01-15T10:30:00Z",
+		},
+		{
+			Text:        `<p>See <a href="https://example.com/spec">the spec</a></p><p>Fixed &amp; deployed</p>`,
+			CreatedBy:   azdo.IdentityRef{DisplayName: "Jane Doe"},
+			CreatedDate: "2024-01-16T09:05:00Z",
+		},
+	}
+
+	want := "John Smith - 2024-01-15 10:30\n" +
+		"Looks good, @Jane Doe please review\n" +
+		"\n" +
+		"Jane Doe - 2024-01-16 09:05\n" +
+		"See the spec (https://example.com/spec)\n" +
+		"Fixed & deployed"
+	if got := commentsToText(comments, "2006-01-02 15:04"); got != want {
+		t.Errorf("commentsToText() =\n%s\nwant\n%s", got, want)
+	}
+
+	// The copy uses the configured date and time formats
+	if got := commentsToText(comments[:1], AppConfig{}.dateTimeLayout()); !strings.HasPrefix(got, "John Smith - Jan 15, 10:30\n") {
+		t.Errorf("commentsToText() with the default layout = %q", got)
+	}
+
+	if got := commentsToText(nil, AppConfig{}.dateTimeLayout()); got != "" {
+		t.Errorf("commentsToText(nil) = %q, want empty", got)
+	}
+}
+
+func TestCreatedLabel(t *testing.T) {
+	created := "2024-01-10T08:30:00Z"
+	localDate := time.Date(2024, 1, 10, 8, 30, 0, 0, time.UTC).Local().Format("Jan 02, 15:04")
+
+	tests := []struct {
+		name string
+		wi   azdo.WorkItem
+		want string
+	}{
+		{"both", azdo.WorkItem{Fields: azdo.WorkItemFields{CreatedBy: &azdo.IdentityRef{DisplayName: "Jane Doe"}, CreatedDate: created}}, "Created: " + localDate + " by Jane Doe"},
+		{"unique name fallback", azdo.WorkItem{Fields: azdo.WorkItemFields{CreatedBy: &azdo.IdentityRef{UniqueName: "jane@example.com"}}}, "Created by: jane@example.com"},
+		{"date only", azdo.WorkItem{Fields: azdo.WorkItemFields{CreatedDate: created}}, "Created: " + localDate},
+		{"neither", azdo.WorkItem{}, ""},
+	}
+	for _, tt := range tests {
+		if got := createdLabel(&tt.wi, "Jan 02, 15:04"); got != tt.want {
+			t.Errorf("%s: createdLabel() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDetailShowsCreated(t *testing.T) {
+	m := setupDetailModel()
+	m.selectedItem.Fields.CreatedBy = &azdo.IdentityRef{DisplayName: "Jane Doe"}
+	m.selectedItem.Fields.CreatedDate = "2024-01-10T08:30:00Z"
+	if view := m.View(); !strings.Contains(view, "by Jane Doe") {
+		t.Errorf("Expected the creator in the detail view, got:\n%s", view)
+	}
+
+	// The configured date format applies
+	m.appConfig.DateFormat = "2006-01-02"
+	want := time.Date(2024, 1, 10, 8, 30, 0, 0, time.UTC).Local().Format("2006-01-02, 15:04")
+	if view := m.View(); !strings.Contains(view, "Created: "+want) {
+		t.Errorf("Expected the created date as %q, got:\n%s", want, view)
+	}
+}
+
+func TestDetailRefreshFetchesItemCommentsAndRelated(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	m := setupDetailModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/comments") {
+			_ = json.NewEncoder(w).Encode(azdo.CommentsResponse{Comments: []azdo.Comment{{ID: 1}}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(azdo.WorkItem{ID: 1, Fields: azdo.WorkItemFields{Title: "Renamed", State: "Resolved"}})
+	})
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyF5})
+	m = newModel.(Model)
+	if cmd == nil || !m.loading {
+		t.Fatal("Expected f5 to start a refresh")
+	}
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) != 3 {
+		t.Fatalf("Expected three fetch commands, got %#v", batch)
+	}
+
+	var refreshed, comments, related bool
+	for _, c := range batch {
+		switch msg := c().(type) {
+		case refreshedItemMsg:
+			refreshed = msg.workItemID == 1
+		case commentsMsg:
+			comments = msg.workItemID == 1
+		case relatedItemsMsg:
+			related = msg.workItemID == 1
+		}
+	}
+	if !refreshed || !comments || !related {
+		t.Errorf("Expected item, comments and related fetches for #1, got item=%v comments=%v related=%v (paths %v)", refreshed, comments, related, paths)
+	}
+}
+
+func TestDetailRefreshKeepsEditedInputs(t *testing.T) {
+	m := setupDetailModel()
+	m.detailInputs[1].SetValue("Closed") // unsaved edit
+
+	item := &azdo.WorkItem{ID: 1, Fields: azdo.WorkItemFields{Title: "Renamed", State: "Resolved", WorkItemType: "Bug"}}
+	newModel, _ := m.Update(refreshedItemMsg{workItemID: 1, item: item})
+	m = newModel.(Model)
+
+	if got := m.detailInputs[0].Value(); got != "Renamed" {
+		t.Errorf("Title = %q, want the refreshed value", got)
+	}
+	if got := m.detailInputs[1].Value(); got != "Closed" {
+		t.Errorf("State = %q, want the unsaved edit kept", got)
+	}
+	if m.selectedItem.Fields.Title != "Renamed" || m.workItems[0].Fields.Title != "Renamed" {
+		t.Error("Expected the refreshed item to replace the open and board copies")
+	}
+}
+
+func TestDetailPriorityOmittedWhenAbsent(t *testing.T) {
+	m := setupDetailModel()
+	if strings.Contains(m.View(), "Priority:") {
+		t.Error("Expected no priority line when the field is absent")
+	}
+
+	priority := 2
+	m.selectedItem.Fields.Priority = &priority
+	if !strings.Contains(m.View(), "Priority: 2") {
+		t.Error("Expected the priority to be shown when present")
+	}
+}
+
+func TestTruncateWidthNeverBreaksRunes(t *testing.T) {
+	inputs := []string{
+		"Fix crash when title contains émojis 🎉🎉🎉 and accents",
+		"ログイン画面でエラーが発生する問題を修正",
+		"👨‍👩‍👧 family emoji title that is fairly long",
+	}
+	for _, s := range inputs {
+		for maxLen := 1; maxLen <= 30; maxLen++ {
+			got := truncateWidth(s, maxLen)
+			if !utf8.ValidString(got) {
+				t.Fatalf("truncateWidth(%q, %d) = %q is not valid UTF-8", s, maxLen, got)
+			}
+			if w := lipgloss.Width(got); w > maxLen {
+				t.Errorf("truncateWidth(%q, %d) = %q is %d columns wide", s, maxLen, got, w)
+			}
+		}
+	}
+}
+
+func TestPlanningInputsAcceptOnlyNumbers(t *testing.T) {
+	m := NewModel()
+	m.planningInputs[0].Focus()
+
+	for _, r := range "1a.5.x 2" {
+		_ = m.updatePlanningInputs(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if got := m.planningInputs[0].Value(); got != "1.52" {
+		t.Errorf("Planning input = %q, want letters, spaces and a second dot ignored", got)
+	}
+
+	// Pasted text keeps only the numeric characters
+	m.planningInputs[0].SetValue("")
+	_ = m.updatePlanningInputs(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3h.25"), Paste: true})
+	if got := m.planningInputs[0].Value(); got != "3.25" {
+		t.Errorf("Pasted planning input = %q, want %q", got, "3.25")
+	}
+}
+
+func TestPriorityInputAcceptsOnlyDigits(t *testing.T) {
+	m := NewModel()
+	m.createInputs[2].Focus()
+
+	for _, r := range "p2.1" {
+		_ = m.updateCreateInputs(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if got := m.createInputs[2].Value(); got != "21" {
+		t.Errorf("Priority input = %q, want only digits", got)
+	}
+}
+
+func TestDetailActivityPicker(t *testing.T) {
+	var requests []string
+	m := setupDetailModel()
+	m.selectedItem.Fields.WorkItemType = "Task"
+	m.selectedItem.Fields.Activity = "Design"
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "PATCH" {
+			_, _ = w.Write([]byte(`{"id":1,"fields":{"System.WorkItemType":"Task","Microsoft.VSTS.Common.Activity":"Testing"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"allowedValues": ["Design", "Development", "Testing"]}`))
+	})
+
+	if !strings.Contains(m.View(), "Activity: Design") {
+		t.Error("Expected the detail view to show the Task's activity")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}, Alt: true})
+	m = updated.(Model)
+	if !m.activityExpanded || cmd == nil {
+		t.Fatal("Expected alt+a to open the activity picker and fetch the allowed values")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if len(m.activities) != 3 || m.activityCursor != 0 {
+		t.Fatalf("Expected the allowed values with the current one selected, got %v at %d", m.activities, m.activityCursor)
+	}
+
+	for range 2 {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = updated.(Model)
+	}
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("Expected enter to save the selected activity")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if m.selectedItem.Fields.Activity != "Testing" || m.activityExpanded {
+		t.Errorf("Expected the activity to be saved and the picker closed, got %q", m.selectedItem.Fields.Activity)
+	}
+	if len(requests) != 2 || !strings.HasSuffix(requests[0], "/fields/Microsoft.VSTS.Common.Activity") || !strings.HasPrefix(requests[1], "PATCH ") {
+		t.Errorf("Unexpected requests %v", requests)
+	}
+}
+
+func TestDetailActivityFetchError(t *testing.T) {
+	m := setupDetailModel()
+	m.selectedItem.Fields.WorkItemType = "Task"
+	m.activityExpanded = true
+	if !strings.Contains(m.View(), "Loading activities...") {
+		t.Fatal("Expected the picker to show it's loading")
+	}
+
+	updated, _ := m.Update(activitiesMsg{workItemType: "Task", err: errors.New("API error 500: oops")})
+	m = updated.(Model)
+	view := m.View()
+	if m.activityExpanded || strings.Contains(view, "Loading activities...") {
+		t.Error("Expected a failed fetch to stop showing the loading line")
+	}
+	if m.err == nil || !strings.Contains(view, "oops") {
+		t.Errorf("Expected the error to be shown, got err %v", m.err)
+	}
+}
+
+func TestDetailActivityHiddenForBugs(t *testing.T) {
+	m := setupDetailModel()
+	if strings.Contains(m.View(), "Activity:") {
+		t.Error("Expected no Activity line on a Bug without an activity")
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}, Alt: true})
+	if updated.(Model).activityExpanded || cmd != nil {
+		t.Error("Expected alt+a to do nothing on a Bug")
+	}
+}
+
+func TestAllowedValue(t *testing.T) {
+	states := []string{"New", "Active", "Resolved", "Closed"}
+	tests := []struct {
+		values []string
+		in     string
+		want   string
+		ok     bool
+	}{
+		{states, "Active", "Active", true},
+		{states, " resolved", "Resolved", true},
+		{states, "Doing", "Doing", false},
+		{nil, "Anything", "Anything", true},
+	}
+	for _, tt := range tests {
+		got, ok := allowedValue(tt.values, tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("allowedValue(%v, %q) = %q, %v; want %q, %v", tt.values, tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDetailStateUsesAllowedValues(t *testing.T) {
+	var patchBody []byte
+	m := setupDetailModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "PATCH" {
+			patchBody, _ = io.ReadAll(r.Body)
+			_, _ = w.Write([]byte(`{"id":1,"fields":{"System.State":"Resolved"}}`))
+			return
+		}
+		if !strings.HasSuffix(r.URL.Path, "/workitemtypes/Bug/fields/System.State") {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"allowedValues": ["New", "Active", "Resolved", "Closed"]}`))
+	})
+
+	// Focusing the State field loads the type's states
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}, Alt: true})
+	m = updated.(Model)
+	var loaded bool
+	for _, c := range cmd().(tea.BatchMsg) {
+		if c == nil {
+			continue
+		}
+		if msg, ok := c().(stateValuesMsg); ok {
+			updated, _ = m.Update(msg)
+			m = updated.(Model)
+			loaded = true
+		}
+	}
+	if !loaded || len(m.stateValues) != 4 {
+		t.Fatalf("Expected the Bug states to be loaded, got %v", m.stateValues)
+	}
+	if !strings.Contains(m.View(), "(New, Active, Resolved, Closed)") {
+		t.Error("Expected the State hint to list the type's states")
+	}
+
+	// A state the type doesn't have is caught before saving
+	m.detailInputs[1].SetValue("Doing")
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+	if cmd != nil || m.err == nil || !strings.Contains(m.err.Error(), `"Doing" isn't a Bug state`) {
+		t.Errorf("Expected an invalid state error, got %v", m.err)
+	}
+
+	// Casing is corrected to match the server
+	m.detailInputs[1].SetValue("resolved")
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("Expected a valid state to be saved")
+	}
+	if msg, ok := cmd().(updateWorkItemMsg); !ok || msg.err != nil {
+		t.Fatalf("Expected the update to be sent, got %#v", msg)
+	}
+	if !strings.Contains(string(patchBody), `"Resolved"`) {
+		t.Errorf("Expected the state saved as Resolved, got %s", patchBody)
+	}
+}
+
+func TestStateChoices(t *testing.T) {
+	states := []azdo.WorkItemState{
+		{Name: "New", Transitions: []string{"Active", "Removed"}},
+		{Name: "Active", Transitions: []string{"New", "Resolved"}},
+		{Name: "Resolved", Transitions: []string{"Active", "Closed"}},
+		{Name: "Closed", Transitions: []string{"Active"}},
+		{Name: "Removed", Transitions: []string{"New"}},
+	}
+	got := stateChoices(states, "active")
+	want := []string{"New", "Active", "Resolved"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := stateChoices(states, "Unknown"); len(got) != 0 {
+		t.Errorf("Expected no choices for an unknown state, got %v", got)
+	}
+}
+
+func TestDetailCycleState(t *testing.T) {
+	m := setupDetailModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.HasSuffix(r.URL.Path, "/workitemtypes/Bug") {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{
+			"states": [{"name": "New"}, {"name": "Active"}, {"name": "Resolved"}, {"name": "Closed"}],
+			"transitions": {
+				"New": [{"to": "Active"}],
+				"Active": [{"to": "New"}, {"to": "Resolved"}],
+				"Resolved": [{"to": "Active"}, {"to": "Closed"}],
+				"Closed": [{"to": "Active"}]
+			}
+		}`))
+	})
+
+	// The first press loads the workflow, then applies the step
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}, Alt: true})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("Expected the workflow to be fetched")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if got := m.detailInputs[1].Value(); got != "Resolved" {
+		t.Fatalf("Expected Active to advance to Resolved, got %q", got)
+	}
+	if !strings.Contains(m.View(), "State: Active → Resolved") {
+		t.Error("Expected the unsaved state change to be shown")
+	}
+
+	// Further presses use the loaded workflow and wrap around
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}, Alt: true})
+	m = updated.(Model)
+	if cmd != nil {
+		t.Error("Expected no fetch once the workflow is loaded")
+	}
+	if got := m.detailInputs[1].Value(); got != "New" {
+		t.Errorf("Expected Resolved to wrap to New, got %q", got)
+	}
+
+	// alt+S goes back; Closed isn't reachable from Active so it's skipped
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}, Alt: true})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}, Alt: true})
+	m = updated.(Model)
+	if got := m.detailInputs[1].Value(); got != "Active" {
+		t.Errorf("Expected two steps back from New to reach Active, got %q", got)
+	}
+	if strings.Contains(m.View(), "State: Active →") {
+		t.Error("Expected no unsaved indicator once the State matches the saved value")
+	}
+}
+
+func TestDetailCommentOrder(t *testing.T) {
+	older := azdo.Comment{ID: 1, Text: "Older note", CreatedDate: "2024-03-01T10:00:00Z", CreatedBy: azdo.IdentityRef{DisplayName: "Alice"}}
+	newer := azdo.Comment{ID: 2, Text: "Newer note", CreatedDate: "2024-03-05T10:00:00Z", CreatedBy: azdo.IdentityRef{DisplayName: "Bob"}}
+	var order string
+	m := setupDetailModel()
+	m.height = 80
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		order = r.URL.Query().Get("order")
+		comments := []azdo.Comment{older, newer}
+		if order == "desc" {
+			comments = []azdo.Comment{newer, older}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(azdo.CommentsResponse{Comments: comments})
+	})
+	m.comments = []azdo.Comment{older, newer}
+
+	// firstComment returns which note the expanded list starts with
+	firstComment := func(m Model) string {
+		m.commentsExpanded = true
+		view := m.View()
+		o, n := strings.Index(view, "Older note"), strings.Index(view, "Newer note")
+		if o < 0 || n < 0 {
+			t.Fatalf("Expected both comments in the view, got:\n%s", view)
+		}
+		if o < n {
+			return "Older note"
+		}
+		return "Newer note"
+	}
+	if got := firstComment(m); got != "Older note" {
+		t.Errorf("Expected oldest first by default, got %q first", got)
+	}
+	if !strings.Contains(m.View(), "Latest: Bob") {
+		t.Error("Expected the summary to show the newest comment")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}, Alt: true})
+	m = updated.(Model)
+	if !m.commentsNewestFirst || cmd == nil {
+		t.Fatal("Expected alt+o to reload the comments newest first")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if order != "desc" {
+		t.Errorf("Expected the comments to be requested newest first, got order=%q", order)
+	}
+	if got := firstComment(m); got != "Newer note" {
+		t.Errorf("Expected newest first after the toggle, got %q first", got)
+	}
+	// The summary still picks the newest comment, now at the top of the list
+	if !strings.Contains(m.View(), "Latest: Bob") {
+		t.Error("Expected the summary to follow the new order")
+	}
+
+	// A page requested in the old order is dropped
+	updated, _ = m.Update(commentsMsg{workItemID: 1, comments: []azdo.Comment{older, newer}})
+	m = updated.(Model)
+	if m.comments[0].ID != newer.ID {
+		t.Error("Expected comments loaded in the old order to be ignored")
+	}
+}
+
+func TestDetailSkipsReadOnlyFields(t *testing.T) {
+	m := setupDetailModel()
+	updated, _ := m.Update(typeFieldsMsg{workItemType: "Bug", fields: []azdo.WorkItemTypeField{
+		{ReferenceName: "System.Title"},
+		{ReferenceName: "System.State", ReadOnly: true},
+		{ReferenceName: "System.AssignedTo"},
+	}})
+	m = updated.(Model)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	if m.detailFocus != 2 {
+		t.Errorf("Expected tab to skip the read-only State, got focus %d", m.detailFocus)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m = updated.(Model)
+	if m.detailFocus != 0 {
+		t.Errorf("Expected shift+tab to skip the read-only State, got focus %d", m.detailFocus)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}, Alt: true})
+	m = updated.(Model)
+	if m.detailFocus != 0 || !strings.Contains(m.message, "State is read-only") {
+		t.Errorf("Expected alt+2 to refuse the read-only State, got focus %d, message %q", m.detailFocus, m.message)
+	}
+	if !strings.Contains(m.View(), "State (read-only)") {
+		t.Error("Expected the State to be marked read-only")
+	}
+
+	// Fields of another type don't apply
+	m.selectedItem = &m.workItems[1]
+	if m.detailFieldReadOnly(1) {
+		t.Error("Expected the Bug's read-only fields not to apply to a Task")
+	}
+}
+
+func TestDetailMovesOffReadOnlyFocus(t *testing.T) {
+	m := setupDetailModel()
+	updated, _ := m.Update(typeFieldsMsg{workItemType: "Bug", fields: []azdo.WorkItemTypeField{
+		{ReferenceName: "System.Title", ReadOnly: true},
+	}})
+	m = updated.(Model)
+	if m.detailFocus != 1 {
+		t.Errorf("Expected focus to move off the read-only Title, got %d", m.detailFocus)
+	}
+}
+
+func TestDetailOpenSkipsCachedReadOnlyFields(t *testing.T) {
+	m := setupBoardModel()
+	m.readOnlyFieldsType = "Bug"
+	m.readOnlyFields = map[string]bool{"System.Title": true}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.detailFocus != 1 || m.detailInputs[0].Focused() {
+		t.Errorf("Expected opening a Bug to focus past the cached read-only Title, got focus %d", m.detailFocus)
+	}
+
+	// A read-only field changed anyway isn't saved
+	m.detailInputs[0].SetValue("Renamed")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+	if cmd != nil || m.pendingWrite != nil || m.err == nil || !strings.Contains(m.err.Error(), "Title is read-only") {
+		t.Errorf("Expected ctrl+s to refuse the edited read-only Title, got err %v", m.err)
+	}
+
+	// Navigating to another Bug re-targets the focus too
+	m.detailFocus = 0
+	other := azdo.WorkItem{ID: 3, Fields: azdo.WorkItemFields{Title: "Third", WorkItemType: "Bug"}}
+	updated, _ = m.navigateToWorkItem(&other)
+	m = updated.(Model)
+	if m.detailFocus != 1 {
+		t.Errorf("Expected navigating to a Bug to focus past the read-only Title, got focus %d", m.detailFocus)
+	}
+}
+
+func TestDetailCommentRawMode(t *testing.T) {
+	raw := `<div>Deploy <b>blocked</b> &amp; waiting</div>`
+	m := setupDetailModel()
+	m.height = 80
+	m.commentsExpanded = true
+	m.comments = []azdo.Comment{{ID: 1, Text: raw, CreatedDate: "2024-03-01T10:00:00Z", CreatedBy: azdo.IdentityRef{DisplayName: "Alice"}}}
+
+	if got := m.commentText(raw, ""); got != stripHTMLTags(raw, "") {
+		t.Errorf("Expected comments to be cleaned up by default, got %q", got)
+	}
+	if view := m.View(); !strings.Contains(view, "Deploy blocked & waiting") || strings.Contains(view, "<b>") {
+		t.Errorf("Expected the rendered comment, got:\n%s", view)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}, Alt: true})
+	m = updated.(Model)
+	if !m.rawMode {
+		t.Fatal("Expected alt+h to switch to raw HTML")
+	}
+	if got := m.commentText(raw, ""); got != raw {
+		t.Errorf("Expected the raw HTML, got %q", got)
+	}
+	if view := m.View(); !strings.Contains(view, raw) {
+		t.Errorf("Expected the raw comment in the view, got:\n%s", view)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}, Alt: true})
+	m = updated.(Model)
+	if m.rawMode || strings.Contains(m.View(), "<b>") {
+		t.Error("Expected alt+h again to show the cleaned-up text")
+	}
+}
+
+func TestDetailStateMenuFetchError(t *testing.T) {
+	m := setupDetailModel()
+	m.stateMenuExpanded = true
+	if !strings.Contains(m.View(), "Loading states...") {
+		t.Fatal("Expected the menu to show it's loading")
+	}
+
+	updated, _ := m.Update(workflowStatesMsg{workItemType: "Bug", err: errors.New("API error 500: oops")})
+	m = updated.(Model)
+	view := m.View()
+	if m.stateMenuExpanded || strings.Contains(view, "Loading states...") {
+		t.Error("Expected a failed fetch to stop showing the loading line")
+	}
+	if m.err == nil || !strings.Contains(view, "oops") {
+		t.Errorf("Expected the error to be shown, got err %v", m.err)
+	}
+}
+
+func TestDetailStateMenu(t *testing.T) {
+	var patched []map[string]interface{}
+	m := setupDetailModel()
+	m.height = 80
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/workitemtypes/Bug"):
+			_, _ = w.Write([]byte(`{
+				"states": [{"name": "New"}, {"name": "Active"}, {"name": "Resolved"}, {"name": "Closed"}],
+				"transitions": {"Active": [{"to": "New"}, {"to": "Resolved"}]}
+			}`))
+		case r.Method == "PATCH" && strings.HasSuffix(r.URL.Path, "/workitems/1"):
+			_ = json.NewDecoder(r.Body).Decode(&patched)
+			_, _ = w.Write([]byte(`{"id": 1, "fields": {"System.Title": "First Item", "System.State": "Resolved", "System.WorkItemType": "Bug"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}, Alt: true})
+	m = updated.(Model)
+	if !m.stateMenuExpanded || cmd == nil {
+		t.Fatal("Expected alt+m to open the menu and load the workflow")
+	}
+	if !strings.Contains(m.View(), "Loading states...") {
+		t.Error("Expected the menu to show it's loading")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	// Closed isn't reachable from Active, so it's left out; the cursor starts on the current state
+	if got := m.stateMenuChoices(); !slices.Equal(got, []string{"New", "Active", "Resolved"}) {
+		t.Errorf("Expected the reachable states, got %v", got)
+	}
+	if view := m.View(); !strings.Contains(view, "✓ Active") {
+		t.Errorf("Expected the current state to be marked, got:\n%s", view)
+	}
+	if got := m.stateMenuChoices()[m.stateMenuCursor]; got != "Active" {
+		t.Errorf("Expected the cursor on Active, got %q", got)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("Expected selecting a state to dispatch the update")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	if len(patched) != 1 || patched[0]["path"] != "/fields/System.State" || patched[0]["value"] != "Resolved" {
+		t.Errorf("Expected only the state to be set to Resolved, got %v", patched)
+	}
+	if m.stateMenuExpanded || m.selectedItem.Fields.State != "Resolved" || m.detailInputs[1].Value() != "Resolved" {
+		t.Error("Expected the menu to close and the item to show its new state")
+	}
+}
@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// errorLogSize is the number of recent errors kept in the error log
+const errorLogSize = 50
+
+// errorDisplayLimit is the longest error shown in full under a view; alt+E copies the rest
+const errorDisplayLimit = 300
+
+// writeClipboard copies text to the system clipboard
+var writeClipboard = clipboard.WriteAll
+
+// renderError renders the current error under a view, truncated so long API
+// responses don't push the view off screen
+func (m Model) renderError() string {
+	text := m.err.Error()
+	line := errorStyle.Render("Error: " + truncateWidth(text, errorDisplayLimit))
+	if lipgloss.Width(text) > errorDisplayLimit {
+		line += " " + helpStyle.UnsetMarginTop().Render("(alt+E: copy full error)")
+	}
+	return line
+}
+
+// copyError copies the full text of the current error to the clipboard
+func (m Model) copyError() Model {
+	if m.err == nil {
+		return m
+	}
+	if err := writeClipboard(m.err.Error()); err != nil {
+		m.err = fmt.Errorf("failed to write clipboard: %w", err)
+		return m
+	}
+	m.message = "Copied error to clipboard"
+	return m
+}
+
+// errorLogEntry is an error recorded in the error log
+type errorLogEntry struct {
+	at      time.Time
+	message string
+}
+
+// logError records err in the error log, dropping the oldest entry once the log is full
+func (m *Model) logError(err error) {
+	if err == nil {
+		return
+	}
+	m.errorLog = append(m.errorLog, errorLogEntry{at: time.Now(), message: err.Error()})
+	if len(m.errorLog) > errorLogSize {
+		m.errorLog = m.errorLog[len(m.errorLog)-errorLogSize:]
+	}
+}
+
+func (m Model) updateErrorLog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "alt+e":
+		m.showErrorLog = false
+	case "x":
+		m.errorLog = nil
+	}
+	return m, nil
+}
+
+func (m Model) viewErrorLog() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("⚠ Error Log (%d)", len(m.errorLog))))
+	b.WriteString("\n\n")
+
+	if len(m.errorLog) == 0 {
+		b.WriteString("No errors this session.")
+		b.WriteString("\n")
+	}
+
+	timeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	// Newest first, since the latest error is usually the one being investigated
+	for i := len(m.errorLog) - 1; i >= 0; i-- {
+		entry := m.errorLog[i]
+		b.WriteString(timeStyle.Render(entry.at.Format("15:04:05")))
+		b.WriteString(" ")
+		b.WriteString(errorStyle.Render(truncateWidth(entry.message, 200)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpStyle.Render("esc/q: close • x: clear log"))
+
+	return boxStyle.Render(b.String())
+}
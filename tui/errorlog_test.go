@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestErrorsAppendedToLog(t *testing.T) {
+	m := setupBoardModel()
+
+	newModel, _ := m.Update(workItemsMsg{err: errors.New("first failure")})
+	m = newModel.(Model)
+	newModel, _ = m.Update(workItemsMsg{err: errors.New("second failure")})
+	m = newModel.(Model)
+
+	if len(m.errorLog) != 2 {
+		t.Fatalf("errorLog has %d entries, want 2", len(m.errorLog))
+	}
+	if m.errorLog[0].message != "first failure" || m.errorLog[1].message != "second failure" {
+		t.Errorf("errorLog = %+v, want both errors in order", m.errorLog)
+	}
+
+	// A message that leaves the same error in place isn't logged again
+	newModel, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = newModel.(Model)
+	if len(m.errorLog) != 2 {
+		t.Errorf("errorLog has %d entries, want the unchanged error logged once", len(m.errorLog))
+	}
+}
+
+func TestErrorLogBounded(t *testing.T) {
+	var m Model
+	for i := 0; i < errorLogSize+5; i++ {
+		m.logError(fmt.Errorf("error %d", i))
+	}
+	if len(m.errorLog) != errorLogSize {
+		t.Fatalf("errorLog has %d entries, want %d", len(m.errorLog), errorLogSize)
+	}
+	if m.errorLog[0].message != "error 5" {
+		t.Errorf("oldest entry = %q, want the oldest errors dropped", m.errorLog[0].message)
+	}
+}
+
+func TestErrorLogOverlay(t *testing.T) {
+	m := setupBoardModel()
+	m.logError(errors.New("connection reset"))
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}, Alt: true})
+	m = newModel.(Model)
+	if !m.showErrorLog {
+		t.Fatal("alt+e should open the error log")
+	}
+	view := m.View()
+	if !strings.Contains(view, "Error Log (1)") || !strings.Contains(view, "connection reset") {
+		t.Errorf("error log overlay should list recent errors, got %q", view)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.showErrorLog || m.view != ViewBoard {
+		t.Error("esc should close the error log and return to the board")
+	}
+}
+
+func TestCopyFullError(t *testing.T) {
+	var copied string
+	orig := writeClipboard
+	writeClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+	t.Cleanup(func() { writeClipboard = orig })
+
+	m := setupBoardModel()
+	body := strings.Repeat("x", errorDisplayLimit) + " end of response"
+	m.err = fmt.Errorf("API error 500: %s", body)
+
+	view := m.View()
+	if strings.Contains(view, "end of response") || !strings.Contains(view, "alt+E: copy full error") {
+		t.Errorf("Expected the displayed error to be truncated with a copy hint, got:\n%s", view)
+	}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}, Alt: true})
+	m = newModel.(Model)
+	if copied != "API error 500: "+body {
+		t.Errorf("Expected the full error to be copied, got %q", copied)
+	}
+	if m.message != "Copied error to clipboard" {
+		t.Errorf("message = %q", m.message)
+	}
+
+	// Short errors are shown whole, without the hint
+	m.err = errors.New("not found")
+	if view := m.View(); !strings.Contains(view, "Error: not found") || strings.Contains(view, "alt+E") {
+		t.Errorf("Expected a short error in full, got:\n%s", view)
+	}
+}
@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fuzzyMatchStyle highlights the title characters matched by the fuzzy filter
+var fuzzyMatchStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("212")).Bold(true)
+
+// fuzzyMatch reports whether every rune of query appears in text in order
+// (case-insensitively), returning a score and the rune positions matched in text.
+// Matches score higher when they start words or run consecutively, and lower the
+// later the first match appears.
+func fuzzyMatch(text, query string) (int, []int, bool) {
+	q := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	if len(q) == 0 {
+		return 0, nil, true
+	}
+	runes := []rune(strings.ToLower(text))
+	positions := make([]int, 0, len(q))
+	score := 0
+	qi := 0
+	for i := 0; i < len(runes) && qi < len(q); i++ {
+		if runes[i] != q[qi] {
+			continue
+		}
+		score++
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += 3 // start of a word
+		}
+		if len(positions) > 0 && positions[len(positions)-1] == i-1 {
+			score += 5 // follows the previous match
+		}
+		positions = append(positions, i)
+		qi++
+	}
+	if qi < len(q) {
+		return 0, nil, false
+	}
+	return score - positions[0], positions, true
+}
+
+// fuzzyFilter returns the items whose title fuzzy-matches query, best match first;
+// items scoring the same keep their order
+func fuzzyFilter(items []azdo.WorkItem, query string) []azdo.WorkItem {
+	type scored struct {
+		item  azdo.WorkItem
+		score int
+	}
+	var matches []scored
+	for _, wi := range items {
+		if score, _, ok := fuzzyMatch(wi.Fields.Title, query); ok {
+			matches = append(matches, scored{wi, score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b scored) int { return b.score - a.score })
+	filtered := make([]azdo.WorkItem, len(matches))
+	for i, match := range matches {
+		filtered[i] = match.item
+	}
+	return filtered
+}
+
+// highlightFuzzy truncates title to width display columns and highlights the runes the
+// fuzzy query matched in what remains
+func highlightFuzzy(title, query string, width int) string {
+	title = truncateWidth(title, width)
+	_, positions, ok := fuzzyMatch(title, query)
+	if !ok || len(positions) == 0 {
+		return title
+	}
+	var b strings.Builder
+	next := 0
+	for i, r := range []rune(title) {
+		if next < len(positions) && positions[next] == i {
+			b.WriteString(fuzzyMatchStyle.Render(string(r)))
+			next++
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// setFuzzyQuery narrows the board to the loaded items matching query, keeping the full
+// list so the filter can be changed or cleared without refetching
+func (m Model) setFuzzyQuery(query string) Model {
+	if m.fuzzyAll == nil {
+		m.fuzzyAll = m.workItems
+	}
+	m.fuzzyQuery = query
+	m.workItems = fuzzyFilter(m.fuzzyAll, query)
+	m.cursor = 0
+	return m
+}
+
+// clearFuzzy shows all loaded items again. Items changed while the filter was on
+// replace their older copies.
+func (m Model) clearFuzzy() Model {
+	if m.fuzzyAll == nil {
+		return m
+	}
+	current := make(map[int]azdo.WorkItem, len(m.workItems))
+	for _, wi := range m.workItems {
+		current[wi.ID] = wi
+	}
+	items := make([]azdo.WorkItem, len(m.fuzzyAll))
+	for i, wi := range m.fuzzyAll {
+		if updated, ok := current[wi.ID]; ok {
+			wi = updated
+		}
+		items[i] = wi
+	}
+	m.workItems = items
+	m.fuzzyAll = nil
+	m.fuzzyQuery = ""
+	m.fuzzyFiltering = false
+	m.cursor = 0
+	return m
+}
+
+// refilterFuzzy applies the fuzzy filter to newly loaded items
+func (m Model) refilterFuzzy() Model {
+	if m.fuzzyAll == nil {
+		return m
+	}
+	m.fuzzyAll = m.workItems
+	m.workItems = fuzzyFilter(m.fuzzyAll, m.fuzzyQuery)
+	if m.cursor >= len(m.workItems) {
+		m.cursor = max(len(m.workItems)-1, 0)
+	}
+	return m
+}
+
+// updateFuzzyPrompt handles key presses while typing the fuzzy filter, narrowing the board on each one
+func (m Model) updateFuzzyPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return m.clearFuzzy(), nil
+	case "enter":
+		m.fuzzyFiltering = false
+		if m.fuzzyQuery == "" {
+			return m.clearFuzzy(), nil
+		}
+		return m, nil
+	case "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil
+	case "down":
+		if m.cursor < len(m.workItems)-1 {
+			m.cursor++
+		}
+		return m, nil
+	case "backspace":
+		if m.fuzzyQuery != "" {
+			runes := []rune(m.fuzzyQuery)
+			return m.setFuzzyQuery(string(runes[:len(runes)-1])), nil
+		}
+		return m, nil
+	case " ":
+		return m.setFuzzyQuery(m.fuzzyQuery + " "), nil
+	}
+	if msg.Type == tea.KeyRunes {
+		return m.setFuzzyQuery(m.fuzzyQuery + string(msg.Runes)), nil
+	}
+	return m, nil
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fuzzyTestItems is a fixed set of titles for the fuzzy filter tests
+var fuzzyTestItems = []azdo.WorkItem{
+	{ID: 1, Fields: azdo.WorkItemFields{Title: "Fix login timeout"}},
+	{ID: 2, Fields: azdo.WorkItemFields{Title: "Refactor billing export"}},
+	{ID: 3, Fields: azdo.WorkItemFields{Title: "Login page styling"}},
+	{ID: 4, Fields: azdo.WorkItemFields{Title: "Update docs"}},
+	{ID: 5, Fields: azdo.WorkItemFields{Title: "flaky integration test"}},
+}
+
+// itemIDs returns the IDs of items in order
+func itemIDs(items []azdo.WorkItem) []int {
+	ids := make([]int, len(items))
+	for i, wi := range items {
+		ids[i] = wi.ID
+	}
+	return ids
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	_, positions, ok := fuzzyMatch("Fix Login", "flg")
+	if !ok || !slices.Equal(positions, []int{0, 4, 6}) {
+		t.Errorf("Expected f, L and g to match at 0, 4 and 6, got %v (ok=%v)", positions, ok)
+	}
+	if _, _, ok := fuzzyMatch("Fix Login", "gl"); ok {
+		t.Error("Expected characters out of order not to match")
+	}
+	// Consecutive matches at the start of a word beat scattered ones
+	tight, _, _ := fuzzyMatch("Login page", "log")
+	loose, _, _ := fuzzyMatch("Large orange bag", "log")
+	if tight <= loose {
+		t.Errorf("Expected %d > %d", tight, loose)
+	}
+}
+
+func TestFuzzyFilterRanks(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"login", []int{3, 1}},
+		{"fx", []int{1, 2}},
+		{"upd docs", []int{4}},
+		{"zzz", []int{}},
+		{"", []int{1, 2, 3, 4, 5}},
+	}
+	for _, tt := range tests {
+		if got := itemIDs(fuzzyFilter(fuzzyTestItems, tt.query)); !slices.Equal(got, tt.want) {
+			t.Errorf("fuzzyFilter(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestBoardFuzzyFilter(t *testing.T) {
+	m := setupBoardModel()
+	m.workItems = slices.Clone(fuzzyTestItems)
+	m.cursor = 4
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	m = updated.(Model)
+	if !m.fuzzyFiltering {
+		t.Fatal("Expected f to open the fuzzy filter")
+	}
+	for _, r := range "lo" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	// "lo" matches more titles than "log", which narrows them further
+	if got := itemIDs(m.workItems); !slices.Equal(got, []int{3, 1, 5, 2}) {
+		t.Errorf("Expected \"lo\" to match 3, 1, 5, 2, got %v", got)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	m = updated.(Model)
+	if got := itemIDs(m.workItems); !slices.Equal(got, []int{3, 1}) || m.cursor != 0 {
+		t.Errorf("Expected \"log\" to match 3, 1 with the cursor on the first, got %v at %d", got, m.cursor)
+	}
+
+	// Enter keeps the filter; newly loaded items are filtered too
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.fuzzyFiltering || m.fuzzyQuery != "log" {
+		t.Fatal("Expected enter to close the prompt and keep the filter")
+	}
+	items := append(slices.Clone(fuzzyTestItems), azdo.WorkItem{ID: 6, Fields: azdo.WorkItemFields{Title: "Blog post"}})
+	updated, _ = m.Update(workItemsMsg{items: items})
+	m = updated.(Model)
+	if got := itemIDs(m.workItems); !slices.Equal(got, []int{3, 1, 6}) {
+		t.Errorf("Expected the reloaded items to be filtered, got %v", got)
+	}
+
+	// Esc on the board shows everything again
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if got := itemIDs(m.workItems); !slices.Equal(got, []int{1, 2, 3, 4, 5, 6}) || m.fuzzyQuery != "" {
+		t.Errorf("Expected esc to clear the filter, got %v", got)
+	}
+}
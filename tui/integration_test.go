@@ -2,6 +2,12 @@ package tui
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -106,6 +112,54 @@ func TestDetailEscapeFlow(t *testing.T) {
 	}
 }
 
+func TestDetailConfirmEsc(t *testing.T) {
+	m := setupDetailModel()
+	m.appConfig.ConfirmEsc = true
+	if !strings.Contains(m.viewDetail(), "esc twice/ctrl+b: board") {
+		t.Error("With confirm_esc, the help should show how to leave")
+	}
+
+	// One esc only asks
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.view != ViewDetail {
+		t.Fatalf("With confirm_esc, one ESC should stay in detail view, got %v", m.view)
+	}
+	if !strings.Contains(m.viewDetail(), "Press esc again") {
+		t.Error("ESC should explain how to leave")
+	}
+
+	// Another key cancels, so the next esc asks again
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = newModel.(Model)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.view != ViewDetail {
+		t.Fatalf("ESC after another key should ask again, got %v", m.view)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.view != ViewBoard {
+		t.Errorf("A second ESC should return to board view, got %v", m.view)
+	}
+
+	// The create form shows the same guarded keys
+	m.view = ViewCreate
+	if view := m.viewCreate(); !strings.Contains(view, "esc twice/ctrl+b: board") || strings.Contains(view, "esc: cancel") {
+		t.Error("With confirm_esc, the create help should show how to leave")
+	}
+
+	// ctrl+b leaves straight away
+	m = setupDetailModel()
+	m.appConfig.ConfirmEsc = true
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	m = newModel.(Model)
+	if m.view != ViewBoard {
+		t.Errorf("ctrl+b should return to board view, got %v", m.view)
+	}
+}
+
 func TestCreateWorkItemFlow(t *testing.T) {
 	m := setupBoardModel()
 
@@ -1024,7 +1078,7 @@ func TestUpdateWorkItemCommand(t *testing.T) {
 	m.client = azdo.NewClient("org", "proj", "", "", "pat")
 	m.selectedItem = &azdo.WorkItem{ID: 123, Fields: azdo.WorkItemFields{Title: "Test"}}
 
-	cmd := m.updateWorkItem(123, "Title", "Active", "user@example.com", "tag1")
+	cmd := m.updateWorkItem(123, "Title", "Active", "", "user@example.com", "tag1")
 	if cmd == nil {
 		t.Error("updateWorkItem should return a command")
 	}
@@ -1123,6 +1177,30 @@ func TestAddHyperlinkCommand(t *testing.T) {
 	}
 }
 
+func TestAddHyperlinkArtifact(t *testing.T) {
+	var body string
+	m := setupDetailModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	})
+
+	msg := m.addHyperlink(1, "vstfs:///Git/Commit/proj%2Frepo%2Fabc123", "")().(addHyperlinkMsg)
+	if msg.err != nil {
+		t.Fatalf("Expected the commit to be linked, got %v", msg.err)
+	}
+	if !strings.Contains(body, `"rel":"ArtifactLink"`) || !strings.Contains(body, `"name":"Fixed in Commit"`) {
+		t.Errorf("Expected an ArtifactLink named after the commit, got %s", body)
+	}
+
+	msg = m.addHyperlink(1, "vstfs:///Unknown/Thing/1", "")().(addHyperlinkMsg)
+	if msg.err == nil || !strings.Contains(msg.err.Error(), "unknown artifact type") {
+		t.Errorf("Expected an unknown artifact type error, got %v", msg.err)
+	}
+}
+
 func TestRemoveHyperlinkCommand(t *testing.T) {
 	m := NewModel()
 	m.client = azdo.NewClient("org", "proj", "", "", "pat")
@@ -2502,3 +2580,890 @@ func TestWindowSizeMsg(t *testing.T) {
 		t.Errorf("Height should be 40, got %d", updated.height)
 	}
 }
+
+func TestDetailViewPageScroll(t *testing.T) {
+	m := setupDetailModel()
+	m.width = 120
+	m.height = 20
+
+	before := m.View()
+	if strings.Contains(before, "Planning") {
+		t.Fatal("Planning section should be below the fold at this height")
+	}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	m = newModel.(Model)
+
+	if m.detailScroll <= 0 {
+		t.Errorf("detailScroll = %d, want > 0 after pgdown", m.detailScroll)
+	}
+	if m.detailScroll > m.detailMaxScroll() {
+		t.Errorf("detailScroll = %d, should be clamped to %d", m.detailScroll, m.detailMaxScroll())
+	}
+
+	// Keep paging until the bottom of the view is reached
+	for i := 0; i < 10; i++ {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+		m = newModel.(Model)
+	}
+	if !strings.Contains(m.View(), "Planning") {
+		t.Error("Planning section should become visible after scrolling down")
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	m = newModel.(Model)
+	if m.detailScroll >= m.detailMaxScroll() {
+		t.Error("pgup should scroll back up")
+	}
+
+	// Arrow keys scroll one line at a time and leave the field focus alone
+	scroll, focus := m.detailScroll, m.detailFocus
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = newModel.(Model)
+	if m.detailScroll != scroll+1 || m.detailFocus != focus {
+		t.Errorf("down should scroll one line, got scroll %d (was %d), focus %d", m.detailScroll, scroll, m.detailFocus)
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m = newModel.(Model)
+	if m.detailScroll != scroll {
+		t.Errorf("up should scroll back one line, got %d, want %d", m.detailScroll, scroll)
+	}
+}
+
+func TestDetailPlanningSubmitInvalidNumber(t *testing.T) {
+	m := setupDetailModel()
+	m.planningExpanded = true
+	m.planningFields = []azdo.PlanningField{
+		{ReferenceName: "Microsoft.VSTS.Scheduling.StoryPoints", DisplayName: "Story Points"},
+	}
+	m.planningInputs[0].SetValue("5o")
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+
+	if cmd != nil {
+		t.Error("invalid planning input should not dispatch an update")
+	}
+	if m.loading {
+		t.Error("loading should not be set when validation fails")
+	}
+	if m.err == nil || !strings.Contains(m.viewDetail(), "Story Points: invalid number") {
+		t.Errorf("expected validation error to be displayed, got err = %v", m.err)
+	}
+}
+
+func TestDetailCloneWorkItem(t *testing.T) {
+	m := setupDetailModel()
+	m.username = "me@example.com"
+	m.workItemTypes = []string{"Task", "Bug", "User Story"}
+	m.selectedItem.Fields.Description = "Steps to reproduce"
+	priority := 1
+	m.selectedItem.Fields.Priority = &priority
+	m.selectedItem.Fields.AreaPath = "Project\\Team"
+	m.selectedItem.Fields.Tags = "recurring; ops"
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	m = newModel.(Model)
+
+	if m.view != ViewCreate {
+		t.Fatalf("view = %v, want ViewCreate", m.view)
+	}
+	if got := m.createInputs[0].Value(); got != "Copy of First Item" {
+		t.Errorf("title = %q, want %q", got, "Copy of First Item")
+	}
+	if got := m.createInputs[1].Value(); got != "Steps to reproduce" {
+		t.Errorf("description = %q, want copied description", got)
+	}
+	if got := m.createInputs[2].Value(); got != "1" {
+		t.Errorf("priority = %q, want %q", got, "1")
+	}
+	if got := m.createInputs[3].Value(); got != "me@example.com" {
+		t.Errorf("assignee = %q, want current user", got)
+	}
+	if m.createType != 1 {
+		t.Errorf("createType = %d, want index of Bug", m.createType)
+	}
+	if m.cloneAreaPath != "Project\\Team" || m.cloneTags != "recurring; ops" {
+		t.Errorf("clone area/tags = %q/%q, want copied from source item", m.cloneAreaPath, m.cloneTags)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.cloneAreaPath != "" || m.cloneTags != "" {
+		t.Error("cancelling the clone should clear carried-over area and tags")
+	}
+}
+
+func TestCreateCloneSendsTagsAndAreaInOneRequest(t *testing.T) {
+	m := setupBoardModel()
+	m.view = ViewCreate
+	m.workItemTypes = []string{"Bug"}
+	m.createInputs[0].SetValue("Copy of First Item")
+	m.cloneAreaPath = "Project\\Team"
+	m.cloneTags = "recurring; ops"
+	var requests []string
+	var ops []azdo.CreateWorkItemOp
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method)
+		_ = json.NewDecoder(r.Body).Decode(&ops)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(azdo.WorkItem{ID: 50})
+	})
+
+	msg, ok := m.createWorkItem()().(createResultMsg)
+	if !ok || msg.err != nil {
+		t.Fatalf("Expected the clone to be created, got %+v", msg)
+	}
+	if len(requests) != 1 || requests[0] != "POST" {
+		t.Fatalf("Expected a single create request, got %v", requests)
+	}
+	fields := map[string]any{}
+	for _, op := range ops {
+		fields[op.Path] = op.Value
+	}
+	if fields["/fields/System.Tags"] != "recurring; ops" || fields["/fields/System.AreaPath"] != "Project\\Team" {
+		t.Errorf("Expected the tags and area in the create request, got %v", fields)
+	}
+}
+
+func TestDetailLinkExistingValidation(t *testing.T) {
+	m := setupDetailModel()
+	m.relatedExpanded = true
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = newModel.(Model)
+	if !m.linkingExisting || !m.linkExistingAsChild {
+		t.Fatal("a should start linking an existing item as a child")
+	}
+
+	// Non-digit input is ignored
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = newModel.(Model)
+	if m.linkExistingID != "" {
+		t.Errorf("linkExistingID = %q, want non-digits ignored", m.linkExistingID)
+	}
+
+	// Empty ID is rejected
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd != nil || m.err == nil {
+		t.Error("enter with an empty ID should set an error and not dispatch")
+	}
+
+	// Linking to itself is rejected
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}})
+	m = newModel.(Model)
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd != nil || m.err == nil || !strings.Contains(m.err.Error(), "itself") {
+		t.Errorf("linking to itself should be rejected, err = %v", m.err)
+	}
+
+	// A valid ID dispatches the link as a parent after toggling
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = newModel.(Model)
+	for _, r := range "42" {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = newModel.(Model)
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = newModel.(Model)
+	if m.linkExistingAsChild {
+		t.Error("tab should switch to linking as parent")
+	}
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd == nil || !m.loading || m.linkingExisting {
+		t.Error("enter with a valid ID should dispatch the link")
+	}
+}
+
+func TestLinkExistingItemNotFound(t *testing.T) {
+	m := setupDetailModel()
+	// An invalid organization makes the lookup fail before reaching the network
+	m.client = azdo.NewClient("%zz", "testproject", "", "", "testpat")
+
+	msg := m.linkExistingItem(1, 42, true)()
+	result, ok := msg.(linkExistingMsg)
+	if !ok {
+		t.Fatalf("linkExistingItem() = %#v, want linkExistingMsg", msg)
+	}
+	if result.err == nil || !strings.Contains(result.err.Error(), "#42 not found") {
+		t.Errorf("err = %v, want not found error for #42", result.err)
+	}
+
+	newModel, _ := m.Update(result)
+	m = newModel.(Model)
+	if m.err == nil {
+		t.Error("a failed link should surface the error")
+	}
+}
+
+func TestDetailAreaPicker(t *testing.T) {
+	m := setupDetailModel()
+	m.selectedItem.Fields.AreaPath = "Project\\Team"
+	m.areas = []string{"Project", "Project\\Team", "Project\\Other"}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	m = newModel.(Model)
+	if !m.areaExpanded {
+		t.Fatal("ctrl+o should open the area picker")
+	}
+	if cmd != nil {
+		t.Error("areas already loaded, should not refetch")
+	}
+	if m.areaCursor != 1 {
+		t.Errorf("areaCursor = %d, want current area selected", m.areaCursor)
+	}
+	if !strings.Contains(m.View(), "Project\\Other") {
+		t.Error("area picker should list available areas")
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = newModel.(Model)
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd == nil || !m.loading {
+		t.Error("enter should dispatch the area update")
+	}
+
+	updated := *m.selectedItem
+	updated.Fields.AreaPath = "Project\\Other"
+	newModel, _ = m.Update(updateAreaMsg{item: &updated})
+	m = newModel.(Model)
+	if m.areaExpanded {
+		t.Error("area picker should close after a successful update")
+	}
+	if m.selectedItem.Fields.AreaPath != "Project\\Other" {
+		t.Errorf("AreaPath = %q, want updated area", m.selectedItem.Fields.AreaPath)
+	}
+}
+
+func TestDetailAreaPickerLoadsAtCurrentAreaAndScrolls(t *testing.T) {
+	m := setupDetailModel()
+	m.selectedItem.Fields.AreaPath = "Project\\Area 15"
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	m = newModel.(Model)
+	if cmd == nil {
+		t.Fatal("ctrl+o should fetch the areas on first use")
+	}
+	var areas []string
+	for i := range 30 {
+		areas = append(areas, fmt.Sprintf("Project\\Area %02d", i))
+	}
+	newModel, _ = m.Update(areasMsg{areas: areas})
+	m = newModel.(Model)
+	if m.areaCursor != 15 {
+		t.Errorf("areaCursor = %d, want the current area selected once loaded", m.areaCursor)
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "Showing 11-20 of 30") || !strings.Contains(view, "Area 15") || strings.Contains(view, "Area 29") {
+		t.Errorf("area picker should show a window around the cursor, got:\n%s", view)
+	}
+}
+
+func TestConfigConnectProxyURL(t *testing.T) {
+	m := NewModel()
+	for i, v := range []string{"testorg", "testproject", "testteam", "Area", "testpat", "test@example.com"} {
+		m.configInputs[i].SetValue(v)
+	}
+
+	m.appConfig.ProxyURL = "not a proxy"
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd != nil || m.client != nil {
+		t.Error("an invalid proxy_url should not connect")
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "proxy_url") {
+		t.Errorf("err = %v, want proxy_url error", m.err)
+	}
+
+	m.err = nil
+	m.appConfig.ProxyURL = "http://proxy.example.com:8080"
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd == nil || m.client == nil {
+		t.Error("a valid proxy_url should build a client and connect")
+	}
+}
+
+func TestConfigConnectServerURL(t *testing.T) {
+	m := NewModel()
+	for i, v := range []string{"DefaultCollection", "testproject", "testteam", "Area", "testpat", "test@example.com"} {
+		m.configInputs[i].SetValue(v)
+	}
+	m.appConfig.ServerURL = "https://tfs.example.com/tfs"
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.client == nil || m.client.BaseURL != "https://tfs.example.com/tfs" {
+		t.Fatal("server_url should be passed to the client")
+	}
+	if got := m.client.WorkItemWebURL(1); got != "https://tfs.example.com/tfs/DefaultCollection/testproject/_workitems/edit/1" {
+		t.Errorf("WorkItemWebURL() = %v, want on-premises URL", got)
+	}
+}
+
+func TestDetailTagEditor(t *testing.T) {
+	m := setupDetailModel()
+	m.detailInputs[3].SetValue("backend; urgent")
+	m.availableTags = []string{"backend", "needs-review", "urgent"}
+	m.detailFocus = 3
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if !m.tagEditing {
+		t.Fatal("enter on the Tags field should open the tag editor")
+	}
+	if len(m.tagList) != 2 {
+		t.Fatalf("tagList = %v, want the current tags", m.tagList)
+	}
+
+	// Remove the selected (last) tag
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = newModel.(Model)
+
+	// Add a tag using autocomplete
+	for _, r := range "nee" {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = newModel.(Model)
+	}
+	if !strings.Contains(m.viewDetail(), "needs-review") {
+		t.Error("tag editor should suggest matching tags")
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = newModel.(Model)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if strings.Join(m.tagList, ",") != "backend,needs-review" {
+		t.Fatalf("tagList = %v, want [backend needs-review]", m.tagList)
+	}
+
+	// Enter with nothing typed applies the tags
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.tagEditing {
+		t.Error("enter with an empty input should close the tag editor")
+	}
+	if got := m.detailInputs[3].Value(); got != "backend; needs-review" {
+		t.Errorf("Tags = %q, want joined tags", got)
+	}
+
+	// Typed spaces are kept, and esc cancels without applying
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	for _, r := range "on hold" {
+		key := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		if r == ' ' {
+			key = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}}
+		}
+		newModel, _ = m.Update(key)
+		m = newModel.(Model)
+	}
+	if m.tagInput != "on hold" {
+		t.Errorf("tagInput = %q, want the typed space kept", m.tagInput)
+	}
+
+	// Non-ASCII input is accepted, and backspace removes whole runes
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("—é")})
+	m = newModel.(Model)
+	if m.tagInput != "on hold—é" {
+		t.Errorf("tagInput = %q, want the typed runes", m.tagInput)
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = newModel.(Model)
+	if m.tagInput != "on hold—" {
+		t.Errorf("tagInput = %q, want the last rune removed", m.tagInput)
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.tagEditing || m.tagInput != "" {
+		t.Error("esc should close the tag editor")
+	}
+	if got := m.detailInputs[3].Value(); got != "backend; needs-review" {
+		t.Errorf("Tags = %q, want esc to leave them unchanged", got)
+	}
+}
+
+func TestDetailIterationDropdownDates(t *testing.T) {
+	m := setupDetailModel()
+	m.iterationExpanded = true
+	m.iterations = []azdo.Iteration{
+		{ID: "1", Name: "Sprint 1", Path: "Project\\Sprint 1", Attributes: &azdo.IterationAttributes{
+			StartDate: "2024-01-01T00:00:00Z", FinishDate: "2024-01-14T00:00:00Z", TimeFrame: "current",
+		}},
+		{ID: "2", Name: "Backlog", Path: "Project\\Backlog"},
+	}
+
+	var sprintLine, backlogLine string
+	for _, line := range strings.Split(m.renderDetail(), "\n") {
+		if strings.Contains(line, "Sprint 1") {
+			sprintLine = line
+		}
+		if strings.Contains(line, "Backlog") {
+			backlogLine = line
+		}
+	}
+	if !strings.Contains(sprintLine, "(Jan 01 – Jan 14)") {
+		t.Errorf("dropdown line should include formatted dates, got %q", sprintLine)
+	}
+	if backlogLine == "" || strings.Contains(backlogLine, "(") {
+		t.Errorf("iteration without attributes should render without dates, got %q", backlogLine)
+	}
+}
+
+func TestDetailIterationDropdownGroupHeaders(t *testing.T) {
+	m := setupDetailModel()
+	m.selectedItem.Fields.IterationPath = "Project\\Sprint 2"
+	m.iterations = []azdo.Iteration{
+		{ID: "2", Name: "Sprint 2", Path: "Project\\Sprint 2", Attributes: &azdo.IterationAttributes{TimeFrame: "current"}},
+		{ID: "1", Name: "Sprint 1", Path: "Project\\Sprint 1", Attributes: &azdo.IterationAttributes{TimeFrame: "past"}},
+		{ID: "3", Name: "Sprint 3", Path: "Project\\Sprint 3", Attributes: &azdo.IterationAttributes{TimeFrame: "future"}},
+	}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	m = newModel.(Model)
+	if m.iterationCursor != 1 {
+		t.Errorf("iterationCursor = %d, want the item's iteration in the grouped list", m.iterationCursor)
+	}
+
+	view := m.renderDetail()
+	past := strings.Index(view, "Past")
+	current := strings.Index(view, "Current")
+	future := strings.Index(view, "Future")
+	if past < 0 || current < past || future < current {
+		t.Errorf("dropdown should show Past, Current, Future headers in order, got:\n%s", view)
+	}
+
+	// Cursor navigation moves across sections
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = newModel.(Model)
+	if got := m.getIterationDisplayOrder()[m.iterationCursor].Name; got != "Sprint 3" {
+		t.Errorf("down should move into the next section, got %q", got)
+	}
+}
+
+func TestDetailMoveToCurrentSprint(t *testing.T) {
+	var ops []azdo.CreateWorkItemOp
+	m := setupDetailModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&ops)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"fields":{"System.IterationPath":"Project\\Sprint 2"}}`))
+	})
+	m.iterations = []azdo.Iteration{
+		{ID: "1", Name: "Sprint 1", Path: "Project\\Sprint 1", Attributes: &azdo.IterationAttributes{TimeFrame: "past"}},
+		{ID: "2", Name: "Sprint 2", Path: "Project\\Sprint 2", Attributes: &azdo.IterationAttributes{TimeFrame: "current"}},
+	}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}, Alt: true})
+	m = newModel.(Model)
+	if cmd == nil || !m.loading {
+		t.Fatal("alt+t should dispatch an iteration update")
+	}
+	if m.iterationExpanded {
+		t.Error("alt+t should not open the iteration dropdown")
+	}
+
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	if len(ops) != 1 || ops[0].Path != "/fields/System.IterationPath" || ops[0].Value != "Project\\Sprint 2" {
+		t.Errorf("update ops = %+v, want the current iteration path", ops)
+	}
+	if m.selectedItem.Fields.IterationPath != "Project\\Sprint 2" {
+		t.Errorf("IterationPath = %q, want the current sprint", m.selectedItem.Fields.IterationPath)
+	}
+}
+
+func TestDetailMoveToCurrentSprintNone(t *testing.T) {
+	m := setupDetailModel()
+	m.iterations = []azdo.Iteration{
+		{ID: "1", Name: "Sprint 1", Path: "Project\\Sprint 1", Attributes: &azdo.IterationAttributes{TimeFrame: "past"}},
+	}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}, Alt: true})
+	m = newModel.(Model)
+	if cmd != nil {
+		t.Error("no update should be sent without a current iteration")
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "no current iteration") {
+		t.Errorf("err = %v, want no current iteration error", m.err)
+	}
+}
+
+func TestDetailMoveToCurrentSprintFetchesIterations(t *testing.T) {
+	m := setupDetailModel()
+	m.iterations = nil
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}, Alt: true})
+	m = newModel.(Model)
+	if cmd == nil || !m.pendingSprintMove {
+		t.Fatal("alt+t should fetch iterations when none are loaded")
+	}
+
+	newModel, cmd = m.Update(iterationsMsg{iterations: []azdo.Iteration{
+		{ID: "2", Name: "Sprint 2", Path: "Project\\Sprint 2", Attributes: &azdo.IterationAttributes{TimeFrame: "current"}},
+	}})
+	m = newModel.(Model)
+	if m.pendingSprintMove || cmd == nil {
+		t.Error("loading iterations should continue the move to the current sprint")
+	}
+}
+
+func TestDetailMoveToCurrentSprintNoIterations(t *testing.T) {
+	m := setupDetailModel()
+	m.iterations = nil
+	requests := 0
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"count": 0, "value": []}`))
+	})
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}, Alt: true})
+	m = newModel.(Model)
+	if cmd == nil || !m.pendingSprintMove {
+		t.Fatal("alt+t should fetch iterations when none are loaded")
+	}
+
+	// The project has no iterations: stop instead of fetching again
+	newModel, cmd = m.Update(cmd())
+	m = newModel.(Model)
+	if cmd != nil || m.loading || m.pendingSprintMove {
+		t.Errorf("an empty iteration list should end the move, got cmd %v loading %v", cmd != nil, m.loading)
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "no iterations") {
+		t.Errorf("err = %v, want a no iterations error", m.err)
+	}
+	if requests != 1 {
+		t.Errorf("iterations fetched %d times, want once", requests)
+	}
+}
+
+func TestDetailCollapseAllSections(t *testing.T) {
+	m := setupDetailModel()
+	m.commentsExpanded = true
+	m.relatedExpanded = true
+	m.planningExpanded = true
+	m.iterationExpanded = true
+	m.hyperlinksExpanded = true
+	m.detailFocus = 2
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	m = newModel.(Model)
+	if m.commentsExpanded || m.relatedExpanded || m.planningExpanded || m.iterationExpanded || m.hyperlinksExpanded {
+		t.Fatal("ctrl+k should collapse every expanded section")
+	}
+	if !m.detailInputs[2].Focused() {
+		t.Error("ctrl+k should refocus the current field")
+	}
+
+	// Field navigation works again
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = newModel.(Model)
+	if m.detailFocus != 3 {
+		t.Errorf("detailFocus = %d, want tab to move to the next field", m.detailFocus)
+	}
+}
+
+func TestDetailJumpToField(t *testing.T) {
+	m := setupDetailModel()
+
+	for i, key := range []rune{'4', '2', '5', '1'} {
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}, Alt: true})
+		m = newModel.(Model)
+		want := int(key - '1')
+		if m.detailFocus != want {
+			t.Errorf("step %d: alt+%c set detailFocus = %d, want %d", i, key, m.detailFocus, want)
+		}
+		if !m.detailInputs[want].Focused() {
+			t.Errorf("step %d: field %d should be focused", i, want)
+		}
+	}
+
+	// Plain digits still type into the focused input
+	m.detailFocus = 0
+	m.detailInputs[0].SetValue("")
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'3'}})
+	m = newModel.(Model)
+	if m.detailFocus != 0 || m.detailInputs[0].Value() != "3" {
+		t.Errorf("plain digit should type into the title, got focus %d value %q", m.detailFocus, m.detailInputs[0].Value())
+	}
+
+	// Ignored while a section captures navigation
+	m.commentsExpanded = true
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'4'}, Alt: true})
+	m = newModel.(Model)
+	if m.detailFocus != 0 {
+		t.Errorf("detailFocus = %d, want unchanged while comments are expanded", m.detailFocus)
+	}
+}
+
+func TestDetailWorkItemDeletedMidSession(t *testing.T) {
+	notFound := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"TF401232: Work item 1 does not exist"}`))
+	}
+
+	tests := []struct {
+		name  string
+		fetch func(m Model) tea.Cmd
+	}{
+		{name: "comments", fetch: func(m Model) tea.Cmd { return m.fetchComments(1) }},
+		{name: "related", fetch: func(m Model) tea.Cmd { return m.fetchRelatedItems(1) }},
+		{name: "hyperlinks", fetch: func(m Model) tea.Cmd { return m.fetchHyperlinks(1) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := setupDetailModel()
+			m.client = mockClient(t, notFound)
+
+			newModel, _ := m.Update(tt.fetch(m)())
+			m = newModel.(Model)
+			if m.view != ViewBoard {
+				t.Errorf("view = %v, want to return to the board", m.view)
+			}
+			if m.err == nil || !strings.Contains(m.err.Error(), "no longer exists") {
+				t.Errorf("err = %v, want friendly no longer exists message", m.err)
+			}
+			if strings.Contains(m.err.Error(), "TF401232") {
+				t.Errorf("err = %v, should not show the raw API error", m.err)
+			}
+			for _, wi := range m.workItems {
+				if wi.ID == 1 {
+					t.Error("deleted item should be dropped from the board")
+				}
+			}
+			if !strings.Contains(m.viewBoard(), "no longer exists") {
+				t.Error("board should show the message")
+			}
+		})
+	}
+}
+
+func TestDetailNotFoundForOtherItemIgnored(t *testing.T) {
+	m := setupDetailModel()
+
+	newModel, _ := m.Update(commentsMsg{workItemID: 99, err: &azdo.APIError{StatusCode: http.StatusNotFound}})
+	m = newModel.(Model)
+	if m.view != ViewDetail || m.err != nil {
+		t.Error("a 404 for an item that isn't open should be ignored")
+	}
+}
+
+func TestDetailCommentsLazyLoadNextPage(t *testing.T) {
+	m := setupDetailModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("continuationToken") != "next-page" {
+			t.Errorf("continuationToken = %q, want next-page", r.URL.Query().Get("continuationToken"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(azdo.CommentsResponse{Comments: []azdo.Comment{{ID: 99, Text: "Older"}}})
+	})
+
+	var firstPage []azdo.Comment
+	for i := 1; i <= 7; i++ {
+		firstPage = append(firstPage, azdo.Comment{ID: i, Text: "Comment " + strconv.Itoa(i)})
+	}
+	newModel, _ := m.Update(commentsMsg{workItemID: 1, comments: firstPage, nextToken: "next-page"})
+	m = newModel.(Model)
+	if !strings.Contains(m.View(), "Comments (7+)") {
+		t.Error("comment count should show that more pages are available")
+	}
+
+	m.commentsExpanded = true
+	// The first scroll still leaves a full screen of loaded comments
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	m = newModel.(Model)
+	if cmd != nil {
+		t.Fatal("should not load more comments before reaching the end of the loaded set")
+	}
+
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	m = newModel.(Model)
+	if cmd == nil || !m.loadingMoreComments {
+		t.Fatal("scrolling to the end of the loaded comments should fetch the next page")
+	}
+
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	if len(m.comments) != 8 || m.comments[7].Text != "Older" {
+		t.Errorf("next page should be appended, got %d comments", len(m.comments))
+	}
+	if m.commentsToken != "" || m.loadingMoreComments {
+		t.Error("paging should stop after the last page")
+	}
+}
+
+func TestDetailCommentsCopyAndSummaryAcrossPages(t *testing.T) {
+	var copied string
+	orig := writeClipboard
+	writeClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+	t.Cleanup(func() { writeClipboard = orig })
+
+	comment := func(id int, text string) azdo.Comment {
+		c := azdo.Comment{ID: id, Text: text, CreatedDate: "2026-01-0" + strconv.Itoa(id) + "T10:00:00Z"}
+		c.CreatedBy.DisplayName = "Author " + strconv.Itoa(id)
+		return c
+	}
+	m := setupDetailModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		resp := azdo.CommentsResponse{}
+		switch token := r.URL.Query().Get("continuationToken"); {
+		case token == "" && r.URL.Query().Get("order") == "desc":
+			resp.Comments = []azdo.Comment{comment(3, "Third")}
+		case token == "page-2":
+			resp.Comments = []azdo.Comment{comment(2, "Second")}
+			resp.ContinuationToken = "page-3"
+		case token == "page-3":
+			resp.Comments = []azdo.Comment{comment(3, "Third")}
+		default:
+			t.Errorf("unexpected comments request %s", r.URL.RawQuery)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+
+	// Oldest first, only the first of three pages is loaded
+	newModel, cmd := m.Update(commentsMsg{workItemID: 1, comments: []azdo.Comment{comment(1, "First")}, nextToken: "page-2"})
+	m = newModel.(Model)
+	if cmd == nil {
+		t.Fatal("a partial thread should fetch the newest comment")
+	}
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	if view := m.View(); !strings.Contains(view, "Latest: Author 3") {
+		t.Errorf("collapsed summary should name the newest comment's author, got:\n%s", view)
+	}
+
+	// alt+c waits for a page that's already loading, which would otherwise be appended twice
+	m.loadingMoreComments = true
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}, Alt: true})
+	m = newModel.(Model)
+	if cmd != nil || copied != "" {
+		t.Fatal("alt+c should not fetch while another comment page is loading")
+	}
+	m.loadingMoreComments = false
+
+	// alt+c loads the remaining pages before copying
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}, Alt: true})
+	m = newModel.(Model)
+	if cmd == nil || copied != "" || !m.loadingMoreComments {
+		t.Fatal("alt+c should fetch the remaining comment pages before copying")
+	}
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	if len(m.comments) != 3 || m.commentsToken != "" || m.loadingMoreComments {
+		t.Errorf("remaining pages should be loaded, got %d comments, token %q", len(m.comments), m.commentsToken)
+	}
+	if !strings.Contains(copied, "First") || !strings.Contains(copied, "Second") || !strings.Contains(copied, "Third") {
+		t.Errorf("whole thread should be copied, got %q", copied)
+	}
+	if m.message != "Copied 3 comments" {
+		t.Errorf("message = %q", m.message)
+	}
+}
+
+func TestDetailPickReasonAndSave(t *testing.T) {
+	m := setupDetailModel()
+	m.selectedItem.Fields.Reason = "New"
+	var ops []azdo.CreateWorkItemOp
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "PATCH" {
+			_ = json.NewDecoder(r.Body).Decode(&ops)
+			_ = json.NewEncoder(w).Encode(azdo.WorkItem{ID: 1, Fields: azdo.WorkItemFields{State: "Resolved", Reason: "Fixed"}})
+			return
+		}
+		_, _ = w.Write([]byte(`{"allowedValues": ["Fixed", "Obsolete"]}`))
+	})
+	if !strings.Contains(m.View(), "Reason: New") {
+		t.Error("detail view should show the current reason")
+	}
+
+	alt := func(r rune) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}, Alt: true} }
+	newModel, cmd := m.Update(alt('r'))
+	m = newModel.(Model)
+	if cmd == nil {
+		t.Fatal("alt+r should fetch the reasons for the item's type")
+	}
+	msg := cmd()
+	// Reasons arriving after the user moved to another item are dropped
+	other := *m.selectedItem
+	other.ID = 2
+	stale := m
+	stale.selectedItem = &other
+	newModel, _ = stale.Update(msg)
+	if got := newModel.(Model); got.stateReasons != nil || got.pendingReason != "" {
+		t.Error("reasons fetched for another item should be dropped")
+	}
+	newModel, _ = m.Update(msg)
+	m = newModel.(Model)
+	if m.pendingReason != "Fixed" {
+		t.Fatalf("pendingReason = %q, want the first reason selected", m.pendingReason)
+	}
+
+	newModel, _ = m.Update(alt('r'))
+	m = newModel.(Model)
+	if m.pendingReason != "Obsolete" {
+		t.Errorf("alt+r should cycle to the next reason, got %q", m.pendingReason)
+	}
+	newModel, _ = m.Update(alt('r'))
+	m = newModel.(Model)
+	if m.pendingReason != "" {
+		t.Errorf("alt+r should cycle back to the default reason, got %q", m.pendingReason)
+	}
+	newModel, _ = m.Update(alt('r'))
+	m = newModel.(Model)
+
+	// A reason is only sent with a state change
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = newModel.(Model)
+	cmd()
+	for _, op := range ops {
+		if op.Path == "/fields/System.Reason" {
+			t.Errorf("ops = %+v, want no reason without a state change", ops)
+		}
+	}
+
+	// Typing the State back to the saved value drops the picked reason
+	m.detailFocus = 1
+	m.updateDetailFocus()
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = newModel.(Model)
+	if m.pendingReason != "Fixed" {
+		t.Fatalf("pendingReason = %q, want it kept while the state differs", m.pendingReason)
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = newModel.(Model)
+	if m.pendingReason != "" {
+		t.Errorf("pendingReason = %q, want it cleared once the state is back to %q", m.pendingReason, m.selectedItem.Fields.State)
+	}
+	newModel, _ = m.Update(alt('r'))
+	m = newModel.(Model)
+
+	m.detailInputs[1].SetValue("Resolved")
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = newModel.(Model)
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+
+	found := false
+	for _, op := range ops {
+		if op.Path == "/fields/System.Reason" && op.Value == "Fixed" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("ops = %+v, want the picked reason saved", ops)
+	}
+	if m.pendingReason != "" {
+		t.Error("the picked reason should reset after saving")
+	}
+}
@@ -1,7 +1,10 @@
 package tui
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/zalando/go-keyring"
 )
@@ -84,20 +87,70 @@ func LoadCredentials() (org, project, team, areaPath, pat, username string, err
 	return org, project, team, areaPath, pat, username, nil
 }
 
+// credentialLoader loads stored credentials; tests replace it to simulate keychain failures
+var credentialLoader = LoadCredentials
+
+// keychainTimeout bounds how long startup waits on the keychain, which can hang on
+// headless systems without a secret service
+var keychainTimeout = 3 * time.Second
+
+// storedCredentials holds the credentials loaded from the keychain
+type storedCredentials struct {
+	org, project, team, areaPath, pat, username string
+}
+
+// loadStoredCredentials loads credentials with a timeout. It returns found=false with a
+// nil error when nothing is stored, and an error when the keychain itself is unavailable.
+func loadStoredCredentials() (creds storedCredentials, found bool, err error) {
+	type result struct {
+		creds storedCredentials
+		err   error
+	}
+	done := make(chan result, 1)
+	// Read the loader before starting, since a timed-out goroutine can outlive this call
+	load := credentialLoader
+	go func() {
+		var r result
+		r.creds.org, r.creds.project, r.creds.team, r.creds.areaPath, r.creds.pat, r.creds.username, r.err = load()
+		done <- r
+	}()
+
+	select {
+	case r := <-done:
+		if errors.Is(r.err, keyring.ErrNotFound) {
+			return storedCredentials{}, false, nil
+		}
+		if r.err != nil {
+			return storedCredentials{}, false, r.err
+		}
+		return r.creds, true, nil
+	case <-time.After(keychainTimeout):
+		return storedCredentials{}, false, fmt.Errorf("timed out after %s", keychainTimeout)
+	}
+}
+
 // ClearCredentials removes the stored credentials from the keychain
 func ClearCredentials() error {
 	if isRunningInDocker() {
 		return nil
 	}
-	_ = keyring.Delete(keychainService, keychainOrgKey)
-	_ = keyring.Delete(keychainService, keychainProjKey)
-	_ = keyring.Delete(keychainService, keychainTeamKey)
-	_ = keyring.Delete(keychainService, keychainAreaPathKey)
-	_ = keyring.Delete(keychainService, keychainPATKey)
-	_ = keyring.Delete(keychainService, keychainUserKey)
-	return nil
+	// Delete every key even if one fails, and report the first failure
+	var firstErr error
+	for _, key := range []string{keychainOrgKey, keychainProjKey, keychainTeamKey, keychainAreaPathKey, keychainPATKey, keychainUserKey} {
+		if err := keyring.Delete(keychainService, key); err != nil && !errors.Is(err, keyring.ErrNotFound) && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
 }
 
+// credentialClearer and fileCredentialClearer delete the stored credentials; tests replace
+// them to simulate failures
+var (
+	credentialClearer     = ClearCredentials
+	fileCredentialClearer = clearFileCredentials
+)
+
 // HasStoredCredentials checks if credentials are stored in the keychain
 func HasStoredCredentials() bool {
 	if isRunningInDocker() {
@@ -4,8 +4,12 @@ package tui
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"regexp"
 	"runtime"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/laupski/bored/azdo"
@@ -28,6 +32,9 @@ const (
 	ViewCreate                 // Create new work item screen
 	ViewDetail                 // Work item detail/edit screen
 	ViewConfigFile             // Application settings screen
+	ViewRecycleBin             // Deleted work items that can be restored
+	ViewOutline                // Loaded work items arranged by their parent/child links
+	ViewAssignees              // Loaded work items grouped by assignee
 )
 
 // Model is the main Bubble Tea model containing all application state.
@@ -41,6 +48,8 @@ type Model struct {
 	createInputs    []textinput.Model
 	createFocus     int
 	createType      int
+	cloneAreaPath   string // area path carried over when cloning a work item
+	cloneTags       string // tags carried over when cloning a work item
 	workItemTypes   []string
 	err             error
 	message         string
@@ -49,8 +58,33 @@ type Model struct {
 	loading         bool
 	keychainLoaded  bool
 	keychainMessage string
+	keychainFailed  bool // true when keychainMessage reports a failure
+	// File credential store state
+	fileCreds           *credentialsFile // saved credentials whose PAT is still encrypted
+	promptingPassphrase bool             // true when asking for the credentials file passphrase
+	passphraseInput     textinput.Model
+	// Session state
 	username        string
 	showAll         bool
+	hideCompleted   bool
+	myQueue         bool        // true when the board always shows the current user's items
+	soundMuted      bool        // true when notification sounds are muted for this session
+	showUniqueNames bool        // true when assignees are shown by unique name (email) instead of display name
+	lastFetched     time.Time   // time of the last successful work item fetch
+	lastUndo        *undoAction // last destructive action that can be undone
+	// Logout state
+	confirmingClearCredentials bool // true when asking before clearing stored credentials
+	// First-run setup wizard state; the wizard step is configFocus
+	wizardActive bool
+	// Guarded esc state (confirm_esc)
+	confirmingEsc bool // true after one esc in the create or detail view, waiting for the second
+	// Error log overlay
+	errorLog     []errorLogEntry // most recent errors, oldest first
+	showErrorLog bool
+	// Notification history overlay
+	notifications      []notificationEntry // detected changes, oldest first
+	showNotifications  bool
+	notificationCursor int // selected entry, counted from the newest
 	// App config (from config file)
 	appConfig        AppConfig
 	appConfigMessage string
@@ -60,9 +94,36 @@ type Model struct {
 	selectedItem     *azdo.WorkItem
 	detailInputs     []textinput.Model
 	detailFocus      int
+	detailScroll     int // vertical scroll offset of the whole detail view
 	comments         []azdo.Comment
 	commentsExpanded bool
 	commentScroll    int
+	// Comment order, toggled with alt+o
+	commentsNewestFirst bool
+	// Show comment HTML as sent instead of cleaned up, toggled with alt+h
+	rawMode bool
+	// State change reason
+	stateReasons  []string // reasons allowed for the item's type, nil until fetched
+	pendingReason string   // reason saved with the next state change, empty for the default
+	// Allowed values of the State field
+	stateValues     []string // states the item's type allows, nil until fetched
+	stateValuesType string   // work item type stateValues were fetched for
+	// Workflow used to cycle the State field
+	workflowStates   []azdo.WorkItemState // states and transitions of workflowType
+	workflowType     string               // work item type workflowStates were fetched for
+	pendingStateStep int                  // state step (+1/-1) waiting for the workflow to load
+	// Quick state menu in the detail view
+	stateMenuExpanded bool // true when the state menu is shown
+	stateMenuCursor   int  // selected state index in the menu
+	// Read-only fields of the open item's type, which the detail editor won't focus
+	readOnlyFields     map[string]bool // reference names the type reports as read-only
+	readOnlyFieldsType string          // work item type readOnlyFields were fetched for
+	// Parent titles of board items whose parent isn't on the board, by parent ID
+	parentTitles map[int]string
+	// Comment paging
+	commentsToken       string // continuation token for the next page of comments, empty when all are loaded
+	loadingMoreComments bool
+	latestComment       *azdo.Comment // newest comment, fetched while oldest-first pages remain
 	// Related work items
 	parentItem      *azdo.WorkItem
 	childItems      []azdo.WorkItem
@@ -83,6 +144,13 @@ type Model struct {
 	createRelatedType     int    // index into workItemTypes
 	createRelatedAssignee string // assignee for the new related item
 	createRelatedFocus    int    // 0 = title, 1 = assignee
+	createRelatedTarget   int    // work item the new item is created relative to
+	// Link existing item state
+	linkingExisting     bool   // true when entering an existing work item ID to link
+	linkExistingAsChild bool   // true = link as child, false = link as parent
+	linkExistingID      string // work item ID being entered
+	// Write confirmation state (confirm_writes)
+	pendingWrite *pendingWrite // write waiting for a y/n answer
 	// Delete confirmation state
 	confirmingDelete      bool // true when waiting for delete confirmation
 	confirmDeleteTargetID int  // ID of the item to unlink
@@ -92,6 +160,64 @@ type Model struct {
 	deleteWorkItemID    int    // ID of work item to delete
 	deleteWorkItemTitle string // Title of work item to delete (for confirmation)
 	deleteConfirmInput  string // User's typed confirmation
+	// Jump to work item state (on board screen)
+	jumpingToID bool   // true when entering a work item ID to open
+	jumpIDInput string // work item ID being entered
+	// Iteration filter state (on board screen)
+	iterationFilter        string // iteration path the board is limited to (empty shows all)
+	pickingIterationFilter bool   // true when the iteration filter picker is open
+	iterationFilterCursor  int    // selected option in the picker (0 = all iterations)
+	currentIteration       bool   // true when the board follows the team's current iteration
+	// Board order
+	sortMode int // index into boardSorts
+	// Multi-select state (on board screen)
+	selectedIDs  map[int]bool // IDs of work items selected for bulk actions
+	bulkTagging  bool         // true when entering a tag to add to the selected items
+	bulkTagInput string       // tag being entered
+	// Save query prompt (on board screen)
+	savingQuery    bool   // true when naming the board query to save
+	saveQueryInput string // name being entered
+	// Last assignee entered in an assign action, reused by A on the board
+	lastAssignee string
+	// Work item to open once connected (from the command line)
+	startupItemID int
+	// Search state (on board screen)
+	searchText  string // text the board is limited to matching (empty shows all)
+	searching   bool   // true when entering the search text
+	searchInput string // search text being entered
+	// Fuzzy filter of the loaded board items
+	fuzzyFiltering bool            // true while typing the filter
+	fuzzyQuery     string          // text the board is narrowed to (empty shows all)
+	fuzzyAll       []azdo.WorkItem // every loaded item while the filter is on, nil otherwise
+	// Offline cache state
+	cache   offlineCache // last fetched board and opened items, saved to disk
+	offline bool         // true when browsing the cache because Azure DevOps can't be reached
+	// Bulk action progress
+	bulkPending int   // number of per-item updates issued by the running bulk action
+	bulkDone    int   // number of per-item results received so far
+	bulkFailed  int   // number of per-item updates that failed
+	bulkLastErr error // most recent per-item failure
+	// Bulk progress shown in the board footer
+	bulkProgress bulkProgressMsg
+	bulkLabel    string // what the running bulk action is doing, e.g. "Tagging"
+	// CSV import prompt (on board screen)
+	importing       bool     // true when entering the CSV file to import
+	importPathInput string   // path being entered
+	importErrors    []string // failed rows of the running import
+	// Tag editor state
+	tagEditing    bool     // true when the tag chip editor is open
+	tagList       []string // tags being edited
+	tagCursor     int      // selected tag chip
+	tagInput      string   // new tag being typed
+	availableTags []string // project tags used for autocomplete
+	// Recycle bin state
+	recycleBin       []azdo.DeletedWorkItem
+	recycleBinCursor int
+	// Outline (tree) view state
+	outlineCursor    int
+	outlineCollapsed map[int]bool // IDs of outline nodes whose children are hidden
+	// Group-by-assignee view state
+	assigneeCursor int // selected item, counted across all groups
 	// Server-side pagination state
 	apiPage     int  // Current page of API results (0-indexed)
 	hasMoreData bool // True if there might be more data to fetch
@@ -99,6 +225,16 @@ type Model struct {
 	iterations        []azdo.Iteration // available iterations
 	iterationExpanded bool             // true when iteration dropdown is shown
 	iterationCursor   int              // selected iteration index in dropdown
+	pendingSprintMove bool             // true when waiting for iterations to move the item to the current sprint
+	// Area selection state
+	areas        []string // available area paths
+	areaExpanded bool     // true when area dropdown is shown
+	areaCursor   int      // selected area index in dropdown
+	// Activity selection state (Tasks)
+	activities       []string // allowed Activity values for activitiesType
+	activitiesType   string   // work item type the activities were fetched for
+	activityExpanded bool     // true when the activity dropdown is shown
+	activityCursor   int      // selected activity index in dropdown
 	// Planning state
 	planningExpanded bool                 // true when planning section is expanded
 	planningFocus    int                  // current field focus index
@@ -109,15 +245,25 @@ type Model struct {
 	knownRevisions       map[int]int // map of work item ID to last known revision
 	lastNotifyCheck      time.Time   // last time we checked for changes
 	notifyMessage        string      // message to display when changes detected
+	notifyFailures       int         // consecutive failed change checks
+	notifyTicking        bool        // true once the notification ticker runs; it reschedules itself
+	notifyUserID         string      // identity ID of the user, fetched to find their @mentions
 }
 
+// notifyFailureThreshold is the number of consecutive failed change checks before warning
+const notifyFailureThreshold = 3
+
 // tickMsg is sent periodically to check for work item changes
 type tickMsg time.Time
 
 // notifyChangesMsg is sent when work item changes are detected
 type notifyChangesMsg struct {
 	changedItems []azdo.WorkItem
+	userID       string    // identity ID used to find mentions, cached for later checks
+	checkedAt    time.Time // when the check started
 	err          error
+	// Changed items that weren't notified (no new mention), so their revisions are now known
+	seen []azdo.WorkItem
 }
 
 var (
@@ -152,6 +298,16 @@ var (
 	labelStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("39")).
 			Bold(true)
+
+	staleStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("208"))
+
+	bannerStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("245"))
+
+	removedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("240")).
+			Padding(0, 1)
 )
 
 // NewModel creates and initializes a new Model with default values.
@@ -249,8 +405,8 @@ func NewModel() Model {
 	configFileInputs[0].Width = 10
 	configFileInputs[0].Prompt = ""
 
-	// Planning inputs: Story Points, Original Estimate, Remaining Work, Completed Work
-	planningInputs := make([]textinput.Model, 4)
+	// Planning inputs: Story Points, Original Estimate, Remaining Work, Completed Work, Effort
+	planningInputs := make([]textinput.Model, 5)
 
 	planningInputs[0] = textinput.New()
 	planningInputs[0].Placeholder = "0"
@@ -272,6 +428,11 @@ func NewModel() Model {
 	planningInputs[3].Width = 10
 	planningInputs[3].Prompt = ""
 
+	planningInputs[4] = textinput.New()
+	planningInputs[4].Placeholder = "0"
+	planningInputs[4].Width = 10
+	planningInputs[4].Prompt = ""
+
 	// Load app config from file
 	appConfig, _ := LoadConfigFile()
 
@@ -284,28 +445,87 @@ func NewModel() Model {
 		planningInputs:   planningInputs,
 		appConfig:        appConfig,
 		showAll:          appConfig.DefaultShowAll,
+		hideCompleted:    appConfig.HideCompleted,
+		soundMuted:       appConfig.MuteSound,
+		showUniqueNames:  appConfig.ShowUniqueNames,
 		workItemTypes:    []string{"Bug", "Task", "User Story", "Feature", "Epic"},
 	}
 
+	m.commentsNewestFirst = appConfig.CommentsNewestFirst
+
 	// Set initial value for max work items input
 	m.configFileInputs[0].SetValue(fmt.Sprintf("%d", appConfig.MaxWorkItems))
 
+	m.passphraseInput = textinput.New()
+	m.passphraseInput.Placeholder = "passphrase"
+	m.passphraseInput.Width = 40
+	m.passphraseInput.EchoMode = textinput.EchoPassword
+	m.passphraseInput.Prompt = ""
+
+	if appConfig.usesFileCredentials() {
+		m.loadFileCredentialsIntoInputs()
+		m.wizardActive = m.fileCreds == nil && !isRunningInDocker()
+		return m
+	}
+
 	// Try to load credentials from keychain
-	if org, project, team, areaPath, pat, username, err := LoadCredentials(); err == nil {
-		m.configInputs[0].SetValue(org)
-		m.configInputs[1].SetValue(project)
-		m.configInputs[2].SetValue(team)
-		m.configInputs[3].SetValue(areaPath)
-		m.configInputs[4].SetValue(pat)
-		m.configInputs[5].SetValue(username)
-		m.username = username
+	creds, found, err := loadStoredCredentials()
+	switch {
+	case err != nil:
+		// Not fatal: the user can still type credentials in
+		m.keychainMessage = "Keychain unavailable; enter credentials manually"
+		m.keychainFailed = true
+	case found:
+		m.configInputs[0].SetValue(creds.org)
+		m.configInputs[1].SetValue(creds.project)
+		m.configInputs[2].SetValue(creds.team)
+		m.configInputs[3].SetValue(creds.areaPath)
+		m.configInputs[4].SetValue(creds.pat)
+		m.configInputs[5].SetValue(creds.username)
+		m.username = creds.username
 		m.keychainLoaded = true
 		m.keychainMessage = "Credentials loaded from keychain"
 	}
+	// Guide first-time users through each field. Docker never saves credentials,
+	// so every launch there would look like a first run.
+	m.wizardActive = !found && !isRunningInDocker()
 
 	return m
 }
 
+// loadFileCredentialsIntoInputs fills the config inputs from the file credential store.
+// The PAT stays encrypted until the user enters the passphrase when connecting.
+func (m *Model) loadFileCredentialsIntoInputs() {
+	f, found, err := loadFileCredentials()
+	switch {
+	case err != nil:
+		m.keychainMessage = "Credentials file unreadable; enter credentials manually"
+		m.keychainFailed = true
+	case found:
+		m.configInputs[0].SetValue(f.Organization)
+		m.configInputs[1].SetValue(f.Project)
+		m.configInputs[2].SetValue(f.Team)
+		m.configInputs[3].SetValue(f.AreaPath)
+		m.configInputs[5].SetValue(f.Username)
+		m.username = f.Username
+		m.fileCreds = &f
+		m.keychainLoaded = true
+		m.keychainMessage = "Credentials loaded from file; the passphrase unlocks the PAT on connect"
+	}
+}
+
+// SetClient replaces the Azure DevOps client used by the model's commands.
+// It lets callers supply a pre-built client, e.g. one with a custom transport.
+func (m *Model) SetClient(client *azdo.Client) {
+	m.client = client
+}
+
+// OpenOnConnect makes the model open work item id in the detail view once it connects,
+// instead of stopping at the board. An id of 0 keeps the board.
+func (m *Model) OpenOnConnect(id int) {
+	m.startupItemID = id
+}
+
 // Init implements tea.Model and returns the initial command to run.
 func (m Model) Init() tea.Cmd {
 	return textinput.Blink
@@ -319,16 +539,25 @@ type workItemsMsg struct {
 type workItemsPageMsg struct {
 	items []azdo.WorkItem
 	page  int
+	last  bool // true when page is the final page of results; the cursor goes to its last item
 	err   error
 }
 
 type createResultMsg struct {
-	item *azdo.WorkItem
-	err  error
+	item     *azdo.WorkItem
+	assignee string // assignee entered for the item, remembered once it's created
+	err      error
 }
 
 type connectMsg struct {
-	err error
+	err         error
+	deprecation string // deprecation notice returned by Azure DevOps, if any
+	warning     string // other warning returned by Azure DevOps, if any
+}
+
+type teamAreasMsg struct {
+	values *azdo.TeamFieldValues
+	err    error
 }
 
 type workItemTypesMsg struct {
@@ -337,7 +566,25 @@ type workItemTypesMsg struct {
 }
 
 // Update implements tea.Model and handles all incoming messages.
+// Every new error it produces is recorded in the error log, and the revision
+// of every work item it opens is recorded for change notifications.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	prevErr := m.err
+	updated, cmd := m.update(msg)
+	if um, ok := updated.(Model); ok {
+		if um.err != nil && um.err != prevErr {
+			um.logError(um.err)
+		}
+		// An item fetched into the detail view has been seen, however it was loaded
+		if um.selectedItem != nil && um.selectedItem != m.selectedItem {
+			um.trackRevisions(*um.selectedItem)
+		}
+		updated = um
+	}
+	return updated, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -345,21 +592,54 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.showErrorLog {
+			return m.updateErrorLog(msg)
+		}
+		if m.showNotifications {
+			return m.updateNotifications(msg)
+		}
+		if m.pendingWrite != nil && msg.String() != "ctrl+c" {
+			return m.updatePendingWrite(msg)
+		}
+		if m.confirmingEsc && msg.String() != "esc" {
+			// Any other key cancels leaving
+			m.confirmingEsc = false
+			m.message = ""
+		}
 		switch msg.String() {
 		case "ctrl+c":
 			return m, tea.Quit
+		case "alt+e":
+			// Open the error log over the current screen
+			m.showErrorLog = true
+			return m, nil
+		case "alt+n":
+			// Open the notification history over the current screen
+			m.showNotifications = true
+			m.notificationCursor = 0
+			return m, nil
+		case "alt+E":
+			// Copy the full error, e.g. for a bug report
+			if m.err != nil {
+				return m.copyError(), nil
+			}
+		case "ctrl+b":
+			// With confirm_esc, ctrl+b leaves without the extra esc
+			if m.appConfig.ConfirmEsc && (m.view == ViewCreate || m.view == ViewDetail) {
+				return m.returnToBoard(), nil
+			}
 		case "esc":
+			// The tag editor handles esc itself, cancelling the edit
+			if m.view == ViewDetail && m.tagEditing {
+				break
+			}
 			if m.view == ViewCreate || m.view == ViewDetail {
-				m.view = ViewBoard
-				m.err = nil
-				m.message = ""
-				// Auto-collapse all expanded sections
-				m.planningExpanded = false
-				m.commentsExpanded = false
-				m.relatedExpanded = false
-				m.iterationExpanded = false
-				m.hyperlinksExpanded = false
-				return m, nil
+				if m.appConfig.ConfirmEsc && !m.confirmingEsc {
+					m.confirmingEsc = true
+					m.message = "Press esc again to return to the board (ctrl+b: board)"
+					return m, nil
+				}
+				return m.returnToBoard(), nil
 			}
 		}
 
@@ -367,20 +647,54 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 		if msg.err != nil {
 			m.err = msg.err
+			if isNetworkError(msg.err) {
+				m = m.enterOffline()
+			}
 			return m, nil
 		}
 		m.view = ViewBoard
-		// Initialize notification tracking based on config setting
-		m.notificationsEnabled = m.appConfig.EnableNotifications
-		m.knownRevisions = make(map[int]int)
-		m.lastNotifyCheck = time.Now()
+		m.offline = false
+		m.err = nil
+		m.notifyFailures = 0
+		if msg.deprecation != "" {
+			m.notifyMessage = "⚠ Azure DevOps deprecation notice: " + msg.deprecation
+		} else if msg.warning != "" {
+			m.notifyMessage = "⚠ Azure DevOps warning: " + msg.warning
+		}
+		// Initialize notification tracking based on config setting. A reconnect keeps it, so
+		// changes made during the outage are still notified.
+		if m.knownRevisions == nil {
+			m.notificationsEnabled = m.appConfig.EnableNotifications
+			m.knownRevisions = make(map[int]int)
+			m.lastNotifyCheck = time.Now()
+		}
 		// Fetch work items and work item types in parallel, and start notification ticker if enabled
-		cmds := []tea.Cmd{m.fetchWorkItems(), m.fetchWorkItemTypes()}
-		if m.notificationsEnabled {
+		// Without an area path, the team's areas scope the board once they're loaded
+		loadItems := m.fetchWorkItems()
+		if m.client != nil && m.client.UsesTeamAreas() {
+			loadItems = m.fetchTeamAreas()
+		}
+		cmds := []tea.Cmd{loadItems, m.fetchWorkItemTypes()}
+		// A reconnect keeps the ticker that is already running
+		if m.notificationsEnabled && !m.notifyTicking {
+			m.notifyTicking = true
 			cmds = append(cmds, m.startNotificationTicker())
 		}
+		// Open the item asked for on the command line; the board loads behind it for esc
+		if m.startupItemID > 0 {
+			m.loading = true
+			cmds = append(cmds, m.jumpToWorkItem(m.startupItemID))
+			m.startupItemID = 0
+		}
 		return m, tea.Batch(cmds...)
 
+	case teamAreasMsg:
+		// Without the team's areas the board falls back to the whole project
+		if msg.err == nil {
+			m.client.SetTeamAreas(msg.values)
+		}
+		return m, m.fetchWorkItems()
+
 	case tickMsg:
 		// Only check for changes if notifications are enabled and not on config screen
 		if m.notificationsEnabled && m.view != ViewConfig && m.view != ViewConfigFile && m.client != nil && m.username != "" {
@@ -390,19 +704,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.startNotificationTicker()
 
 	case notifyChangesMsg:
+		if msg.err != nil {
+			m.notifyFailures++
+		} else {
+			m.notifyFailures = 0
+		}
+		if msg.err == nil {
+			m.lastNotifyCheck = msg.checkedAt
+			if msg.userID != "" {
+				m.notifyUserID = msg.userID
+			}
+			// Don't re-check the comments of items whose change wasn't a new mention
+			m.trackRevisions(msg.seen...)
+		}
 		if msg.err == nil && len(msg.changedItems) > 0 {
 			// Play notification sound
-			playNotificationSound()
+			m.playNotification()
 			// Build notification message
 			if len(msg.changedItems) == 1 {
 				m.notifyMessage = fmt.Sprintf("🔔 Work item #%d changed: %s", msg.changedItems[0].ID, msg.changedItems[0].Fields.Title)
 			} else {
 				m.notifyMessage = fmt.Sprintf("🔔 %d work items changed", len(msg.changedItems))
 			}
-			// Update known revisions
-			for _, item := range msg.changedItems {
-				m.knownRevisions[item.ID] = item.Rev
-			}
+			m.recordNotifications(msg.changedItems, time.Now())
+			m.trackRevisions(msg.changedItems...)
 		}
 		// Continue ticking
 		return m, m.startNotificationTicker()
@@ -418,33 +743,59 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 		if msg.err != nil {
 			m.err = msg.err
+			if isNetworkError(msg.err) {
+				m = m.enterOffline()
+			}
 			return m, nil
 		}
 		m.workItems = msg.items
 		m.apiPage = 0
 		m.hasMoreData = len(msg.items) >= m.appConfig.MaxWorkItems
+		m.lastFetched = time.Now()
 		m.err = nil
 		m.message = ""
-		return m, nil
+		m = m.refilterFuzzy()
+		m.trackRevisions(msg.items...)
+		m, saveCache := m.cacheWorkItems(msg.items)
+		return m, tea.Batch(m.fetchParentTitles(), saveCache)
 
 	case workItemsPageMsg:
 		m.loading = false
 		if msg.err != nil {
 			m.err = msg.err
+			if isNetworkError(msg.err) {
+				m = m.enterOffline()
+			}
 			return m, nil
 		}
 		m.workItems = msg.items
 		m.apiPage = msg.page
 		m.hasMoreData = len(msg.items) >= m.appConfig.MaxWorkItems
+		m.lastFetched = time.Now()
 		m.cursor = 0
+		if msg.last {
+			m.hasMoreData = false
+			m.cursor = max(len(msg.items)-1, 0)
+		}
 		m.err = nil
 		m.message = ""
-		// Seed known revisions to prevent false positives on initial load
-		if m.knownRevisions != nil {
+		m = m.refilterFuzzy()
+		m.trackRevisions(msg.items...)
+		m, saveCache := m.cacheWorkItems(msg.items)
+		return m, tea.Batch(m.fetchParentTitles(), saveCache)
+
+	case parentTitlesMsg:
+		// Parent titles are decoration; a failed lookup just leaves the bare #ID. Misses are
+		// recorded as "" so they aren't looked up again on every load, until the next refresh.
+		if m.parentTitles == nil {
+			m.parentTitles = make(map[int]string)
+		}
+		for _, id := range msg.ids {
+			m.parentTitles[id] = ""
+		}
+		if msg.err == nil {
 			for _, item := range msg.items {
-				if _, exists := m.knownRevisions[item.ID]; !exists {
-					m.knownRevisions[item.ID] = item.Rev
-				}
+				m.parentTitles[item.ID] = item.Fields.Title
 			}
 		}
 		return m, nil
@@ -456,19 +807,72 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.message = fmt.Sprintf("Created work item #%d", msg.item.ID)
+		m = m.rememberAssignee(msg.assignee)
 		m.view = ViewBoard
 		for i := range m.createInputs {
 			m.createInputs[i].SetValue("")
 		}
+		m.cloneAreaPath = ""
+		m.cloneTags = ""
 		return m, m.fetchWorkItems()
 
 	case commentsMsg:
 		m.loading = false
-		if msg.err == nil {
-			m.comments = msg.comments
+		if azdo.IsNotFound(msg.err) {
+			return m.workItemGone(msg.workItemID)
+		}
+		// Drop comments loaded in the order used before a toggle
+		if msg.newestFirst != m.commentsNewestFirst {
+			return m, nil
+		}
+		if msg.more {
+			m.loadingMoreComments = false
+			// Drop a late page for an item the user has since left
+			if m.selectedItem == nil || m.selectedItem.ID != msg.workItemID {
+				return m, nil
+			}
+			if msg.err != nil {
+				m.err = msg.err
+				return m, nil
+			}
+			m.comments = append(m.comments, msg.comments...)
+			m.commentsToken = msg.nextToken
+			return m, nil
+		}
+		if msg.err != nil {
+			return m, nil
+		}
+		m.comments = msg.comments
+		m.commentsToken = msg.nextToken
+		m.latestComment = nil
+		updated, saveCache := m.cacheDetail(msg.workItemID, func(d *cachedDetail) { d.Comments = msg.comments })
+		if !msg.newestFirst && msg.nextToken != "" {
+			// The newest comment is on a later page; fetch it for the collapsed summary
+			return updated, tea.Batch(saveCache, updated.fetchLatestComment(msg.workItemID))
+		}
+		return updated, saveCache
+
+	case latestCommentMsg:
+		if msg.err == nil && m.selectedItem != nil && m.selectedItem.ID == msg.workItemID {
+			m.latestComment = msg.comment
 		}
 		return m, nil
 
+	case remainingCommentsMsg:
+		m.loading = false
+		// Drop pages for an item the user has since left or loaded in another order
+		if m.selectedItem == nil || m.selectedItem.ID != msg.workItemID || msg.newestFirst != m.commentsNewestFirst {
+			return m, nil
+		}
+		m.loadingMoreComments = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.comments = append(m.comments, msg.comments...)
+		m.commentsToken = ""
+		return m.copyComments(), nil
+
 	case addCommentMsg:
 		m.loading = false
 		if msg.err != nil {
@@ -486,16 +890,134 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.message = "Work item updated"
+		if m.selectedItem != nil && msg.assignee != detailFieldValues(m.selectedItem)[2] {
+			m = m.rememberAssignee(msg.assignee)
+		}
 		m.selectedItem = msg.item
+		m.pendingReason = ""
 		return m, nil
 
-	case relatedItemsMsg:
+	case stateReasonsMsg:
+		m.loading = false
+		// Drop reasons fetched for an item the user has since left
+		if m.selectedItem == nil || m.selectedItem.ID != msg.workItemID {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.stateReasons = msg.reasons
+		if len(msg.reasons) == 0 {
+			m.message = "No reasons are defined for this work item type"
+			return m, nil
+		}
+		m.pendingReason = nextReason(m.stateReasons, m.pendingReason)
+		return m, nil
+
+	case workflowStatesMsg:
+		m.loading = false
+		step := m.pendingStateStep
+		m.pendingStateStep = 0
+		if msg.err != nil {
+			// Close the menu so it doesn't keep waiting for states that won't arrive
+			m.stateMenuExpanded = false
+			m.err = msg.err
+			return m, nil
+		}
+		m.workflowStates = msg.states
+		m.workflowType = msg.workItemType
+		if step != 0 && m.selectedItem != nil && m.selectedItem.Fields.WorkItemType == msg.workItemType {
+			m = m.stepState(step)
+		}
+		if m.stateMenuExpanded {
+			m.stateMenuCursor = m.currentStateIndex()
+		}
+		return m, nil
+
+	case typeFieldsMsg:
+		// Without the list every field stays editable, so errors are ignored
+		if msg.err != nil {
+			return m, nil
+		}
+		m.readOnlyFields = make(map[string]bool)
+		for _, f := range msg.fields {
+			if f.ReadOnly {
+				m.readOnlyFields[f.ReferenceName] = true
+			}
+		}
+		m.readOnlyFieldsType = msg.workItemType
+		// Move off a field that turned out to be read-only
+		if m.view == ViewDetail && m.detailFieldReadOnly(m.detailFocus) {
+			m.detailFocus = m.nextEditableField(m.detailFocus, 1)
+			return m, m.updateDetailFocus()
+		}
+		return m, nil
+
+	case stateValuesMsg:
+		// The list only improves the hint and save check, so errors are ignored
 		if msg.err == nil {
-			m.parentItem = msg.parent
-			m.childItems = msg.children
+			m.stateValues = msg.values
+			m.stateValuesType = msg.workItemType
+		}
+		return m, nil
+
+	case assignMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		if msg.assignee == m.username {
+			m.message = fmt.Sprintf("Assigned #%d to you", msg.id)
+		} else {
+			m.message = fmt.Sprintf("Assigned #%d to %s", msg.id, msg.assignee)
 		}
+		return m, m.fetchWorkItemsPage(m.apiPage)
+
+	case bulkResultMsg:
+		return m.handleBulkResult(msg)
+
+	case importResultMsg:
+		return m.handleImportResult(msg)
+
+	case bulkProgressMsg:
+		// Progress can arrive after the action finished or out of order; only move forward
+		if m.bulkPending == msg.total && msg.done > m.bulkProgress.done {
+			m.bulkProgress = msg
+		}
+		return m, nil
+
+	case configSavedMsg:
+		if msg.err != nil {
+			m.err = fmt.Errorf("saving config: %w", msg.err)
+		}
+		return m, nil
+
+	case savedQueryMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.err = nil
+		m.message = fmt.Sprintf("Saved query %q to My Queries", msg.query.Name)
 		return m, nil
 
+	case relatedItemsMsg:
+		if azdo.IsNotFound(msg.err) {
+			return m.workItemGone(msg.workItemID)
+		}
+		if msg.err != nil {
+			return m, nil
+		}
+		m.parentItem = msg.parent
+		m.childItems = msg.children
+		return m.cacheDetail(msg.workItemID, func(d *cachedDetail) {
+			d.Parent = msg.parent
+			d.Children = msg.children
+		})
+
 	case createRelatedMsg:
 		m.loading = false
 		m.creatingRelated = false
@@ -508,6 +1030,50 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			relType = "child"
 		}
 		m.message = fmt.Sprintf("Created %s #%d", relType, msg.item.ID)
+		m = m.rememberAssignee(msg.assignee)
+		if m.view == ViewBoard {
+			// Show the new item on the board
+			m.loading = true
+			return m, m.fetchWorkItems()
+		}
+		// Refresh related items
+		return m, m.fetchRelatedItems(m.selectedItem.ID)
+
+	case jumpToWorkItemMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.view = ViewDetail
+		m.detailInputs[0].Focus()
+		return m.navigateToWorkItem(msg.item)
+
+	case refreshedItemMsg:
+		m.loading = false
+		if azdo.IsNotFound(msg.err) {
+			return m.workItemGone(msg.workItemID)
+		}
+		if m.selectedItem == nil || m.selectedItem.ID != msg.workItemID {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		return m.applyRefreshedItem(msg.item), nil
+
+	case linkExistingMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		relType := "parent"
+		if msg.asChild {
+			relType = "child"
+		}
+		m.message = fmt.Sprintf("Linked %s #%d", relType, msg.targetID)
 		// Refresh related items
 		return m, m.fetchRelatedItems(m.selectedItem.ID)
 
@@ -517,7 +1083,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = msg.err
 			return m, nil
 		}
-		m.message = "Link removed"
+		m.message = "Link removed (z: undo)"
+		m.lastUndo = &undoAction{kind: undoUnlink, workItemID: msg.workItemID, targetID: msg.targetID, isParent: msg.isParent}
 		m.relatedCursor = 0
 		// Refresh related items
 		return m, m.fetchRelatedItems(m.selectedItem.ID)
@@ -530,14 +1097,80 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = msg.err
 			return m, nil
 		}
-		m.message = fmt.Sprintf("Deleted work item #%d", m.deleteWorkItemID)
+		m.message = fmt.Sprintf("Deleted work item #%d (z: undo)", m.deleteWorkItemID)
+		m.lastUndo = &undoAction{kind: undoDelete, workItemID: m.deleteWorkItemID}
 		m.cursor = 0
 		return m, m.fetchWorkItems()
 
+	case undoMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.lastUndo = nil
+		m.err = nil
+		if msg.action.kind == undoDelete {
+			m.message = fmt.Sprintf("Restored work item #%d", msg.action.workItemID)
+			return m, m.fetchWorkItems()
+		}
+		m.message = fmt.Sprintf("Restored link to #%d", msg.action.targetID)
+		if m.view == ViewDetail && m.selectedItem != nil && m.selectedItem.ID == msg.action.workItemID {
+			return m, m.fetchRelatedItems(m.selectedItem.ID)
+		}
+		return m, nil
+
+	case tagsMsg:
+		// Autocomplete is best-effort; the editor works without suggestions
+		if msg.err == nil {
+			m.availableTags = msg.tags
+		}
+		return m, nil
+
+	case recycleBinMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.recycleBin = msg.items
+		if m.recycleBinCursor >= len(m.recycleBin) {
+			m.recycleBinCursor = max(len(m.recycleBin)-1, 0)
+		}
+		return m, nil
+
+	case restoreMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.err = nil
+		m.message = fmt.Sprintf("Restored work item #%d", msg.id)
+		if m.lastUndo != nil && m.lastUndo.kind == undoDelete && m.lastUndo.workItemID == msg.id {
+			m.lastUndo = nil
+		}
+		return m, tea.Batch(m.fetchRecycleBin(), m.fetchWorkItems())
+
 	case iterationsMsg:
 		if msg.err == nil {
 			m.iterations = msg.iterations
 		}
+		if m.pendingSprintMove {
+			m.pendingSprintMove = false
+			if msg.err != nil {
+				m.loading = false
+				m.err = msg.err
+				return m, nil
+			}
+			if len(msg.iterations) == 0 {
+				// Moving on would fetch the empty list again
+				m.loading = false
+				m.err = fmt.Errorf("no iterations found, so there is no current iteration")
+				return m, nil
+			}
+			return m.moveToCurrentSprint()
+		}
 		return m, nil
 
 	case updateIterationMsg:
@@ -551,6 +1184,66 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.iterationExpanded = false
 		return m, nil
 
+	case areasMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.areas = msg.areas
+		// Start the picker on the item's current area
+		if m.areaExpanded && m.selectedItem != nil {
+			m.areaCursor = max(slices.Index(m.areas, m.selectedItem.Fields.AreaPath), 0)
+		}
+		return m, nil
+
+	case updateAreaMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.message = "Area updated"
+		m.selectedItem = msg.item
+		m.areaExpanded = false
+		return m, nil
+
+	case activitiesMsg:
+		if msg.err != nil {
+			// Close the picker so it doesn't keep showing "Loading activities..."
+			m.activityExpanded = false
+			m.err = msg.err
+			return m, nil
+		}
+		m.activities = msg.activities
+		m.activitiesType = msg.workItemType
+		if m.selectedItem != nil {
+			m.activityCursor = max(slices.Index(m.activities, m.selectedItem.Fields.Activity), 0)
+		}
+		return m, nil
+
+	case updateActivityMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.message = "Activity updated"
+		m.selectedItem = msg.item
+		m.activityExpanded = false
+		return m, nil
+
+	case updateStateMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.message = fmt.Sprintf("State updated to %s", msg.item.Fields.State)
+		m.selectedItem = msg.item
+		m.detailInputs[1].SetValue(msg.item.Fields.State)
+		m.stateMenuExpanded = false
+		return m, nil
+
 	case updatePlanningMsg:
 		m.loading = false
 		if msg.err != nil {
@@ -559,8 +1252,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.message = "Planning updated"
 		m.selectedItem = msg.item
-		// Update planning inputs with the new values
-		m.updatePlanningInputsFromWorkItem()
+		// Update planning inputs with the new values, in the order they're shown
+		if len(m.planningFields) > 0 {
+			m.updatePlanningInputsFromWorkItemDynamic()
+		} else {
+			m.updatePlanningInputsFromWorkItem()
+		}
 		return m, nil
 
 	case planningFieldsMsg:
@@ -580,6 +1277,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case hyperlinksMsg:
+		if azdo.IsNotFound(msg.err) {
+			return m.workItemGone(msg.workItemID)
+		}
 		if msg.err == nil {
 			m.hyperlinks = msg.hyperlinks
 		}
@@ -621,6 +1321,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateDetail(msg)
 	case ViewConfigFile:
 		return m.updateConfigFile(msg)
+	case ViewRecycleBin:
+		return m.updateRecycleBin(msg)
+	case ViewOutline:
+		return m.updateOutline(msg)
+	case ViewAssignees:
+		return m.updateAssignees(msg)
 	}
 
 	return m, nil
@@ -628,19 +1334,60 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View implements tea.Model and renders the current view as a string.
 func (m Model) View() string {
-	switch m.view {
+	if m.showErrorLog {
+		return m.viewErrorLog()
+	}
+	if m.showNotifications {
+		return m.viewNotifications()
+	}
+	var view string
+	switch m.view {
 	case ViewConfig:
-		return m.viewConfig()
+		view = m.viewConfig()
 	case ViewBoard:
-		return m.viewBoard()
+		view = m.viewBoard()
 	case ViewCreate:
-		return m.viewCreate()
+		view = m.viewCreate()
 	case ViewDetail:
-		return m.viewDetail()
+		view = m.viewDetail()
 	case ViewConfigFile:
-		return m.viewConfigFile()
+		view = m.viewConfigFile()
+	case ViewRecycleBin:
+		view = m.viewRecycleBin()
+	case ViewOutline:
+		view = m.viewOutline()
+	case ViewAssignees:
+		view = m.viewAssignees()
 	}
-	return ""
+	if m.pendingWrite != nil {
+		view += "\n" + m.renderPendingWrite()
+	}
+	return view
+}
+
+// connectionLabel describes what the client is connected to (e.g. "org/project (team)")
+func (m Model) connectionLabel() string {
+	if m.client == nil {
+		return ""
+	}
+	label := fmt.Sprintf("%s/%s", m.client.Organization, m.client.Project)
+	if m.client.Team != "" {
+		label += fmt.Sprintf(" (%s)", m.client.Team)
+	}
+	return label
+}
+
+// renderConnectionBanner renders the connection label as a line shown above a screen's title
+func (m Model) renderConnectionBanner() string {
+	label := m.connectionLabel()
+	if label == "" {
+		return ""
+	}
+	banner := bannerStyle.Render("🔗 "+label) + "\n"
+	if m.offline {
+		banner += m.offlineBanner() + "\n"
+	}
+	return banner
 }
 
 func (m Model) fetchWorkItems() tea.Cmd {
@@ -649,20 +1396,88 @@ func (m Model) fetchWorkItems() tea.Cmd {
 
 func (m Model) fetchWorkItemsPage(page int) tea.Cmd {
 	return func() tea.Msg {
-		assignedTo := ""
-		if !m.showAll && m.username != "" {
-			assignedTo = m.username
+		if m.inMyQueue() && page == 0 {
+			items, err := m.client.GetMyWorkItems(m.username, m.appConfig.MaxWorkItems)
+			return workItemsPageMsg{items: items, page: page, err: err}
 		}
 		skip := page * m.appConfig.MaxWorkItems
-		items, err := m.client.GetWorkItemsPaged("", assignedTo, m.appConfig.MaxWorkItems, skip)
+		items, err := m.client.QueryWorkItems(m.workItemFilter(), m.appConfig.MaxWorkItems, skip)
 		return workItemsPageMsg{items: items, page: page, err: err}
 	}
 }
 
+// fetchLastWorkItemsPage fetches the final page of results, counting them from the cached ID list
+func (m Model) fetchLastWorkItemsPage() tea.Cmd {
+	return func() tea.Msg {
+		filter := m.workItemFilter()
+		count, err := m.client.CountWorkItems(filter)
+		if err != nil {
+			return workItemsPageMsg{err: err}
+		}
+		page := 0
+		if count > 0 {
+			page = (count - 1) / m.appConfig.MaxWorkItems
+		}
+		items, err := m.client.QueryWorkItems(filter, m.appConfig.MaxWorkItems, page*m.appConfig.MaxWorkItems)
+		return workItemsPageMsg{items: items, page: page, last: true, err: err}
+	}
+}
+
+// returnToBoard leaves the create or detail view for the board, discarding unsaved input
+func (m Model) returnToBoard() Model {
+	m.view = ViewBoard
+	m.err = nil
+	m.message = ""
+	m.cloneAreaPath = ""
+	m.cloneTags = ""
+	m.confirmingEsc = false
+	// Auto-collapse all expanded sections
+	m.collapseDetailSections()
+	m.tagEditing = false
+	return m
+}
+
+// inMyQueue reports whether the board shows the current user's queue
+func (m Model) inMyQueue() bool {
+	return m.myQueue && m.username != ""
+}
+
+// workItemFilter builds the board query filter from the current toggles. My queue is the
+// same query as GetMyWorkItems, so later pages match the first.
+func (m Model) workItemFilter() azdo.WorkItemFilter {
+	var filter azdo.WorkItemFilter
+	if m.inMyQueue() {
+		filter.AssignedTo = m.username
+		return filter
+	}
+	if !m.showAll && m.username != "" {
+		filter.AssignedTo = m.username
+	}
+	if m.hideCompleted {
+		filter.ExcludeStates = m.appConfig.DoneStates
+	}
+	if m.appConfig.HideRemoved && !slices.ContainsFunc(filter.ExcludeStates, isRemovedState) {
+		filter.ExcludeStates = append(slices.Clone(filter.ExcludeStates), removedState)
+	}
+	filter.IterationPath = m.iterationFilter
+	filter.CurrentIteration = m.currentIteration
+	filter.Search = m.searchText
+	filter.Sort = boardSorts[m.sortMode].sort
+	return filter
+}
+
 func (m Model) connect() tea.Cmd {
 	return func() tea.Msg {
 		err := m.client.TestConnection()
-		return connectMsg{err: err}
+		return connectMsg{err: err, deprecation: m.client.DeprecationWarning(), warning: m.client.ConnectionWarning()}
+	}
+}
+
+// fetchTeamAreas loads the area paths that belong to the team
+func (m Model) fetchTeamAreas() tea.Cmd {
+	return func() tea.Msg {
+		values, err := m.client.GetTeamFieldValues()
+		return teamAreasMsg{values: values, err: err}
 	}
 }
 
@@ -686,14 +1501,72 @@ func (m Model) createWorkItem() tea.Cmd {
 		assignedTo := m.createInputs[3].Value()
 		wiType := m.workItemTypes[m.createType]
 
-		item, err := m.client.CreateWorkItemWithAssignee(wiType, title, desc, priority, assignedTo)
-		return createResultMsg{item: item, err: err}
+		client := m.client
+		if m.cloneAreaPath != "" {
+			// Create the clone in the source item's area instead of the configured one
+			cloneClient := *m.client
+			cloneClient.AreaPath = m.cloneAreaPath
+			client = &cloneClient
+		}
+
+		// Clone tags go in the create request, so a failure never leaves an untagged copy
+		item, err := client.CreateWorkItemWithTags(wiType, title, desc, priority, assignedTo, m.cloneTags)
+		return createResultMsg{item: item, assignee: assignedTo, err: err}
 	}
 }
 
+type parentTitlesMsg struct {
+	ids   []int // parents looked up
+	items []azdo.WorkItem
+	err   error
+}
+
+type workflowStatesMsg struct {
+	workItemType string
+	states       []azdo.WorkItemState
+	err          error
+}
+
+type typeFieldsMsg struct {
+	workItemType string
+	fields       []azdo.WorkItemTypeField
+	err          error
+}
+
+type stateValuesMsg struct {
+	workItemType string
+	values       []string
+	err          error
+}
+
+type stateReasonsMsg struct {
+	workItemID int
+	reasons    []string
+	err        error
+}
+
 type commentsMsg struct {
-	comments []azdo.Comment
-	err      error
+	workItemID  int
+	comments    []azdo.Comment
+	nextToken   string // continuation token for the next page
+	more        bool   // true when this page extends the already loaded comments
+	newestFirst bool   // order the comments were requested in
+	err         error
+}
+
+// latestCommentMsg carries the newest comment of a work item, or nil when it has none
+type latestCommentMsg struct {
+	workItemID int
+	comment    *azdo.Comment
+	err        error
+}
+
+// remainingCommentsMsg carries every comment page after the loaded ones, for copying the thread
+type remainingCommentsMsg struct {
+	workItemID  int
+	comments    []azdo.Comment
+	newestFirst bool
+	err         error
 }
 
 type addCommentMsg struct {
@@ -701,26 +1574,133 @@ type addCommentMsg struct {
 }
 
 type updateWorkItemMsg struct {
+	item     *azdo.WorkItem
+	assignee string // assignee saved with the item, remembered once the save succeeds
+	err      error
+}
+
+type assignMsg struct {
+	id       int
+	assignee string
+	err      error
+}
+
+// bulkResultMsg reports the outcome of one item in a bulk action
+type bulkResultMsg struct {
+	id   int
 	item *azdo.WorkItem
 	err  error
 }
 
+// importResultMsg reports the outcome of creating the work item for one CSV row
+type importResultMsg struct {
+	line int
+	err  error
+	rest []importRow // rows still to import, created after this one
+}
+
+// bulkProgressMsg reports how many items of the running bulk action have completed
+type bulkProgressMsg struct {
+	done   int // items with a result so far
+	failed int // items whose update failed
+	total  int // items in the action
+}
+
+// configSavedMsg reports the outcome of saving the config file in the background
+type configSavedMsg struct {
+	err error
+}
+
+// savedQueryMsg reports the outcome of saving the board query
+type savedQueryMsg struct {
+	query *azdo.SavedQuery
+	err   error
+}
+
 type relatedItemsMsg struct {
-	parent   *azdo.WorkItem
-	children []azdo.WorkItem
-	err      error
+	workItemID int
+	parent     *azdo.WorkItem
+	children   []azdo.WorkItem
+	err        error
 }
 
 type createRelatedMsg struct {
-	item    *azdo.WorkItem
-	asChild bool
-	err     error
+	item     *azdo.WorkItem
+	asChild  bool
+	assignee string // assignee entered for the item, remembered once it's created
+	err      error
 }
 
 type removeLinkMsg struct {
+	workItemID int
+	targetID   int
+	isParent   bool
+	err        error
+}
+
+// undoKind identifies the kind of action that can be undone
+type undoKind int
+
+const (
+	undoDelete undoKind = iota // restore a deleted work item from the recycle bin
+	undoUnlink                 // re-add a removed parent/child link
+)
+
+// undoAction records enough context to reverse the last destructive action
+type undoAction struct {
+	kind       undoKind
+	workItemID int  // deleted item, or the item the link was removed from
+	targetID   int  // the other end of a removed link
+	isParent   bool // true if the removed link pointed to the parent
+}
+
+// description asks whether to perform the undo, for the write confirmation prompt
+func (a undoAction) description() string {
+	if a.kind == undoDelete {
+		return fmt.Sprintf("Restore work item #%d?", a.workItemID)
+	}
+	return fmt.Sprintf("Restore link to #%d?", a.targetID)
+}
+
+type undoMsg struct {
+	action undoAction
+	err    error
+}
+
+type tagsMsg struct {
+	tags []string
+	err  error
+}
+
+type recycleBinMsg struct {
+	items []azdo.DeletedWorkItem
+	err   error
+}
+
+type restoreMsg struct {
+	id  int
 	err error
 }
 
+type jumpToWorkItemMsg struct {
+	id   int
+	item *azdo.WorkItem
+	err  error
+}
+
+// refreshedItemMsg carries a re-fetched copy of the open work item
+type refreshedItemMsg struct {
+	workItemID int
+	item       *azdo.WorkItem
+	err        error
+}
+
+type linkExistingMsg struct {
+	targetID int
+	asChild  bool
+	err      error
+}
+
 type deleteWorkItemMsg struct {
 	err error
 }
@@ -735,6 +1715,32 @@ type updateIterationMsg struct {
 	err  error
 }
 
+type areasMsg struct {
+	areas []string
+	err   error
+}
+
+type updateAreaMsg struct {
+	item *azdo.WorkItem
+	err  error
+}
+
+type activitiesMsg struct {
+	workItemType string
+	activities   []string
+	err          error
+}
+
+type updateActivityMsg struct {
+	item *azdo.WorkItem
+	err  error
+}
+
+type updateStateMsg struct {
+	item *azdo.WorkItem
+	err  error
+}
+
 type updatePlanningMsg struct {
 	item *azdo.WorkItem
 	err  error
@@ -746,6 +1752,7 @@ type planningFieldsMsg struct {
 }
 
 type hyperlinksMsg struct {
+	workItemID int
 	hyperlinks []azdo.Hyperlink
 	err        error
 }
@@ -760,29 +1767,170 @@ type removeHyperlinkMsg struct {
 
 func (m Model) fetchComments(workItemID int) tea.Cmd {
 	return func() tea.Msg {
-		comments, err := m.client.GetComments(workItemID)
-		return commentsMsg{comments: comments, err: err}
+		comments, next, err := m.client.GetCommentsPageOrdered(workItemID, "", m.commentsNewestFirst)
+		return commentsMsg{workItemID: workItemID, comments: comments, nextToken: next, newestFirst: m.commentsNewestFirst, err: err}
+	}
+}
+
+// fetchMoreComments loads the next page of comments after the ones already shown
+func (m Model) fetchMoreComments(workItemID int, token string) tea.Cmd {
+	return func() tea.Msg {
+		comments, next, err := m.client.GetCommentsPageOrdered(workItemID, token, m.commentsNewestFirst)
+		return commentsMsg{workItemID: workItemID, comments: comments, nextToken: next, more: true, newestFirst: m.commentsNewestFirst, err: err}
+	}
+}
+
+// fetchLatestComment loads the newest comment by requesting the first page newest first
+func (m Model) fetchLatestComment(workItemID int) tea.Cmd {
+	return func() tea.Msg {
+		comments, _, err := m.client.GetCommentsPageOrdered(workItemID, "", true)
+		msg := latestCommentMsg{workItemID: workItemID, err: err}
+		if len(comments) > 0 {
+			msg.comment = &comments[0]
+		}
+		return msg
+	}
+}
+
+// fetchRemainingComments loads every page of comments from token onwards
+func (m Model) fetchRemainingComments(workItemID int, token string) tea.Cmd {
+	return func() tea.Msg {
+		var all []azdo.Comment
+		for token != "" {
+			comments, next, err := m.client.GetCommentsPageOrdered(workItemID, token, m.commentsNewestFirst)
+			if err != nil {
+				return remainingCommentsMsg{workItemID: workItemID, newestFirst: m.commentsNewestFirst, err: err}
+			}
+			all = append(all, comments...)
+			token = next
+		}
+		return remainingCommentsMsg{workItemID: workItemID, comments: all, newestFirst: m.commentsNewestFirst}
 	}
 }
 
 func (m Model) addComment(workItemID int, text string) tea.Cmd {
 	return func() tea.Msg {
-		err := m.client.AddComment(workItemID, text)
+		var err error
+		if m.appConfig.usesHistoryComments() {
+			err = m.client.AddHistoryComment(workItemID, text)
+		} else {
+			err = m.client.AddComment(workItemID, text)
+		}
 		return addCommentMsg{err: err}
 	}
 }
 
-func (m Model) updateWorkItem(workItemID int, title, state, assignedTo, tags string) tea.Cmd {
+func (m Model) updateWorkItem(workItemID int, title, state, reason, assignedTo, tags string) tea.Cmd {
+	return func() tea.Msg {
+		item, err := m.client.UpdateWorkItemWithReason(workItemID, title, state, reason, assignedTo, tags)
+		return updateWorkItemMsg{item: item, assignee: assignedTo, err: err}
+	}
+}
+
+// fetchParentTitles looks up the titles of board items' parents that aren't loaded yet,
+// in one batched request. It returns nil when there is nothing to look up.
+func (m Model) fetchParentTitles() tea.Cmd {
+	ids := missingParentIDs(m.workItems, m.parentTitles)
+	if len(ids) == 0 || m.client == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		items, err := m.client.GetWorkItemsByIDs(ids)
+		return parentTitlesMsg{ids: ids, items: items, err: err}
+	}
+}
+
+// fetchWorkflowStates loads the states and transitions of workItemType
+func (m Model) fetchWorkflowStates(workItemType string) tea.Cmd {
+	return func() tea.Msg {
+		states, err := m.client.GetWorkItemStates(workItemType)
+		return workflowStatesMsg{workItemType: workItemType, states: states, err: err}
+	}
+}
+
+// fetchReadOnlyFields loads the fields of wi's type to find the read-only ones,
+// unless they are already loaded
+func (m Model) fetchReadOnlyFields(wi *azdo.WorkItem) tea.Cmd {
+	if m.client == nil || m.readOnlyFieldsType == wi.Fields.WorkItemType {
+		return nil
+	}
+	workItemType := wi.Fields.WorkItemType
+	return func() tea.Msg {
+		fields, err := m.client.GetWorkItemTypeFields(workItemType)
+		return typeFieldsMsg{workItemType: workItemType, fields: fields, err: err}
+	}
+}
+
+// fetchStateValues loads the allowed values of the State field for workItemType
+func (m Model) fetchStateValues(workItemType string) tea.Cmd {
+	return func() tea.Msg {
+		values, err := m.client.GetFieldAllowedValues(workItemType, "System.State")
+		return stateValuesMsg{workItemType: workItemType, values: values, err: err}
+	}
+}
+
+// fetchStateReasons loads the reasons that can accompany a state change of wi's type
+func (m Model) fetchStateReasons(wi *azdo.WorkItem) tea.Cmd {
+	id, workItemType := wi.ID, wi.Fields.WorkItemType
+	return func() tea.Msg {
+		reasons, err := m.client.GetStateReasons(workItemType)
+		return stateReasonsMsg{workItemID: id, reasons: reasons, err: err}
+	}
+}
+
+// assignToMe assigns a work item to the current user, leaving its other fields untouched
+func (m Model) assignToMe(wi azdo.WorkItem) tea.Cmd {
+	return m.assignTo(wi, m.username)
+}
+
+// assignTo assigns a work item to assignee, leaving its other fields untouched
+func (m Model) assignTo(wi azdo.WorkItem, assignee string) tea.Cmd {
 	return func() tea.Msg {
-		item, err := m.client.UpdateWorkItem(workItemID, title, state, assignedTo, tags)
-		return updateWorkItemMsg{item: item, err: err}
+		_, err := m.client.UpdateAssignee(wi.ID, assignee)
+		return assignMsg{id: wi.ID, assignee: assignee, err: err}
 	}
 }
 
+// rememberAssignee records assignee as the last one entered, for A on the board.
+// Blank values and the current user (who already has m) are not remembered.
+func (m Model) rememberAssignee(assignee string) Model {
+	assignee = strings.TrimSpace(assignee)
+	if assignee != "" && !strings.EqualFold(assignee, m.username) {
+		m.lastAssignee = assignee
+	}
+	return m
+}
+
+// workItemGone returns to the board when the open work item no longer exists
+// (e.g. it was deleted in the web UI while open here)
+func (m Model) workItemGone(workItemID int) (Model, tea.Cmd) {
+	if m.view != ViewDetail || m.selectedItem == nil || m.selectedItem.ID != workItemID {
+		// A stale response for an item that's no longer open
+		return m, nil
+	}
+	m.view = ViewBoard
+	m.collapseDetailSections()
+	m.tagEditing = false
+	m.message = ""
+	m.err = fmt.Errorf("work item #%d no longer exists (it may have been deleted)", workItemID)
+	// Drop it from the board rather than refetching, which would clear the error
+	items := make([]azdo.WorkItem, 0, len(m.workItems))
+	for _, wi := range m.workItems {
+		if wi.ID != workItemID {
+			items = append(items, wi)
+		}
+	}
+	m.workItems = items
+	if m.cursor >= len(m.workItems) {
+		m.cursor = max(len(m.workItems)-1, 0)
+	}
+	return m, nil
+}
+
 func (m Model) fetchRelatedItems(workItemID int) tea.Cmd {
 	return func() tea.Msg {
 		parent, children, err := m.client.GetRelatedWorkItems(workItemID)
-		return relatedItemsMsg{parent: parent, children: children, err: err}
+		return relatedItemsMsg{workItemID: workItemID, parent: parent, children: children, err: err}
 	}
 }
 
@@ -795,6 +1943,14 @@ func (m Model) createRelatedItem(parentID int, asChild bool, title, wiType, assi
 			// Create a new work item as a child of the current item
 			item, err = m.client.CreateWorkItemWithParentAndAssignee(wiType, title, "", 2, parentID, assignee)
 		} else {
+			// A work item can only have one parent, so check before creating another
+			current, getErr := m.client.GetWorkItemWithRelations(parentID)
+			if getErr != nil {
+				return createRelatedMsg{asChild: asChild, err: getErr}
+			}
+			if existing := current.ParentID(); existing != 0 {
+				return createRelatedMsg{asChild: asChild, err: fmt.Errorf("#%d already has parent #%d; remove that link first", parentID, existing)}
+			}
 			// Create a new work item and make the current item its child
 			item, err = m.client.CreateWorkItemWithAssignee(wiType, title, "", 2, assignee)
 			if err == nil && item != nil {
@@ -803,14 +1959,103 @@ func (m Model) createRelatedItem(parentID int, asChild bool, title, wiType, assi
 			}
 		}
 
-		return createRelatedMsg{item: item, asChild: asChild, err: err}
+		return createRelatedMsg{item: item, asChild: asChild, assignee: assignee, err: err}
+	}
+}
+
+func (m Model) jumpToWorkItem(workItemID int) tea.Cmd {
+	return func() tea.Msg {
+		item, err := m.client.GetWorkItemWithRelations(workItemID)
+		if err != nil {
+			return jumpToWorkItemMsg{id: workItemID, err: fmt.Errorf("work item #%d not found: %w", workItemID, err)}
+		}
+		return jumpToWorkItemMsg{id: workItemID, item: item}
+	}
+}
+
+// refreshWorkItem re-fetches the open work item
+func (m Model) refreshWorkItem(workItemID int) tea.Cmd {
+	return func() tea.Msg {
+		item, err := m.client.GetWorkItemWithRelations(workItemID)
+		return refreshedItemMsg{workItemID: workItemID, item: item, err: err}
+	}
+}
+
+func (m Model) linkExistingItem(workItemID, targetID int, asChild bool) tea.Cmd {
+	return func() tea.Msg {
+		// Make sure the target exists before linking to it
+		if _, err := m.client.GetWorkItemWithRelations(targetID); err != nil {
+			return linkExistingMsg{targetID: targetID, asChild: asChild, err: fmt.Errorf("work item #%d not found: %w", targetID, err)}
+		}
+
+		// Catch duplicate links and cycles, which Azure DevOps rejects without explanation
+		parentID, childID := workItemID, targetID
+		if !asChild {
+			parentID, childID = targetID, workItemID
+		}
+		if err := m.client.CheckHierarchyLink(parentID, childID); err != nil {
+			return linkExistingMsg{targetID: targetID, asChild: asChild, err: err}
+		}
+
+		var err error
+		if asChild {
+			err = m.client.AddChildLink(workItemID, targetID)
+		} else {
+			err = m.client.AddParentLink(workItemID, targetID)
+		}
+		return linkExistingMsg{targetID: targetID, asChild: asChild, err: err}
 	}
 }
 
 func (m Model) removeLink(workItemID, targetID int, isParent bool) tea.Cmd {
 	return func() tea.Msg {
 		err := m.client.RemoveHierarchyLink(workItemID, targetID, isParent)
-		return removeLinkMsg{err: err}
+		return removeLinkMsg{workItemID: workItemID, targetID: targetID, isParent: isParent, err: err}
+	}
+}
+
+// undoLast reverses the last undoable action, if any
+func (m Model) undoLast() (Model, tea.Cmd) {
+	if m.lastUndo == nil {
+		m.message = "Nothing to undo"
+		return m, nil
+	}
+	action := *m.lastUndo
+	m.loading = true
+	return m.confirmWrite(action.description(), func() tea.Msg {
+		var err error
+		switch action.kind {
+		case undoDelete:
+			err = m.client.RestoreWorkItem(action.workItemID)
+		case undoUnlink:
+			if action.isParent {
+				err = m.client.AddParentLink(action.workItemID, action.targetID)
+			} else {
+				err = m.client.AddChildLink(action.workItemID, action.targetID)
+			}
+		}
+		return undoMsg{action: action, err: err}
+	})
+}
+
+func (m Model) fetchTags() tea.Cmd {
+	return func() tea.Msg {
+		tags, err := m.client.GetTags()
+		return tagsMsg{tags: tags, err: err}
+	}
+}
+
+func (m Model) fetchRecycleBin() tea.Cmd {
+	return func() tea.Msg {
+		items, err := m.client.ListRecycleBin()
+		return recycleBinMsg{items: items, err: err}
+	}
+}
+
+func (m Model) restoreWorkItem(workItemID int) tea.Cmd {
+	return func() tea.Msg {
+		err := m.client.RestoreWorkItem(workItemID)
+		return restoreMsg{id: workItemID, err: err}
 	}
 }
 
@@ -828,6 +2073,74 @@ func (m Model) fetchIterations() tea.Cmd {
 	}
 }
 
+func (m Model) fetchAreas() tea.Cmd {
+	return func() tea.Msg {
+		areas, err := m.client.GetAreas()
+		return areasMsg{areas: areas, err: err}
+	}
+}
+
+func (m Model) fetchActivities(workItemType string) tea.Cmd {
+	return func() tea.Msg {
+		activities, err := m.client.GetActivities(workItemType)
+		return activitiesMsg{workItemType: workItemType, activities: activities, err: err}
+	}
+}
+
+func (m Model) updateActivity(workItemID int, activity string) tea.Cmd {
+	return func() tea.Msg {
+		item, err := m.client.UpdateActivity(workItemID, activity)
+		return updateActivityMsg{item: item, err: err}
+	}
+}
+
+func (m Model) updateState(workItemID int, state string) tea.Cmd {
+	return func() tea.Msg {
+		item, err := m.client.UpdateState(workItemID, state)
+		return updateStateMsg{item: item, err: err}
+	}
+}
+
+func (m Model) updateArea(workItemID int, areaPath string) tea.Cmd {
+	return func() tea.Msg {
+		item, err := m.client.UpdateWorkItemArea(workItemID, areaPath)
+		return updateAreaMsg{item: item, err: err}
+	}
+}
+
+// moveToCurrentSprint moves the selected item into the current iteration, fetching
+// the iterations first if they haven't been loaded
+func (m Model) moveToCurrentSprint() (Model, tea.Cmd) {
+	if m.selectedItem == nil {
+		return m, nil
+	}
+	if len(m.iterations) == 0 {
+		m.loading = true
+		m.pendingSprintMove = true
+		return m, m.fetchIterations()
+	}
+	current := currentIteration(m.iterations)
+	if current == nil {
+		m.loading = false
+		m.err = fmt.Errorf("no current iteration found")
+		return m, nil
+	}
+	m.loading = true
+	m.err = nil
+	return m.confirmWrite(fmt.Sprintf("Move #%d to %s?", m.selectedItem.ID, current.Name), m.updateIteration(m.selectedItem.ID, current.Path))
+}
+
+// currentIteration returns the iteration whose timeframe is "current", or nil
+func currentIteration(iterations []azdo.Iteration) *azdo.Iteration {
+	now := time.Now()
+	for i, iter := range iterations {
+		if iterationGroup(iter, now) == "current" {
+			return &iterations[i]
+		}
+	}
+	return nil
+}
+
 func (m Model) updateIteration(workItemID int, iterationPath string) tea.Cmd {
 	return func() tea.Msg {
 		item, err := m.client.UpdateWorkItemIteration(workItemID, iterationPath)
@@ -882,6 +2195,13 @@ func (m *Model) updatePlanningInputsFromWorkItem() {
 	} else {
 		m.planningInputs[3].SetValue("")
 	}
+
+	// Effort
+	if m.selectedItem.Fields.Effort != nil {
+		m.planningInputs[4].SetValue(fmt.Sprintf("%.1f", *m.selectedItem.Fields.Effort))
+	} else {
+		m.planningInputs[4].SetValue("")
+	}
 }
 
 // updatePlanningInputsFromWorkItemDynamic populates the planning inputs based on dynamic field definitions
@@ -890,21 +2210,12 @@ func (m *Model) updatePlanningInputsFromWorkItemDynamic() {
 		return
 	}
 
-	// Map of reference names to current values
-	fieldValues := map[string]*float64{
-		"Microsoft.VSTS.Scheduling.StoryPoints":      m.selectedItem.Fields.StoryPoints,
-		"Microsoft.VSTS.Scheduling.OriginalEstimate": m.selectedItem.Fields.OriginalEstimate,
-		"Microsoft.VSTS.Scheduling.RemainingWork":    m.selectedItem.Fields.RemainingWork,
-		"Microsoft.VSTS.Scheduling.CompletedWork":    m.selectedItem.Fields.CompletedWork,
-		"Microsoft.VSTS.Scheduling.Effort":           m.selectedItem.Fields.Effort,
-	}
-
 	// Populate inputs based on the dynamic fields
 	for i, field := range m.planningFields {
 		if i >= len(m.planningInputs) {
 			break
 		}
-		if val, ok := fieldValues[field.ReferenceName]; ok && val != nil {
+		if val := m.selectedItem.Fields.PlanningValue(field.ReferenceName); val != nil {
 			m.planningInputs[i].SetValue(fmt.Sprintf("%.1f", *val))
 		} else {
 			m.planningInputs[i].SetValue("")
@@ -915,17 +2226,45 @@ func (m *Model) updatePlanningInputsFromWorkItemDynamic() {
 func (m Model) fetchHyperlinks(workItemID int) tea.Cmd {
 	return func() tea.Msg {
 		hyperlinks, err := m.client.GetHyperlinks(workItemID)
-		return hyperlinksMsg{hyperlinks: hyperlinks, err: err}
+		return hyperlinksMsg{workItemID: workItemID, hyperlinks: hyperlinks, err: err}
 	}
 }
 
+// addHyperlink adds a web link, or an artifact link when given a vstfs:/// URI
 func (m Model) addHyperlink(workItemID int, url string, comment string) tea.Cmd {
 	return func() tea.Msg {
+		if strings.HasPrefix(url, "vstfs:///") {
+			name, ok := artifactLinkName(url)
+			if !ok {
+				return addHyperlinkMsg{err: fmt.Errorf("unknown artifact type in %s", url)}
+			}
+			return addHyperlinkMsg{err: m.client.AddArtifactLink(workItemID, url, name, comment)}
+		}
 		err := m.client.AddHyperlink(workItemID, url, comment)
 		return addHyperlinkMsg{err: err}
 	}
 }
 
+// artifactLinkNames maps vstfs:/// URI prefixes to the artifact link type they are added as
+var artifactLinkNames = []struct{ prefix, name string }{
+	{"vstfs:///Git/PullRequestId/", "Pull Request"},
+	{"vstfs:///Git/Commit/", "Fixed in Commit"},
+	{"vstfs:///Git/Ref/", "Branch"},
+	{"vstfs:///GitHub/PullRequest/", "GitHub Pull Request"},
+	{"vstfs:///GitHub/Commit/", "GitHub Commit"},
+	{"vstfs:///Build/Build/", "Build"},
+}
+
+// artifactLinkName returns the artifact link type for a vstfs:/// URI
+func artifactLinkName(uri string) (string, bool) {
+	for _, a := range artifactLinkNames {
+		if strings.HasPrefix(uri, a.prefix) {
+			return a.name, true
+		}
+	}
+	return "", false
+}
+
 func (m Model) removeHyperlink(workItemID int, url string) tea.Cmd {
 	return func() tea.Msg {
 		err := m.client.RemoveHyperlink(workItemID, url)
@@ -940,56 +2279,188 @@ func (m Model) startNotificationTicker() tea.Cmd {
 	})
 }
 
-// checkForChanges fetches recently changed work items and compares against known revisions
+// checkForChanges fetches recently changed work items and compares against known revisions.
+// Depending on the notification scope it looks at items assigned to the user, items with
+// comments @mentioning them since the last check, or both.
 func (m Model) checkForChanges() tea.Cmd {
+	scope := m.appConfig.notifyScope()
+	since := m.lastNotifyCheck
 	return func() tea.Msg {
-		// Fetch work items assigned to user that changed in the last 2 minutes
-		// (slightly longer than our check interval to catch any changes)
-		items, err := m.client.GetRecentlyChangedWorkItems(m.username, 2)
-		if err != nil {
-			return notifyChangesMsg{err: err}
+		checkedAt := time.Now()
+		var assigned, mentioned, seen []azdo.WorkItem
+		if scope != notifyScopeMentioned {
+			// Fetch work items assigned to user that changed in the last 2 minutes
+			// (slightly longer than our check interval to catch any changes)
+			items, err := m.client.GetRecentlyChangedWorkItems(m.username, 2)
+			if err != nil {
+				return notifyChangesMsg{err: err}
+			}
+			assigned = m.changedSinceKnown(items)
 		}
 
-		// Find items that have changed or are newly assigned since we last checked
-		var changedItems []azdo.WorkItem
-		for _, item := range items {
-			if knownRev, exists := m.knownRevisions[item.ID]; exists {
-				// Item exists in our cache - check if revision changed
-				if item.Rev > knownRev {
-					changedItems = append(changedItems, item)
+		userID := m.notifyUserID
+		if scope != notifyScopeAssigned {
+			var err error
+			if userID == "" {
+				if userID, err = m.client.GetAuthenticatedUserID(); err != nil {
+					return notifyChangesMsg{err: err}
 				}
-			} else {
-				// New item we haven't seen before
-				// Only notify if we have already seeded the cache (not first load)
-				// and the item was recently changed (within our check window)
-				if len(m.knownRevisions) > 0 {
-					changedItems = append(changedItems, item)
+			}
+			items, err := m.client.GetRecentlyMentionedWorkItems(m.username, 2)
+			if err != nil {
+				return notifyChangesMsg{err: err}
+			}
+			// An item mentioning the user changed; notify only if the change is a new mention
+			for _, item := range m.changedSinceKnown(items) {
+				comments, _, err := m.client.GetCommentsPageOrdered(item.ID, "", true)
+				if err != nil {
+					return notifyChangesMsg{err: err}
+				}
+				if hasNewMention(comments, userID, since) {
+					mentioned = append(mentioned, item)
+				} else {
+					seen = append(seen, item)
 				}
 			}
 		}
 
-		return notifyChangesMsg{changedItems: changedItems, err: nil}
+		return notifyChangesMsg{changedItems: scopeChanges(scope, assigned, mentioned), seen: seen, userID: userID, checkedAt: checkedAt}
 	}
 }
 
-// playNotificationSound plays a system notification sound
-func playNotificationSound() {
-	var cmd *exec.Cmd
-	switch runtime.GOOS {
+// trackRevisions records the revisions of fetched work items, so changes already seen
+// on the board or in the detail view (including the user's own edits) aren't notified
+// again. Known revisions only move forward, so stale cached copies can't hide a change.
+func (m Model) trackRevisions(items ...azdo.WorkItem) {
+	if m.knownRevisions == nil {
+		return
+	}
+	for _, item := range items {
+		if item.Rev > m.knownRevisions[item.ID] {
+			m.knownRevisions[item.ID] = item.Rev
+		}
+	}
+}
+
+// changedSinceKnown returns the items whose revision is newer than the known one, plus
+// items not seen before once the known revisions have been seeded (not first load)
+func (m Model) changedSinceKnown(items []azdo.WorkItem) []azdo.WorkItem {
+	var changedItems []azdo.WorkItem
+	for _, item := range items {
+		if knownRev, exists := m.knownRevisions[item.ID]; exists {
+			// Item exists in our cache - check if revision changed
+			if item.Rev > knownRev {
+				changedItems = append(changedItems, item)
+			}
+		} else if len(m.knownRevisions) > 0 {
+			// New item we haven't seen before
+			changedItems = append(changedItems, item)
+		}
+	}
+	return changedItems
+}
+
+// scopeChanges returns the changes to notify about for a notification scope: changed
+// assigned items, items with new mentions, or both without repeating an item
+func scopeChanges(scope string, assigned, mentioned []azdo.WorkItem) []azdo.WorkItem {
+	switch scope {
+	case notifyScopeMentioned:
+		return mentioned
+	case notifyScopeBoth:
+		changes := slices.Clone(assigned)
+		for _, item := range mentioned {
+			if !slices.ContainsFunc(changes, func(wi azdo.WorkItem) bool { return wi.ID == item.ID }) {
+				changes = append(changes, item)
+			}
+		}
+		return changes
+	}
+	return assigned
+}
+
+// mentionIDRegex matches the identity ID in a comment's @mention anchor tag
+var mentionIDRegex = regexp.MustCompile(`data-vss-mention="version:[^,]*,([^"]*)"`)
+
+// hasNewMention reports whether any comment created after since @mentions the user with userID
+func hasNewMention(comments []azdo.Comment, userID string, since time.Time) bool {
+	for _, c := range comments {
+		created, err := time.Parse(time.RFC3339, c.CreatedDate)
+		if err != nil || !created.After(since) {
+			continue
+		}
+		for _, match := range mentionIDRegex.FindAllStringSubmatch(c.Text, -1) {
+			if strings.EqualFold(match[1], userID) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// notificationPlayer plays the notification sound for the given custom file path.
+// It is a variable so tests can observe notifications without playing audio.
+var notificationPlayer = playNotificationSound
+
+// playNotification plays the notification sound unless sounds are muted
+func (m Model) playNotification() {
+	if m.soundMuted {
+		return
+	}
+	notificationPlayer(m.appConfig.NotificationSound)
+}
+
+// defaultSoundFile returns the system notification sound for the given OS
+func defaultSoundFile(goos string) string {
+	switch goos {
+	case "darwin":
+		return "/System/Library/Sounds/Ping.aiff"
+	case "linux":
+		return "/usr/share/sounds/freedesktop/stereo/message.oga"
+	case "windows":
+		return "C:\\Windows\\Media\\notify.wav"
+	}
+	return ""
+}
+
+// soundCommand returns the program and arguments used to play a notification sound.
+// The custom sound file is used when set, otherwise the OS default. An empty name
+// means the sound file is missing or the OS is unsupported, so the terminal bell
+// should be used instead.
+func soundCommand(goos, customPath string) (name string, args []string) {
+	soundFile := customPath
+	if soundFile == "" {
+		soundFile = defaultSoundFile(goos)
+	}
+	if soundFile == "" {
+		return "", nil
+	}
+	if _, err := os.Stat(soundFile); err != nil {
+		return "", nil
+	}
+
+	switch goos {
 	case "darwin":
-		// macOS: use afplay with system sound
-		cmd = exec.Command("afplay", "/System/Library/Sounds/Ping.aiff")
+		// macOS: use afplay
+		return "afplay", []string{soundFile}
 	case "linux":
-		// Linux: try paplay (PulseAudio) with freedesktop sound
-		cmd = exec.Command("paplay", "/usr/share/sounds/freedesktop/stereo/message.oga")
+		// Linux: try paplay (PulseAudio)
+		return "paplay", []string{soundFile}
 	case "windows":
-		// Windows: use PowerShell to play system sound
-		cmd = exec.Command("powershell", "-c", "(New-Object Media.SoundPlayer 'C:\\Windows\\Media\\notify.wav').PlaySync()")
-	default:
+		// Windows: use PowerShell to play the sound; quotes in the path are doubled to escape them
+		quoted := strings.ReplaceAll(soundFile, "'", "''")
+		return "powershell", []string{"-c", fmt.Sprintf("(New-Object Media.SoundPlayer '%s').PlaySync()", quoted)}
+	}
+	return "", nil
+}
+
+// playNotificationSound plays a system notification sound, or the custom sound file if set
+func playNotificationSound(customPath string) {
+	name, args := soundCommand(runtime.GOOS, customPath)
+	if name == "" {
 		// Fallback: print bell character to terminal
 		fmt.Print("\a")
 		return
 	}
 	// Run in background, ignore errors (sound is optional)
-	_ = cmd.Start()
+	_ = exec.Command(name, args...).Start() // #nosec G204 -- player is fixed, path comes from user config
 }
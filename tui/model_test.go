@@ -1,10 +1,21 @@
 package tui
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/laupski/bored/azdo"
+	"github.com/zalando/go-keyring"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -193,6 +204,93 @@ func TestConnectMsg(t *testing.T) {
 	}
 }
 
+func TestConnectLoadsTeamAreasBeforeWorkItems(t *testing.T) {
+	var paths []string
+	m := NewModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"defaultValue": "testproject\\Web", "values": [{"value": "testproject\\Web", "includeChildren": true}]}`))
+	})
+
+	newModel, cmd := m.Update(connectMsg{})
+	m = newModel.(Model)
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) == 0 {
+		t.Fatal("Expected connect to start loading")
+	}
+	msg := batch[0]()
+	areas, ok := msg.(teamAreasMsg)
+	if !ok || areas.err != nil || len(paths) != 1 || !strings.HasSuffix(paths[0], "/teamfieldvalues") {
+		t.Fatalf("Expected the team's areas to be fetched, got %#v (paths %v)", msg, paths)
+	}
+
+	newModel, cmd = m.Update(areas)
+	m = newModel.(Model)
+	if cmd == nil {
+		t.Fatal("Expected the work items to be fetched once the team's areas are known")
+	}
+	if !m.client.UsesTeamAreas() {
+		t.Error("Expected the client to use the team's areas")
+	}
+}
+
+func TestConnectOpensStartupItem(t *testing.T) {
+	m := NewModel()
+	m.appConfig.EnableNotifications = false // no ticker to wait on
+	m.OpenOnConnect(42)
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/workitems/42") {
+			_, _ = w.Write([]byte(`{"id": 42, "fields": {"System.Title": "Deep Linked", "System.WorkItemType": "Bug"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	newModel, cmd := m.Update(connectMsg{})
+	m = newModel.(Model)
+	if m.startupItemID != 0 {
+		t.Error("Expected the startup item to be opened only once")
+	}
+	var opened bool
+	for _, c := range cmd().(tea.BatchMsg) {
+		if c == nil {
+			continue
+		}
+		if msg, ok := c().(jumpToWorkItemMsg); ok {
+			newModel, _ = m.Update(msg)
+			m = newModel.(Model)
+			opened = true
+		}
+	}
+	if !opened {
+		t.Fatal("Expected connect to fetch the startup item")
+	}
+	if m.view != ViewDetail || m.selectedItem == nil || m.selectedItem.ID != 42 {
+		t.Errorf("Expected the detail view of #42, got view %v", m.view)
+	}
+}
+
+func TestConnectMsgDeprecationNotice(t *testing.T) {
+	m := NewModel()
+	m.client = azdo.NewClient("org", "proj", "", "", "pat")
+
+	newModel, _ := m.Update(connectMsg{deprecation: "api-version 7.0 is deprecated"})
+	updated := newModel.(Model)
+
+	if !strings.Contains(updated.notifyMessage, "api-version 7.0 is deprecated") {
+		t.Errorf("notifyMessage = %q, want deprecation notice", updated.notifyMessage)
+	}
+
+	// Other warnings aren't labelled as deprecations
+	newModel, _ = m.Update(connectMsg{warning: "Service maintenance tonight"})
+	updated = newModel.(Model)
+	if !strings.Contains(updated.notifyMessage, "Azure DevOps warning: Service maintenance tonight") {
+		t.Errorf("notifyMessage = %q, want a generic warning", updated.notifyMessage)
+	}
+}
+
 func TestWorkItemsMsg(t *testing.T) {
 	m := NewModel()
 	m.loading = true
@@ -326,3 +424,736 @@ type modelTestError struct {
 func (e *modelTestError) Error() string {
 	return e.msg
 }
+
+func TestSoundCommand(t *testing.T) {
+	soundFile := filepath.Join(t.TempDir(), "ding.wav")
+	if err := os.WriteFile(soundFile, []byte("RIFF"), 0600); err != nil {
+		t.Fatalf("failed to write sound file: %v", err)
+	}
+	missingFile := filepath.Join(t.TempDir(), "missing.wav")
+
+	tests := []struct {
+		name       string
+		goos       string
+		customPath string
+		wantName   string
+	}{
+		{name: "custom file on linux", goos: "linux", customPath: soundFile, wantName: "paplay"},
+		{name: "custom file on macOS", goos: "darwin", customPath: soundFile, wantName: "afplay"},
+		{name: "custom file on windows", goos: "windows", customPath: soundFile, wantName: "powershell"},
+		{name: "missing custom file falls back to bell", goos: "linux", customPath: missingFile, wantName: ""},
+		{name: "unsupported OS falls back to bell", goos: "plan9", customPath: soundFile, wantName: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args := soundCommand(tt.goos, tt.customPath)
+			if name != tt.wantName {
+				t.Errorf("soundCommand() name = %q, want %q", name, tt.wantName)
+			}
+			if name == "" {
+				return
+			}
+			if !strings.Contains(strings.Join(args, " "), soundFile) {
+				t.Errorf("soundCommand() args = %v, want them to reference %q", args, soundFile)
+			}
+		})
+	}
+}
+
+func TestSoundCommandEscapesWindowsPath(t *testing.T) {
+	soundFile := filepath.Join(t.TempDir(), "it's'; calc; '.wav")
+	if err := os.WriteFile(soundFile, []byte("RIFF"), 0600); err != nil {
+		t.Fatalf("failed to write sound file: %v", err)
+	}
+
+	_, args := soundCommand("windows", soundFile)
+	if len(args) != 2 || !strings.Contains(args[1], "it''s''; calc; ''.wav').PlaySync()") {
+		t.Errorf("soundCommand() args = %v, want the quotes in the path doubled", args)
+	}
+}
+
+func TestPlayNotificationMuted(t *testing.T) {
+	var played []string
+	original := notificationPlayer
+	notificationPlayer = func(customPath string) { played = append(played, customPath) }
+	defer func() { notificationPlayer = original }()
+
+	m := setupBoardModel()
+	m.appConfig.NotificationSound = "/tmp/ding.wav"
+
+	m.playNotification()
+	if len(played) != 1 || played[0] != "/tmp/ding.wav" {
+		t.Fatalf("played = %v, want custom sound played once", played)
+	}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+	m = newModel.(Model)
+	if !m.soundMuted {
+		t.Fatal("S should mute notification sounds")
+	}
+
+	m.playNotification()
+	if len(played) != 1 {
+		t.Errorf("played = %v, want no sound while muted", played)
+	}
+}
+
+func TestNotificationFailuresSurfaceWarning(t *testing.T) {
+	m := setupBoardModel()
+	// An invalid organization makes every request fail before reaching the network
+	m.client = azdo.NewClient("%zz", "testproject", "", "", "testpat")
+	m.username = "me@example.com"
+	m.notificationsEnabled = true
+
+	for i := 0; i < notifyFailureThreshold; i++ {
+		if strings.Contains(m.viewBoard(), "change checks failing") {
+			t.Fatalf("warning shown after only %d failures", i)
+		}
+		msg := m.checkForChanges()()
+		if result, ok := msg.(notifyChangesMsg); !ok || result.err == nil {
+			t.Fatalf("checkForChanges() = %#v, want notifyChangesMsg with error", msg)
+		}
+		newModel, _ := m.Update(msg)
+		m = newModel.(Model)
+	}
+
+	if m.notifyFailures != notifyFailureThreshold {
+		t.Errorf("notifyFailures = %d, want %d", m.notifyFailures, notifyFailureThreshold)
+	}
+	if !strings.Contains(m.viewBoard(), "change checks failing") {
+		t.Error("board footer should warn that change checks are failing")
+	}
+
+	newModel, _ := m.Update(notifyChangesMsg{})
+	m = newModel.(Model)
+	if m.notifyFailures != 0 {
+		t.Errorf("notifyFailures = %d, want reset after a successful check", m.notifyFailures)
+	}
+}
+
+func TestScopeChanges(t *testing.T) {
+	item := func(id int) azdo.WorkItem { return azdo.WorkItem{ID: id} }
+	assigned := []azdo.WorkItem{item(1), item(2)}
+	mentioned := []azdo.WorkItem{item(2), item(3)}
+	tests := []struct {
+		scope string
+		want  []int
+	}{
+		{notifyScopeAssigned, []int{1, 2}},
+		{notifyScopeMentioned, []int{2, 3}},
+		{notifyScopeBoth, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		if got := itemIDs(scopeChanges(tt.scope, assigned, mentioned)); !slices.Equal(got, tt.want) {
+			t.Errorf("scopeChanges(%q) = %v, want %v", tt.scope, got, tt.want)
+		}
+	}
+
+	for value, want := range map[string]string{"": notifyScopeAssigned, "Mentioned": notifyScopeMentioned, "both": notifyScopeBoth, "everything": notifyScopeAssigned} {
+		if got := (AppConfig{NotificationScope: value}).notifyScope(); got != want {
+			t.Errorf("notifyScope(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+// mentionComment returns a comment created at created that @mentions the identity id
+func mentionComment(id, created string) azdo.Comment {
+	return azdo.Comment{
+		Text:        `<div><a href="#" data-vss-mention="version:2.0,` + id + `">@Someone</a> can you look?</div>`,
+		CreatedDate: created,
+	}
+}
+
+func TestHasNewMention(t *testing.T) {
+	since := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		comments []azdo.Comment
+		want     bool
+	}{
+		{"new mention", []azdo.Comment{mentionComment("ME-ID", "2024-03-01T12:05:00Z")}, true},
+		{"old mention", []azdo.Comment{mentionComment("me-id", "2024-03-01T11:00:00Z")}, false},
+		{"someone else", []azdo.Comment{mentionComment("other-id", "2024-03-01T12:05:00Z")}, false},
+		{"no mention", []azdo.Comment{{Text: "me-id", CreatedDate: "2024-03-01T12:05:00Z"}}, false},
+	}
+	for _, tt := range tests {
+		if got := hasNewMention(tt.comments, "me-id", since); got != tt.want {
+			t.Errorf("%s: hasNewMention() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckForChangesScope(t *testing.T) {
+	original := notificationPlayer
+	notificationPlayer = func(string) {}
+	defer func() { notificationPlayer = original }()
+
+	recent := time.Now().UTC().Format(time.RFC3339)
+	m := setupBoardModel()
+	m.username = "me@example.com"
+	m.knownRevisions = map[int]int{1: 1, 5: 1, 6: 1}
+	m.lastNotifyCheck = time.Now().Add(-time.Minute)
+	userLookups := 0
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/connectionData"):
+			userLookups++
+			_, _ = w.Write([]byte(`{"authenticatedUser": {"id": "me-id"}}`))
+		case strings.Contains(r.URL.Path, "/wiql"):
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			// Item 1 is assigned to the user; 5 and 6 mention them
+			refs := []azdo.WorkItemRef{{ID: 1}}
+			if strings.Contains(body["query"], "@RecentMentions") {
+				refs = []azdo.WorkItemRef{{ID: 5}, {ID: 6}}
+			}
+			_ = json.NewEncoder(w).Encode(azdo.WorkItemQueryResult{WorkItems: refs})
+		case strings.HasSuffix(r.URL.Path, "/workitems/5/comments"):
+			_ = json.NewEncoder(w).Encode(azdo.CommentsResponse{Comments: []azdo.Comment{mentionComment("me-id", recent)}})
+		case strings.HasSuffix(r.URL.Path, "/workitems/6/comments"):
+			// Changed, but the new comment mentions someone else
+			_ = json.NewEncoder(w).Encode(azdo.CommentsResponse{Comments: []azdo.Comment{mentionComment("other-id", recent)}})
+		case strings.HasSuffix(r.URL.Path, "/workitems"):
+			var items []azdo.WorkItem
+			for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+				n, _ := strconv.Atoi(id)
+				items = append(items, azdo.WorkItem{ID: n, Rev: 2})
+			}
+			_ = json.NewEncoder(w).Encode(azdo.WorkItemListResponse{Count: len(items), Value: items})
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+
+	tests := []struct {
+		scope string
+		want  []int
+	}{
+		{"", []int{1}},
+		{notifyScopeMentioned, []int{5}},
+		{notifyScopeBoth, []int{1, 5}},
+	}
+	for _, tt := range tests {
+		m.appConfig.NotificationScope = tt.scope
+		msg, ok := m.checkForChanges()().(notifyChangesMsg)
+		if !ok || msg.err != nil {
+			t.Fatalf("scope %q: checkForChanges() = %#v", tt.scope, msg)
+		}
+		if got := itemIDs(msg.changedItems); !slices.Equal(got, tt.want) {
+			t.Errorf("scope %q: changed items = %v, want %v", tt.scope, got, tt.want)
+		}
+		// The user's identity is looked up once and kept for later checks
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+		// An item changed without a new mention isn't checked again
+		if tt.scope != "" && m.knownRevisions[6] != 2 {
+			t.Errorf("scope %q: known revision of #6 = %d, want 2", tt.scope, m.knownRevisions[6])
+		}
+		m.knownRevisions = map[int]int{1: 1, 5: 1, 6: 1}
+		m.lastNotifyCheck = time.Now().Add(-time.Minute)
+	}
+	if userLookups != 1 || m.notifyUserID != "me-id" {
+		t.Errorf("Expected one identity lookup, got %d (id %q)", userLookups, m.notifyUserID)
+	}
+}
+
+func TestDetailUpdatesKnownRevision(t *testing.T) {
+	original := notificationPlayer
+	notificationPlayer = func(string) {}
+	defer func() { notificationPlayer = original }()
+
+	m := setupBoardModel()
+	m.username = "me@example.com"
+	m.knownRevisions = map[int]int{1: 1, 2: 1}
+	serverRev := 3
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/wiql"):
+			_ = json.NewEncoder(w).Encode(azdo.WorkItemQueryResult{WorkItems: []azdo.WorkItemRef{{ID: 1}}})
+		case strings.HasSuffix(r.URL.Path, "/workitems"):
+			items := []azdo.WorkItem{{ID: 1, Rev: serverRev}}
+			_ = json.NewEncoder(w).Encode(azdo.WorkItemListResponse{Count: len(items), Value: items})
+		}
+	})
+
+	// Opening the item in detail fetches revision 3
+	item := azdo.WorkItem{ID: 1, Rev: 3}
+	updated, _ := m.Update(jumpToWorkItemMsg{item: &item})
+	m = updated.(Model)
+	if m.knownRevisions[1] != 3 {
+		t.Fatalf("known revision of #1 = %d, want 3 after opening it", m.knownRevisions[1])
+	}
+
+	// The change check sees the same revision, which the user has already seen
+	msg := m.checkForChanges()().(notifyChangesMsg)
+	if msg.err != nil || len(msg.changedItems) != 0 {
+		t.Errorf("checkForChanges() = %#v, want no duplicate notification", msg)
+	}
+
+	// A later change is still notified
+	serverRev = 4
+	msg = m.checkForChanges()().(notifyChangesMsg)
+	if got := itemIDs(msg.changedItems); !slices.Equal(got, []int{1}) {
+		t.Errorf("changed items = %v, want [1]", got)
+	}
+
+	// A stale copy never moves the known revision back
+	m.trackRevisions(azdo.WorkItem{ID: 1, Rev: 2})
+	if m.knownRevisions[1] != 3 {
+		t.Errorf("known revision of #1 = %d, want it kept at 3", m.knownRevisions[1])
+	}
+}
+
+// rewriteTransport sends every request to a test server instead of Azure DevOps
+type rewriteTransport struct {
+	serverURL string
+	transport http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	req.URL.Host = strings.TrimPrefix(t.serverURL, "http://")
+	return t.transport.RoundTrip(req)
+}
+
+// mockClient returns a client whose requests are served by handler
+func mockClient(t *testing.T, handler http.HandlerFunc) *azdo.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	hc := &http.Client{Transport: &rewriteTransport{serverURL: server.URL, transport: http.DefaultTransport}}
+	return azdo.NewClientWithHTTP("testorg", "testproject", "testteam", "", "testpat", hc)
+}
+
+func TestSetClientFetchWorkItems(t *testing.T) {
+	client := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/wiql") {
+			_ = json.NewEncoder(w).Encode(azdo.WorkItemQueryResult{
+				WorkItems: []azdo.WorkItemRef{{ID: 7}, {ID: 8}},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(azdo.WorkItemListResponse{
+			Count: 2,
+			Value: []azdo.WorkItem{
+				{ID: 7, Fields: azdo.WorkItemFields{Title: "Mocked Seven"}},
+				{ID: 8, Fields: azdo.WorkItemFields{Title: "Mocked Eight"}},
+			},
+		})
+	})
+
+	m := NewModel()
+	m.SetClient(client)
+	m.showAll = true
+
+	msg := m.fetchWorkItems()()
+	result, ok := msg.(workItemsPageMsg)
+	if !ok {
+		t.Fatalf("fetchWorkItems() returned %T, want workItemsPageMsg", msg)
+	}
+	if result.err != nil {
+		t.Fatalf("fetchWorkItems() error: %v", result.err)
+	}
+	if result.page != 0 {
+		t.Errorf("page = %d, want 0", result.page)
+	}
+	if len(result.items) != 2 || result.items[0].Fields.Title != "Mocked Seven" {
+		t.Errorf("items = %+v, want the mocked work items", result.items)
+	}
+
+	newModel, _ := m.Update(result)
+	m = newModel.(Model)
+	if len(m.workItems) != 2 {
+		t.Errorf("workItems = %d, want 2 after handling the message", len(m.workItems))
+	}
+}
+
+func TestUndoDeleteRestoresFromRecycleBin(t *testing.T) {
+	var restorePath string
+	client := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			restorePath = r.URL.Path
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	m := setupBoardModel()
+	m.SetClient(client)
+	m.deleteWorkItemID = 2
+
+	newModel, _ := m.Update(deleteWorkItemMsg{})
+	m = newModel.(Model)
+	if m.lastUndo == nil || m.lastUndo.kind != undoDelete {
+		t.Fatal("a successful delete should be undoable")
+	}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	m = newModel.(Model)
+	if cmd == nil {
+		t.Fatal("z should dispatch the undo")
+	}
+	msg := cmd()
+	if !strings.HasSuffix(restorePath, "/_apis/wit/recyclebin/2") {
+		t.Errorf("restore path = %q, want recycle bin restore of #2", restorePath)
+	}
+
+	newModel, _ = m.Update(msg)
+	m = newModel.(Model)
+	if m.lastUndo != nil {
+		t.Error("undo should be cleared after it succeeds")
+	}
+	if !strings.Contains(m.message, "Restored work item #2") {
+		t.Errorf("message = %q, want restore confirmation", m.message)
+	}
+}
+
+func TestUndoUnlinkReaddsLink(t *testing.T) {
+	var linkPath, linkBody string
+	client := mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			linkPath = r.URL.Path
+			body, _ := io.ReadAll(r.Body)
+			linkBody = string(body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	})
+
+	m := setupDetailModel()
+	m.SetClient(client)
+	m.relatedExpanded = true
+
+	newModel, _ := m.Update(removeLinkMsg{workItemID: 1, targetID: 9, isParent: true})
+	m = newModel.(Model)
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	m = newModel.(Model)
+	if cmd == nil {
+		t.Fatal("z should dispatch the undo in related mode")
+	}
+	msg := cmd()
+	if !strings.Contains(linkPath, "/workitems/1") {
+		t.Errorf("link path = %q, want PATCH on #1", linkPath)
+	}
+	if !strings.Contains(linkBody, "System.LinkTypes.Hierarchy-Reverse") || !strings.Contains(linkBody, "workItems/9") {
+		t.Errorf("link body = %q, want parent link to #9", linkBody)
+	}
+
+	newModel, _ = m.Update(msg)
+	m = newModel.(Model)
+	if m.lastUndo != nil || !strings.Contains(m.message, "Restored link to #9") {
+		t.Errorf("message = %q, want link restored", m.message)
+	}
+}
+
+func TestUndoNothing(t *testing.T) {
+	m := setupBoardModel()
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+	m = newModel.(Model)
+	if cmd != nil || m.message != "Nothing to undo" {
+		t.Errorf("z with nothing to undo: cmd = %v, message = %q", cmd, m.message)
+	}
+}
+
+func TestNewModelKeychainUnavailable(t *testing.T) {
+	original := credentialLoader
+	defer func() { credentialLoader = original }()
+	credentialLoader = func() (string, string, string, string, string, string, error) {
+		return "", "", "", "", "", "", errors.New("org.freedesktop.secrets was not provided by any .service files")
+	}
+
+	m := NewModel()
+	if m.keychainLoaded {
+		t.Error("credentials should not be marked loaded when the keychain fails")
+	}
+	if !strings.Contains(m.keychainMessage, "Keychain unavailable") {
+		t.Errorf("keychainMessage = %q, want unavailable message", m.keychainMessage)
+	}
+	if !strings.Contains(m.viewConfig(), "enter credentials manually") {
+		t.Error("config screen should tell the user to enter credentials manually")
+	}
+	if !m.keychainFailed || m.renderKeychainMessage() != errorStyle.Render("🔐 "+m.keychainMessage) {
+		t.Error("the unavailable keychain should be shown as an error")
+	}
+}
+
+func TestNewModelKeychainTimeout(t *testing.T) {
+	originalLoader, originalTimeout := credentialLoader, keychainTimeout
+	defer func() { credentialLoader, keychainTimeout = originalLoader, originalTimeout }()
+	release := make(chan struct{})
+	defer close(release)
+	credentialLoader = func() (string, string, string, string, string, string, error) {
+		<-release
+		return "", "", "", "", "", "", nil
+	}
+	keychainTimeout = 10 * time.Millisecond
+
+	m := NewModel()
+	if !strings.Contains(m.keychainMessage, "Keychain unavailable") {
+		t.Errorf("keychainMessage = %q, want unavailable message after a hang", m.keychainMessage)
+	}
+}
+
+func TestNewModelKeychainEmpty(t *testing.T) {
+	original := credentialLoader
+	defer func() { credentialLoader = original }()
+	credentialLoader = func() (string, string, string, string, string, string, error) {
+		return "", "", "", "", "", "", keyring.ErrNotFound
+	}
+
+	m := NewModel()
+	if m.keychainMessage != "" || m.keychainLoaded {
+		t.Errorf("keychainMessage = %q, want no message when nothing is stored", m.keychainMessage)
+	}
+}
+
+func TestNewModelKeychainLoaded(t *testing.T) {
+	original := credentialLoader
+	defer func() { credentialLoader = original }()
+	credentialLoader = func() (string, string, string, string, string, string, error) {
+		return "org", "proj", "team", "", "pat", "me@example.com", nil
+	}
+
+	m := NewModel()
+	if !m.keychainLoaded || m.configInputs[0].Value() != "org" || m.username != "me@example.com" {
+		t.Errorf("credentials should be loaded into the config inputs, got loaded=%v org=%q", m.keychainLoaded, m.configInputs[0].Value())
+	}
+}
+
+func TestConfigLogoutConfirmation(t *testing.T) {
+	m := NewModel()
+	m.view = ViewConfig
+	for i := range m.configInputs {
+		m.configInputs[i].SetValue("value")
+	}
+	m.keychainLoaded = true
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	m = newModel.(Model)
+	if !m.confirmingClearCredentials {
+		t.Fatal("ctrl+d should ask for confirmation before clearing credentials")
+	}
+	if !strings.Contains(m.View(), "Clear saved credentials?") {
+		t.Error("config view should show the logout confirmation")
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = newModel.(Model)
+	if m.confirmingClearCredentials || m.configInputs[0].Value() != "value" {
+		t.Error("n should cancel logout and keep the inputs")
+	}
+}
+
+func TestConfigLogoutClearsCredentials(t *testing.T) {
+	m := NewModel()
+	m.view = ViewConfig
+	for i := range m.configInputs {
+		m.configInputs[i].SetValue("value")
+	}
+	m.username = "me@example.com"
+	m.keychainLoaded = true
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	m = newModel.(Model)
+	if m.configInputs[0].Value() != "value" || !m.keychainLoaded {
+		t.Fatal("ctrl+d alone should not clear anything")
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = newModel.(Model)
+
+	for i, input := range m.configInputs {
+		if input.Value() != "" {
+			t.Errorf("configInputs[%d] = %q, want blank after logout", i, input.Value())
+		}
+	}
+	if m.keychainLoaded || m.username != "" || m.confirmingClearCredentials {
+		t.Error("logout should reset the loaded credential state")
+	}
+	if m.keychainMessage != "Credentials cleared from keychain" {
+		t.Errorf("keychainMessage = %q, want cleared message", m.keychainMessage)
+	}
+	if strings.Contains(m.View(), "Clear saved credentials?") {
+		t.Error("the confirmation should close after logout")
+	}
+	if _, found, err := loadStoredCredentials(); found || err != nil {
+		t.Errorf("loadStoredCredentials() found=%v err=%v, want nothing stored after logout", found, err)
+	}
+
+	// The file credential store forgets its encrypted PAT too
+	m.appConfig.CredentialStore = credentialStoreFile
+	m.fileCreds = &credentialsFile{}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	newModel, _ = newModel.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = newModel.(Model)
+	if m.fileCreds != nil || m.keychainMessage != "Credentials file cleared" {
+		t.Errorf("fileCreds = %v, keychainMessage = %q, want the file credentials cleared", m.fileCreds, m.keychainMessage)
+	}
+	if _, found, err := loadFileCredentials(); found || err != nil {
+		t.Errorf("loadFileCredentials() found=%v err=%v, want nothing stored after logout", found, err)
+	}
+}
+
+func TestConfigLogoutKeepsInputsWhenClearFails(t *testing.T) {
+	original := credentialClearer
+	defer func() { credentialClearer = original }()
+	credentialClearer = func() error { return errors.New("secret service is locked") }
+
+	m := NewModel()
+	m.view = ViewConfig
+	for i := range m.configInputs {
+		m.configInputs[i].SetValue("value")
+	}
+	m.keychainLoaded = true
+	m.keychainMessage = ""
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	newModel, _ = newModel.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = newModel.(Model)
+
+	if m.err == nil || !strings.Contains(m.err.Error(), "secret service is locked") {
+		t.Errorf("err = %v, want the clear failure", m.err)
+	}
+	if m.configInputs[0].Value() != "value" || !m.keychainLoaded || strings.Contains(m.keychainMessage, "cleared") {
+		t.Error("a failed clear should keep the credentials and not report them cleared")
+	}
+}
+
+func TestConfigWizardStepProgression(t *testing.T) {
+	m := NewModel()
+	m.wizardActive = true
+
+	// An empty field can't be skipped
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.configFocus != 0 || m.err == nil {
+		t.Fatalf("enter on an empty field should stay on step 0 with an error, got step %d err %v", m.configFocus, m.err)
+	}
+
+	m.configInputs[0].SetValue("myorg")
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.configFocus != 1 || m.err != nil {
+		t.Fatalf("enter should advance to step 1, got step %d err %v", m.configFocus, m.err)
+	}
+	if !strings.Contains(m.View(), "step 2 of 6") || !strings.Contains(m.View(), "Project") {
+		t.Error("wizard should show only the project step")
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.configFocus != 0 || !m.wizardActive {
+		t.Errorf("esc should go back to step 0, got step %d", m.configFocus)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.wizardActive {
+		t.Error("esc on the first step should leave the wizard")
+	}
+}
+
+func TestConfigWizardCompletesWithConnect(t *testing.T) {
+	m := NewModel()
+	m.wizardActive = true
+
+	values := []string{"myorg", "MyProject", "MyTeam", "MyProject\\MyTeam", "pat", "me@example.com"}
+	var cmd tea.Cmd
+	for _, v := range values {
+		for _, r := range v {
+			newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+			m = newModel.(Model)
+		}
+		var newModel tea.Model
+		newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = newModel.(Model)
+	}
+
+	if m.wizardActive {
+		t.Error("wizard should finish after the last step")
+	}
+	if !m.loading || m.client == nil || cmd == nil {
+		t.Fatal("completing the wizard should start connecting")
+	}
+	if m.client.Organization != "myorg" || m.client.Team != "MyTeam" || m.username != "me@example.com" {
+		t.Errorf("client built from wizard values, got org %q team %q user %q", m.client.Organization, m.client.Team, m.username)
+	}
+}
+
+func TestConfigAreaPathOptionalWithTeam(t *testing.T) {
+	// The wizard lets the Area Path be skipped once a Team is entered
+	m := NewModel()
+	m.wizardActive = true
+	var cmd tea.Cmd
+	for _, v := range []string{"myorg", "MyProject", "MyTeam", "", "pat", "me@example.com"} {
+		m.configInputs[m.configFocus].SetValue(v)
+		var newModel tea.Model
+		newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = newModel.(Model)
+		if m.err != nil {
+			t.Fatalf("step %d: unexpected error %v", m.configFocus, m.err)
+		}
+	}
+	if m.wizardActive || m.client == nil || cmd == nil {
+		t.Fatal("completing the wizard without an Area Path should start connecting")
+	}
+	if !m.client.UsesTeamAreas() {
+		t.Error("a Team without an Area Path should scope the board to the team's areas")
+	}
+
+	// Without a Team the Area Path is still required
+	m = NewModel()
+	m.wizardActive = true
+	m.configFocus = 3
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.configFocus != 3 || m.err == nil {
+		t.Errorf("an empty Area Path without a Team should be rejected, got step %d err %v", m.configFocus, m.err)
+	}
+
+	// The config form connects with the same fields
+	m = NewModel()
+	for i, v := range []string{"myorg", "MyProject", "", "", "pat", "me@example.com"} {
+		m.configInputs[i].SetValue(v)
+	}
+	if m.credentialsComplete() {
+		t.Error("the form should need an Area Path or a Team")
+	}
+	m.configInputs[2].SetValue("MyTeam")
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if !m.loading || m.client == nil || cmd == nil || !m.client.UsesTeamAreas() {
+		t.Error("enter on the form with a Team and no Area Path should connect using the team's areas")
+	}
+}
+
+func TestAddCommentUsesConfiguredMechanism(t *testing.T) {
+	tests := []struct {
+		mode, method, pathSuffix string
+	}{
+		{"", "POST", "/_apis/wit/workitems/1/comments"},
+		{"history", "PATCH", "/_apis/wit/workitems/1"},
+	}
+	for _, tt := range tests {
+		var method, path string
+		m := setupDetailModel()
+		m.appConfig.CommentMode = tt.mode
+		m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+			method, path = r.Method, r.URL.Path
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":1}`))
+		})
+
+		if msg := m.addComment(1, "Looks good")().(addCommentMsg); msg.err != nil {
+			t.Fatalf("mode %q: addComment failed: %v", tt.mode, msg.err)
+		}
+		if method != tt.method || !strings.HasSuffix(path, tt.pathSuffix) {
+			t.Errorf("mode %q: got %s %s, want %s ...%s", tt.mode, method, path, tt.method, tt.pathSuffix)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// notificationHistorySize is the number of recent notifications kept in the history
+const notificationHistorySize = 100
+
+// notificationEntry is a change recorded in the notification history
+type notificationEntry struct {
+	at    time.Time
+	id    int
+	title string
+	read  bool
+}
+
+// recordNotifications adds changed items to the notification history as unread,
+// dropping the oldest entries once the history is full
+func (m *Model) recordNotifications(items []azdo.WorkItem, at time.Time) {
+	for _, item := range items {
+		m.notifications = append(m.notifications, notificationEntry{at: at, id: item.ID, title: item.Fields.Title})
+	}
+	if len(m.notifications) > notificationHistorySize {
+		m.notifications = m.notifications[len(m.notifications)-notificationHistorySize:]
+	}
+}
+
+// unreadNotifications returns how many notifications haven't been marked read
+func (m Model) unreadNotifications() int {
+	n := 0
+	for _, entry := range m.notifications {
+		if !entry.read {
+			n++
+		}
+	}
+	return n
+}
+
+// markNotificationsRead marks every notification in the history as read
+func (m Model) markNotificationsRead() Model {
+	for i := range m.notifications {
+		m.notifications[i].read = true
+	}
+	return m
+}
+
+func (m Model) updateNotifications(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "alt+n":
+		m.showNotifications = false
+	case "up", "k":
+		if m.notificationCursor > 0 {
+			m.notificationCursor--
+		}
+	case "down", "j":
+		if m.notificationCursor < len(m.notifications)-1 {
+			m.notificationCursor++
+		}
+	case "enter":
+		// Open the selected item; the list is shown newest first. Only from the board,
+		// so unsaved edits in another view aren't thrown away
+		if m.view == ViewBoard && m.notificationCursor < len(m.notifications) && m.client != nil && !m.offline {
+			i := len(m.notifications) - 1 - m.notificationCursor
+			m.notifications[i].read = true
+			m.showNotifications = false
+			m.loading = true
+			return m, m.jumpToWorkItem(m.notifications[i].id)
+		}
+	case "r":
+		m = m.markNotificationsRead()
+	case "x":
+		m.notifications = nil
+		m.notificationCursor = 0
+	}
+	return m, nil
+}
+
+func (m Model) viewNotifications() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("🔔 Notifications (%d unread)", m.unreadNotifications())))
+	b.WriteString("\n\n")
+
+	if len(m.notifications) == 0 {
+		b.WriteString("No notifications this session.")
+		b.WriteString("\n")
+	}
+
+	// Keep the cursor in view on short terminals
+	start, end := 0, len(m.notifications)
+	if maxRows := m.height - 8; m.height > 0 && maxRows > 0 && end > maxRows {
+		start = max(m.notificationCursor-maxRows/2, 0)
+		if start+maxRows > end {
+			start = end - maxRows
+		}
+		end = start + maxRows
+	}
+
+	timeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	// Newest first, like the error log
+	for cursor := start; cursor < end; cursor++ {
+		entry := m.notifications[len(m.notifications)-1-cursor]
+		marker := "  "
+		if !entry.read {
+			marker = "● "
+		}
+		line := fmt.Sprintf("%s#%d %s", marker, entry.id, truncateWidth(entry.title, 80))
+		b.WriteString(timeStyle.Render(entry.at.Format("15:04:05")))
+		b.WriteString(" ")
+		if cursor == m.notificationCursor {
+			b.WriteString(selectedStyle.Render(line))
+		} else {
+			b.WriteString(normalStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	if m.view == ViewBoard {
+		b.WriteString(helpStyle.Render("↑↓: select • enter: open • r: mark all read • x: clear all • esc/q: close"))
+	} else {
+		b.WriteString(helpStyle.Render("↑↓: select • r: mark all read • x: clear all • esc/q: close • open items from the board"))
+	}
+
+	return boxStyle.Render(b.String())
+}
@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNotificationsAppendedToHistory(t *testing.T) {
+	original := notificationPlayer
+	notificationPlayer = func(string) {}
+	defer func() { notificationPlayer = original }()
+
+	m := setupBoardModel()
+	m.knownRevisions = make(map[int]int)
+	changed := func(id int, title string) notifyChangesMsg {
+		item := azdo.WorkItem{ID: id, Rev: 2}
+		item.Fields.Title = title
+		return notifyChangesMsg{changedItems: []azdo.WorkItem{item}}
+	}
+
+	newModel, _ := m.Update(changed(1, "First Item"))
+	m = newModel.(Model)
+	newModel, _ = m.Update(changed(2, "Second Item"))
+	m = newModel.(Model)
+	if len(m.notifications) != 2 {
+		t.Fatalf("notifications has %d entries, want 2", len(m.notifications))
+	}
+	if m.notifications[0].id != 1 || m.notifications[1].id != 2 || m.notifications[0].at.IsZero() {
+		t.Errorf("notifications = %+v, want both changes in order with timestamps", m.notifications)
+	}
+	if m.unreadNotifications() != 2 {
+		t.Errorf("unreadNotifications() = %d, want 2", m.unreadNotifications())
+	}
+
+	// A failed check records nothing
+	newModel, _ = m.Update(notifyChangesMsg{err: errors.New("connection reset")})
+	m = newModel.(Model)
+	if len(m.notifications) != 2 {
+		t.Errorf("notifications has %d entries after a failed check, want 2", len(m.notifications))
+	}
+}
+
+func TestNotificationHistorySize(t *testing.T) {
+	m := setupBoardModel()
+	items := make([]azdo.WorkItem, notificationHistorySize+5)
+	for i := range items {
+		items[i].ID = i
+	}
+	m.recordNotifications(items, time.Now())
+	if len(m.notifications) != notificationHistorySize {
+		t.Fatalf("notifications has %d entries, want %d", len(m.notifications), notificationHistorySize)
+	}
+	if m.notifications[0].id != 5 {
+		t.Errorf("oldest entry = #%d, want the oldest notifications dropped", m.notifications[0].id)
+	}
+}
+
+func TestNotificationOverlay(t *testing.T) {
+	m := setupBoardModel()
+	m.recordNotifications(m.workItems, time.Now())
+	if !strings.Contains(m.View(), "2 unread (alt+n: history)") {
+		t.Error("board should show the unread notification count")
+	}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}, Alt: true})
+	m = newModel.(Model)
+	if !m.showNotifications {
+		t.Fatal("alt+n should open the notification history")
+	}
+	view := m.View()
+	if !strings.Contains(view, "Notifications (2 unread)") || !strings.Contains(view, "#2 Second Item") {
+		t.Errorf("notification history should list recent changes, got %q", view)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = newModel.(Model)
+	if m.unreadNotifications() != 0 || len(m.notifications) != 2 {
+		t.Errorf("r should mark all read and keep the history, got %+v", m.notifications)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = newModel.(Model)
+	if len(m.notifications) != 0 {
+		t.Errorf("x should clear the history, got %d entries", len(m.notifications))
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.showNotifications || m.view != ViewBoard {
+		t.Error("esc should close the notification history and return to the board")
+	}
+	if strings.Contains(m.View(), "unread") {
+		t.Error("board shouldn't show an unread count once the history is empty")
+	}
+}
+
+func TestNotificationOverlayScrollsAndOpensFromBoardOnly(t *testing.T) {
+	m := setupBoardModel()
+	m.height = 20
+	var items []azdo.WorkItem
+	for id := 1; id <= 30; id++ {
+		items = append(items, azdo.WorkItem{ID: id, Fields: azdo.WorkItemFields{Title: fmt.Sprintf("Item %d", id)}})
+	}
+	m.recordNotifications(items, time.Now())
+	m.showNotifications = true
+
+	for range 29 {
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = newModel.(Model)
+	}
+	view := m.View()
+	if !strings.Contains(view, "#1 Item 1") || strings.Contains(view, "#30 Item 30") {
+		t.Errorf("notification history should scroll to keep the cursor in view, got %q", view)
+	}
+
+	// Opening an item from another view would discard its unsaved edits
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL.Path)
+	})
+	m.view = ViewDetail
+	m.selectedItem = &m.workItems[0]
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd != nil || !m.showNotifications || m.view != ViewDetail {
+		t.Error("enter should only open notifications from the board")
+	}
+	if strings.Contains(m.View(), "enter: open") {
+		t.Error("help shouldn't offer enter outside the board")
+	}
+
+	m.view = ViewBoard
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil {
+		t.Error("enter should open the selected notification from the board")
+	}
+}
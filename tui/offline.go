@@ -0,0 +1,224 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// offlineCacheDirName is the directory under the config dir that holds offline caches
+const offlineCacheDirName = "cache"
+
+// offlineReadOnlyMessage is shown when a write action is attempted while offline
+const offlineReadOnlyMessage = "Offline: cached data is read-only (R on the board to reconnect)"
+
+// offlineCache is the last fetched board and opened work items for one org/project,
+// saved to disk so they can be browsed when Azure DevOps can't be reached
+type offlineCache struct {
+	Organization string               `json:"organization"`
+	Project      string               `json:"project"`
+	SavedAt      time.Time            `json:"saved_at"`
+	WorkItems    []azdo.WorkItem      `json:"work_items"`
+	Details      map[int]cachedDetail `json:"details,omitempty"`
+}
+
+// cachedDetail is what the detail view loaded for one work item
+type cachedDetail struct {
+	Comments []azdo.Comment  `json:"comments,omitempty"`
+	Parent   *azdo.WorkItem  `json:"parent,omitempty"`
+	Children []azdo.WorkItem `json:"children,omitempty"`
+	CachedAt time.Time       `json:"cached_at"`
+}
+
+// maxCachedDetails is how many opened work items the offline cache keeps;
+// the least recently cached are dropped first
+const maxCachedDetails = 100
+
+// offlineBoardKeys are the board keys that only read already loaded data
+var offlineBoardKeys = map[string]bool{
+	"up": true, "k": true, "down": true, "j": true,
+	"pgup": true, "pgdown": true, "end": true,
+	"e": true, "enter": true, "R": true, "u": true, "[": true, "]": true, "q": true, "f": true,
+}
+
+// offlineDetailKeys are the detail keys that only read already loaded data
+var offlineDetailKeys = map[string]bool{
+	"up": true, "down": true, "tab": true, "shift+tab": true,
+	"pgup": true, "pgdown": true, "ctrl+k": true,
+	"ctrl+e": true, "ctrl+r": true, "ctrl+y": true, "alt+y": true, "alt+c": true, "alt+h": true,
+	"alt+1": true, "alt+2": true, "alt+3": true, "alt+4": true, "alt+5": true,
+}
+
+// offlineCachePath returns the cache file for an org/project
+func offlineCachePath(org, project string) (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	name := cacheFileComponent(org) + "_" + cacheFileComponent(project) + ".json"
+	return filepath.Join(configDir, offlineCacheDirName, name), nil
+}
+
+// cacheFileComponent makes a name safe to use in a file name
+func cacheFileComponent(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// writeOfflineCache saves a cache to path, readable only by the user
+func writeOfflineCache(path string, c offlineCache) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// readOfflineCache loads a cache from path
+func readOfflineCache(path string) (offlineCache, error) {
+	var c offlineCache
+	data, err := os.ReadFile(path) // #nosec G304 -- path is built from the config dir
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// loadOfflineCache loads the saved cache for an org/project, or an empty one if there is none
+func loadOfflineCache(org, project string) offlineCache {
+	empty := offlineCache{Organization: org, Project: project}
+	if isRunningInDocker() {
+		return empty
+	}
+	path, err := offlineCachePath(org, project)
+	if err != nil {
+		return empty
+	}
+	c, err := readOfflineCache(path)
+	if err != nil || c.Organization != org || c.Project != project {
+		return empty
+	}
+	return c
+}
+
+// saveOfflineCache writes the in-memory cache to disk in the background.
+// The cache is best-effort, so write failures are ignored.
+func (m Model) saveOfflineCache() tea.Cmd {
+	if isRunningInDocker() || m.client == nil {
+		return nil
+	}
+	c := m.cache
+	return func() tea.Msg {
+		if path, err := offlineCachePath(c.Organization, c.Project); err == nil {
+			_ = writeOfflineCache(path, c)
+		}
+		return nil
+	}
+}
+
+// cacheWorkItems records the loaded board items in the offline cache
+func (m Model) cacheWorkItems(items []azdo.WorkItem) (Model, tea.Cmd) {
+	// Copy the items, since the board updates its own slice in place while the cache is saved
+	m.cache.WorkItems = slices.Clone(items)
+	m.cache.SavedAt = time.Now()
+	return m, m.saveOfflineCache()
+}
+
+// cacheDetail updates the cached detail of a work item. The map and the cached
+// slices are copied first so a background save never sees them change.
+func (m Model) cacheDetail(id int, update func(*cachedDetail)) (Model, tea.Cmd) {
+	details := maps.Clone(m.cache.Details)
+	if details == nil {
+		details = make(map[int]cachedDetail)
+	}
+	detail := details[id]
+	update(&detail)
+	detail.Comments = slices.Clone(detail.Comments)
+	detail.Children = slices.Clone(detail.Children)
+	if detail.Parent != nil {
+		parent := *detail.Parent
+		detail.Parent = &parent
+	}
+	detail.CachedAt = time.Now()
+	details[id] = detail
+	for len(details) > maxCachedDetails {
+		delete(details, oldestCachedDetail(details))
+	}
+	m.cache.Details = details
+	return m, m.saveOfflineCache()
+}
+
+// oldestCachedDetail returns the ID of the least recently cached detail
+func oldestCachedDetail(details map[int]cachedDetail) int {
+	oldest, found := 0, false
+	for id, detail := range details {
+		if !found || detail.CachedAt.Before(details[oldest].CachedAt) {
+			oldest, found = id, true
+		}
+	}
+	return oldest
+}
+
+// isNetworkError reports whether err means Azure DevOps couldn't be reached at all,
+// as opposed to an error response from the server
+func isNetworkError(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// enterOffline shows the cached board read-only, if there is one
+func (m Model) enterOffline() Model {
+	if len(m.cache.WorkItems) == 0 {
+		return m
+	}
+	m.offline = true
+	m.view = ViewBoard
+	m.workItems = m.cache.WorkItems
+	m.lastFetched = m.cache.SavedAt
+	m.apiPage = 0
+	m.hasMoreData = false
+	m.selectedIDs = nil
+	if m.cursor >= len(m.workItems) {
+		m.cursor = 0
+	}
+	return m
+}
+
+// loadCachedDetail fills the detail view's comments and related items from the cache
+func (m Model) loadCachedDetail(id int) Model {
+	detail := m.cache.Details[id]
+	m.comments = detail.Comments
+	m.parentItem = detail.Parent
+	m.childItems = detail.Children
+	return m
+}
+
+// offlineBanner describes the cached data being shown while offline
+func (m Model) offlineBanner() string {
+	text := "⚠ OFFLINE - read-only cached data"
+	if !m.cache.SavedAt.IsZero() {
+		text += fmt.Sprintf(" from %s", updatedAgo(m.cache.SavedAt))
+	}
+	return staleStyle.Render(text)
+}
@@ -0,0 +1,244 @@
+package tui
+
+import (
+	"errors"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testOfflineCache() offlineCache {
+	return offlineCache{
+		Organization: "testorg",
+		Project:      "testproject",
+		SavedAt:      time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+		WorkItems: []azdo.WorkItem{
+			{ID: 1, Fields: azdo.WorkItemFields{Title: "Cached One"}},
+			{ID: 2, Fields: azdo.WorkItemFields{Title: "Cached Two"}},
+		},
+		Details: map[int]cachedDetail{
+			1: {
+				Comments: []azdo.Comment{{ID: 9, Text: "cached comment"}},
+				Parent:   &azdo.WorkItem{ID: 10},
+				Children: []azdo.WorkItem{{ID: 11}},
+			},
+		},
+	}
+}
+
+func offlineModel() Model {
+	m := setupBoardModel()
+	m.cache = testOfflineCache()
+	m = m.enterOffline()
+	return m
+}
+
+func TestOfflineCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "testorg_testproject.json")
+	want := testOfflineCache()
+
+	if err := writeOfflineCache(path, want); err != nil {
+		t.Fatalf("writeOfflineCache failed: %v", err)
+	}
+	got, err := readOfflineCache(path)
+	if err != nil {
+		t.Fatalf("readOfflineCache failed: %v", err)
+	}
+
+	if got.Organization != want.Organization || got.Project != want.Project || !got.SavedAt.Equal(want.SavedAt) {
+		t.Errorf("Header mismatch: got %+v", got)
+	}
+	if len(got.WorkItems) != 2 || got.WorkItems[1].Fields.Title != "Cached Two" {
+		t.Errorf("WorkItems mismatch: got %+v", got.WorkItems)
+	}
+	detail := got.Details[1]
+	if len(detail.Comments) != 1 || detail.Comments[0].Text != "cached comment" {
+		t.Errorf("Comments mismatch: got %+v", detail.Comments)
+	}
+	if detail.Parent == nil || detail.Parent.ID != 10 || len(detail.Children) != 1 || detail.Children[0].ID != 11 {
+		t.Errorf("Related items mismatch: got %+v", detail)
+	}
+}
+
+func TestReadOfflineCacheMissing(t *testing.T) {
+	if _, err := readOfflineCache(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error reading a missing cache")
+	}
+}
+
+func TestCacheFileComponent(t *testing.T) {
+	if got := cacheFileComponent("my org/Proj 1"); got != "my_org_Proj_1" {
+		t.Errorf("cacheFileComponent() = %q", got)
+	}
+}
+
+func TestConnectNetworkErrorEntersOffline(t *testing.T) {
+	m := NewModel()
+	m.cache = testOfflineCache()
+	netErr := &url.Error{Op: "Get", URL: "https://dev.azure.com", Err: errors.New("no such host")}
+
+	newModel, _ := m.Update(connectMsg{err: netErr})
+	m = newModel.(Model)
+	if !m.offline || m.view != ViewBoard {
+		t.Fatalf("Expected the cached board, offline=%v view=%v", m.offline, m.view)
+	}
+	if len(m.workItems) != 2 || m.workItems[0].Fields.Title != "Cached One" {
+		t.Errorf("Expected cached items, got %+v", m.workItems)
+	}
+	if view := m.View(); !contains(view, "OFFLINE") {
+		t.Errorf("Expected an offline banner, got:\n%s", view)
+	}
+}
+
+func TestConnectAuthErrorStaysOnConfig(t *testing.T) {
+	m := NewModel()
+	m.cache = testOfflineCache()
+
+	newModel, _ := m.Update(connectMsg{err: errors.New("connection failed (401): unauthorized")})
+	m = newModel.(Model)
+	if m.offline || m.view != ViewConfig {
+		t.Errorf("Expected a server error not to go offline, offline=%v view=%v", m.offline, m.view)
+	}
+}
+
+func TestOfflineWithoutCacheStaysOnConfig(t *testing.T) {
+	m := NewModel()
+	netErr := &url.Error{Op: "Get", URL: "https://dev.azure.com", Err: errors.New("no such host")}
+
+	newModel, _ := m.Update(connectMsg{err: netErr})
+	m = newModel.(Model)
+	if m.offline || m.view != ViewConfig {
+		t.Errorf("Expected no offline mode without a cache, offline=%v view=%v", m.offline, m.view)
+	}
+}
+
+func TestOfflineBoardDisablesWrites(t *testing.T) {
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("d")},
+		{Type: tea.KeyRunes, Runes: []rune("c")},
+		{Type: tea.KeyRunes, Runes: []rune("m")},
+		{Type: tea.KeyRunes, Runes: []rune("r")},
+		{Type: tea.KeySpace},
+	}
+	for _, key := range keys {
+		m := offlineModel()
+		m.username = "me@example.com"
+		newModel, cmd := m.Update(key)
+		m = newModel.(Model)
+		if cmd != nil || m.loading || m.deletingWorkItem || m.view != ViewBoard || len(m.selectedIDs) != 0 {
+			t.Errorf("Expected %q to be disabled offline", key.String())
+		}
+		if m.message != offlineReadOnlyMessage {
+			t.Errorf("%q: message = %q", key.String(), m.message)
+		}
+	}
+}
+
+func TestOfflineDetailFromCache(t *testing.T) {
+	m := offlineModel()
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd != nil {
+		t.Error("Expected the detail view to open without fetching")
+	}
+	if m.view != ViewDetail || len(m.comments) != 1 || m.parentItem == nil || len(m.childItems) != 1 {
+		t.Fatalf("Expected cached comments and related items, got view=%v comments=%v", m.view, m.comments)
+	}
+
+	// Saving and typing are disabled
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = newModel.(Model)
+	if cmd != nil || m.loading || m.message != offlineReadOnlyMessage {
+		t.Errorf("Expected ctrl+s to be disabled offline, message=%q", m.message)
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = newModel.(Model)
+	if m.detailInputs[0].Value() != "Cached One" {
+		t.Errorf("Expected the title to be unchanged, got %q", m.detailInputs[0].Value())
+	}
+
+	// Browsing still works
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	m = newModel.(Model)
+	if !m.commentsExpanded {
+		t.Error("Expected comments to expand offline")
+	}
+}
+
+func TestFetchesUpdateOfflineCache(t *testing.T) {
+	m := setupBoardModel()
+	items := []azdo.WorkItem{{ID: 5, Fields: azdo.WorkItemFields{Title: "Fresh"}}}
+
+	newModel, _ := m.Update(workItemsMsg{items: items})
+	m = newModel.(Model)
+	if len(m.cache.WorkItems) != 1 || m.cache.WorkItems[0].ID != 5 || m.cache.SavedAt.IsZero() {
+		t.Errorf("Expected the board to be cached, got %+v", m.cache)
+	}
+
+	newModel, _ = m.Update(commentsMsg{workItemID: 5, comments: []azdo.Comment{{ID: 1}}})
+	m = newModel.(Model)
+	newModel, _ = m.Update(relatedItemsMsg{workItemID: 5, parent: &azdo.WorkItem{ID: 4}})
+	m = newModel.(Model)
+	detail := m.cache.Details[5]
+	if len(detail.Comments) != 1 || detail.Parent == nil || detail.Parent.ID != 4 {
+		t.Errorf("Expected the detail to be cached, got %+v", detail)
+	}
+}
+
+func TestReconnectLeavesOffline(t *testing.T) {
+	m := offlineModel()
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	m = newModel.(Model)
+	if cmd == nil {
+		t.Fatal("Expected R to reconnect while offline")
+	}
+	newModel, _ = m.Update(connectMsg{})
+	m = newModel.(Model)
+	if m.offline {
+		t.Error("Expected a successful reconnect to leave offline mode")
+	}
+}
+
+func TestCacheWorkItemsCopies(t *testing.T) {
+	m := setupBoardModel()
+	m, _ = m.cacheWorkItems(m.workItems)
+
+	// The board updates its items in place, e.g. after a bulk tag
+	m.workItems[0].Fields.Tags = "changed"
+	if m.cache.WorkItems[0].Fields.Tags == "changed" {
+		t.Error("Expected the cache to keep its own copy of the items")
+	}
+}
+
+func TestCacheDetailCapsAndCopies(t *testing.T) {
+	m := setupBoardModel()
+	m.cache.Details = make(map[int]cachedDetail)
+	start := time.Now().Add(-time.Hour)
+	for id := 1; id <= maxCachedDetails; id++ {
+		m.cache.Details[id] = cachedDetail{CachedAt: start.Add(time.Duration(id) * time.Second)}
+	}
+
+	comments := []azdo.Comment{{ID: 1, Text: "original"}}
+	m, _ = m.cacheDetail(500, func(d *cachedDetail) { d.Comments = comments })
+	if len(m.cache.Details) != maxCachedDetails {
+		t.Errorf("Expected the cache to stay at %d details, got %d", maxCachedDetails, len(m.cache.Details))
+	}
+	if _, ok := m.cache.Details[1]; ok {
+		t.Error("Expected the least recently cached detail to be dropped")
+	}
+	if _, ok := m.cache.Details[500]; !ok {
+		t.Error("Expected the new detail to be cached")
+	}
+
+	comments[0].Text = "changed"
+	if got := m.cache.Details[500].Comments[0].Text; got != "original" {
+		t.Errorf("Expected the cached comments to be a copy, got %q", got)
+	}
+}
@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hierarchyNode is a work item in the outline tree together with its children
+type hierarchyNode struct {
+	item     azdo.WorkItem
+	children []*hierarchyNode
+}
+
+// buildHierarchy arranges items into trees using their parent links. Items whose parent
+// isn't among items (orphans) become roots. Roots and siblings keep the order of items,
+// and a parent cycle is broken at the first item of the cycle.
+func buildHierarchy(items []azdo.WorkItem) []*hierarchyNode {
+	byID := make(map[int]azdo.WorkItem, len(items))
+	for _, wi := range items {
+		byID[wi.ID] = wi
+	}
+	childIDs := make(map[int][]int)
+	var rootIDs []int
+	for _, wi := range items {
+		pid := wi.ParentID()
+		if _, ok := byID[pid]; ok && pid != wi.ID {
+			childIDs[pid] = append(childIDs[pid], wi.ID)
+		} else {
+			rootIDs = append(rootIDs, wi.ID)
+		}
+	}
+
+	visited := make(map[int]bool, len(items))
+	var build func(id int) *hierarchyNode
+	build = func(id int) *hierarchyNode {
+		visited[id] = true
+		node := &hierarchyNode{item: byID[id]}
+		for _, childID := range childIDs[id] {
+			if !visited[childID] {
+				node.children = append(node.children, build(childID))
+			}
+		}
+		return node
+	}
+
+	var roots []*hierarchyNode
+	for _, id := range rootIDs {
+		roots = append(roots, build(id))
+	}
+	// Items only reachable through a cycle have no root; promote them in order
+	for _, wi := range items {
+		if !visited[wi.ID] {
+			roots = append(roots, build(wi.ID))
+		}
+	}
+	return roots
+}
+
+// outlineRow is a visible line of the outline
+type outlineRow struct {
+	item        azdo.WorkItem
+	depth       int
+	hasChildren bool
+}
+
+// flattenHierarchy lists the visible outline rows, skipping the children of collapsed nodes
+func flattenHierarchy(roots []*hierarchyNode, collapsed map[int]bool) []outlineRow {
+	var rows []outlineRow
+	var walk func(nodes []*hierarchyNode, depth int)
+	walk = func(nodes []*hierarchyNode, depth int) {
+		for _, n := range nodes {
+			rows = append(rows, outlineRow{item: n.item, depth: depth, hasChildren: len(n.children) > 0})
+			if !collapsed[n.item.ID] {
+				walk(n.children, depth+1)
+			}
+		}
+	}
+	walk(roots, 0)
+	return rows
+}
+
+// outlineRows returns the visible outline rows for the loaded work items
+func (m Model) outlineRows() []outlineRow {
+	return flattenHierarchy(buildHierarchy(m.workItems), m.outlineCollapsed)
+}
+
+func (m Model) updateOutline(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	rows := m.outlineRows()
+	switch keyMsg.String() {
+	case "esc", "q", "t":
+		m.view = ViewBoard
+		return m, nil
+	case "up", "k":
+		if m.outlineCursor > 0 {
+			m.outlineCursor--
+		}
+	case "down", "j":
+		if m.outlineCursor < len(rows)-1 {
+			m.outlineCursor++
+		}
+	case "left", "h", "right", "l", " ":
+		if m.outlineCursor >= len(rows) || !rows[m.outlineCursor].hasChildren {
+			return m, nil
+		}
+		id := rows[m.outlineCursor].item.ID
+		if m.outlineCollapsed == nil {
+			m.outlineCollapsed = make(map[int]bool)
+		}
+		switch keyMsg.String() {
+		case "left", "h":
+			m.outlineCollapsed[id] = true
+		case "right", "l":
+			delete(m.outlineCollapsed, id)
+		default:
+			if m.outlineCollapsed[id] {
+				delete(m.outlineCollapsed, id)
+			} else {
+				m.outlineCollapsed[id] = true
+			}
+		}
+	case "e", "enter":
+		if m.outlineCursor >= len(rows) {
+			return m, nil
+		}
+		wi := rows[m.outlineCursor].item
+		m.view = ViewDetail
+		newModel, cmd := m.navigateToWorkItem(&wi)
+		updated := newModel.(Model)
+		return updated, tea.Batch(cmd, updated.updateDetailFocus())
+	}
+	return m, nil
+}
+
+func (m Model) viewOutline() string {
+	var b strings.Builder
+
+	header := titleStyle.Render(fmt.Sprintf("🌳 Outline - %s", m.connectionLabel()))
+	b.WriteString(header)
+	b.WriteString("\n\n")
+
+	rows := m.outlineRows()
+	if len(rows) == 0 {
+		b.WriteString("No work items found.")
+		b.WriteString("\n")
+	}
+
+	// Keep the cursor in view on short terminals
+	start, end := 0, len(rows)
+	if maxRows := m.height - 8; m.height > 0 && maxRows > 0 && len(rows) > maxRows {
+		start = m.outlineCursor - maxRows/2
+		if start < 0 {
+			start = 0
+		}
+		if start+maxRows > len(rows) {
+			start = len(rows) - maxRows
+		}
+		end = start + maxRows
+	}
+
+	for i := start; i < end; i++ {
+		row := rows[i]
+		marker := "  "
+		if row.hasChildren {
+			marker = "▾ "
+			if m.outlineCollapsed[row.item.ID] {
+				marker = "▸ "
+			}
+		}
+		line := fmt.Sprintf("%s%s#%d [%s] %s (%s)",
+			strings.Repeat("  ", row.depth), marker, row.item.ID,
+			row.item.Fields.WorkItemType, row.item.Fields.Title, row.item.Fields.State)
+		if m.width > 0 {
+			line = truncateWidth(line, m.width-normalStyle.GetHorizontalFrameSize()-2)
+		}
+		if i == m.outlineCursor {
+			b.WriteString(selectedStyle.Render(line))
+		} else {
+			b.WriteString(normalStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpStyle.Render("↑/k ↓/j: navigate • ←/h: collapse • →/l: expand • space: toggle • enter: open • esc/t: board"))
+
+	return b.String()
+}
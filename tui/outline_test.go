@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func hierarchyItem(id, parentID int, title string) azdo.WorkItem {
+	wi := azdo.WorkItem{ID: id, Fields: azdo.WorkItemFields{Title: title}}
+	if parentID != 0 {
+		wi.Relations = []azdo.WorkItemRelation{childOf(parentID)}
+	}
+	return wi
+}
+
+// outlineIDs returns each row as "depth:id" for compact comparisons
+func outlineIDs(rows []outlineRow) []string {
+	ids := make([]string, len(rows))
+	for i, r := range rows {
+		ids[i] = strings.Repeat(">", r.depth) + "#" + strconv.Itoa(r.item.ID)
+	}
+	return ids
+}
+
+func TestBuildHierarchy(t *testing.T) {
+	items := []azdo.WorkItem{
+		hierarchyItem(3, 2, "Story"),
+		hierarchyItem(1, 0, "Epic"),
+		hierarchyItem(2, 1, "Feature"),
+		hierarchyItem(4, 99, "Orphan task"), // parent not loaded
+		hierarchyItem(5, 2, "Second story"),
+		hierarchyItem(6, 0, "Standalone bug"),
+	}
+
+	roots := buildHierarchy(items)
+	if len(roots) != 3 {
+		t.Fatalf("got %d roots, want epic, orphan, and standalone item", len(roots))
+	}
+	got := strings.Join(outlineIDs(flattenHierarchy(roots, nil)), " ")
+	want := "#1 >#2 >>#3 >>#5 #4 #6"
+	if got != want {
+		t.Errorf("outline = %q, want %q", got, want)
+	}
+}
+
+func TestBuildHierarchyCycle(t *testing.T) {
+	items := []azdo.WorkItem{
+		hierarchyItem(1, 2, "A"),
+		hierarchyItem(2, 1, "B"),
+	}
+	got := strings.Join(outlineIDs(flattenHierarchy(buildHierarchy(items), nil)), " ")
+	if got != "#1 >#2" {
+		t.Errorf("outline = %q, want the cycle broken at the first item", got)
+	}
+}
+
+func TestFlattenHierarchyCollapsed(t *testing.T) {
+	items := []azdo.WorkItem{
+		hierarchyItem(1, 0, "Epic"),
+		hierarchyItem(2, 1, "Feature"),
+		hierarchyItem(3, 2, "Story"),
+	}
+	got := strings.Join(outlineIDs(flattenHierarchy(buildHierarchy(items), map[int]bool{2: true})), " ")
+	if got != "#1 >#2" {
+		t.Errorf("outline = %q, want the collapsed node's children hidden", got)
+	}
+}
+
+func TestBoardOutlineView(t *testing.T) {
+	m := setupBoardModel()
+	m.workItems[1].Relations = []azdo.WorkItemRelation{childOf(1)}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	m = newModel.(Model)
+	if m.view != ViewOutline {
+		t.Fatalf("t should open the outline, got view %v", m.view)
+	}
+	view := m.View()
+	if !strings.Contains(view, "▾ #1") || !strings.Contains(view, "    #2") {
+		t.Errorf("outline should indent the child under its parent, got %q", view)
+	}
+
+	// Collapse the parent
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m = newModel.(Model)
+	if strings.Contains(m.View(), "Second Item") || !strings.Contains(m.View(), "▸ #1") {
+		t.Error("collapsing should hide the child")
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m = newModel.(Model)
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = newModel.(Model)
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.view != ViewDetail || m.selectedItem == nil || m.selectedItem.ID != 2 || cmd == nil {
+		t.Error("enter should open the selected outline item")
+	}
+}
@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func (m Model) updateRecycleBin(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			m.view = ViewBoard
+			m.err = nil
+			return m, nil
+		case "up", "k":
+			if m.recycleBinCursor > 0 {
+				m.recycleBinCursor--
+			}
+			return m, nil
+		case "down", "j":
+			if m.recycleBinCursor < len(m.recycleBin)-1 {
+				m.recycleBinCursor++
+			}
+			return m, nil
+		case "r":
+			// Refresh the recycle bin listing
+			m.loading = true
+			m.err = nil
+			return m, m.fetchRecycleBin()
+		case "enter", "u":
+			// Restore the selected work item
+			if m.loading || len(m.recycleBin) == 0 || m.recycleBinCursor >= len(m.recycleBin) {
+				return m, nil
+			}
+			m.loading = true
+			m.err = nil
+			m.message = ""
+			id := m.recycleBin[m.recycleBinCursor].ID
+			return m.confirmWrite(fmt.Sprintf("Restore #%d?", id), m.restoreWorkItem(id))
+		}
+	}
+	return m, nil
+}
+
+func (m Model) viewRecycleBin() string {
+	var b strings.Builder
+
+	header := titleStyle.Render(fmt.Sprintf("🗑  Recycle Bin - %s", m.connectionLabel()))
+	b.WriteString(header)
+	b.WriteString("\n\n")
+
+	if m.loading && len(m.recycleBin) == 0 {
+		b.WriteString("Loading recycle bin...")
+		b.WriteString("\n")
+	} else if len(m.recycleBin) == 0 && m.err == nil {
+		b.WriteString("The recycle bin is empty.")
+		b.WriteString("\n")
+	} else {
+		colID := lipgloss.NewStyle().Width(10).Align(lipgloss.Left).MarginRight(2)
+		colType := lipgloss.NewStyle().Width(12).Align(lipgloss.Left)
+		colTitle := lipgloss.NewStyle().Width(40).Align(lipgloss.Left)
+		colDeletedBy := lipgloss.NewStyle().Width(25).Align(lipgloss.Left)
+		colDeleted := lipgloss.NewStyle().Width(14).Align(lipgloss.Left)
+
+		headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+		b.WriteString(lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			colID.Inherit(headerStyle).Render("ID"),
+			colType.Inherit(headerStyle).Render("Type"),
+			colTitle.Inherit(headerStyle).Render("Title"),
+			colDeletedBy.Inherit(headerStyle).Render("Deleted By"),
+			colDeleted.Inherit(headerStyle).Render("Deleted"),
+		))
+		b.WriteString("\n")
+		b.WriteString(strings.Repeat("─", 103))
+		b.WriteString("\n")
+
+		for i, item := range m.recycleBin {
+			row := lipgloss.JoinHorizontal(
+				lipgloss.Top,
+				colID.Render(fmt.Sprintf("#%d", item.ID)),
+				colType.Render(truncateWidth(item.Type, 11)),
+				colTitle.Render(truncateWidth(item.Name, 39)),
+				colDeletedBy.Render(truncateWidth(item.DeletedBy, 24)),
+				colDeleted.Render(relativeTime(item.DeletedDate)),
+			)
+			if i == m.recycleBinCursor {
+				b.WriteString(selectedStyle.Render(row))
+			} else {
+				b.WriteString(normalStyle.Render(row))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if m.err != nil {
+		b.WriteString("\n")
+		b.WriteString(m.renderError())
+	}
+
+	if m.message != "" {
+		b.WriteString("\n")
+		b.WriteString(successStyle.Render(m.message))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("↑/k ↓/j: navigate • enter/u: restore • r: refresh • esc: back"))
+
+	return b.String()
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBoardOpensRecycleBin(t *testing.T) {
+	m := setupBoardModel()
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	m = newModel.(Model)
+	if m.view != ViewRecycleBin {
+		t.Fatalf("view = %v, want ViewRecycleBin", m.view)
+	}
+	if cmd == nil || !m.loading {
+		t.Error("opening the recycle bin should fetch its contents")
+	}
+
+	newModel, _ = m.Update(recycleBinMsg{items: []azdo.DeletedWorkItem{
+		{ID: 5, Name: "Old bug", Type: "Bug", DeletedBy: "Jane Doe"},
+		{ID: 6, Name: "Old task", Type: "Task", DeletedBy: "Jane Doe"},
+	}})
+	m = newModel.(Model)
+	view := m.viewRecycleBin()
+	if !strings.Contains(view, "Old bug") || !strings.Contains(view, "#6") {
+		t.Errorf("recycle bin should list deleted items, got:\n%s", view)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = newModel.(Model)
+	if m.recycleBinCursor != 1 {
+		t.Errorf("recycleBinCursor = %d, want 1", m.recycleBinCursor)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.view != ViewBoard {
+		t.Errorf("view = %v, want ViewBoard after esc", m.view)
+	}
+}
+
+func TestRecycleBinRestore(t *testing.T) {
+	var restored string
+	m := setupBoardModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			restored = r.URL.Path
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	})
+	m.view = ViewRecycleBin
+	m.recycleBin = []azdo.DeletedWorkItem{{ID: 5, Name: "Old bug"}, {ID: 6, Name: "Old task"}}
+	m.recycleBinCursor = 1
+	m.lastUndo = &undoAction{kind: undoDelete, workItemID: 6}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd == nil || !m.loading {
+		t.Fatal("enter should restore the selected item")
+	}
+
+	newModel, cmd = m.Update(cmd())
+	m = newModel.(Model)
+	if !strings.HasSuffix(restored, "/_apis/wit/recyclebin/6") {
+		t.Errorf("restore request path = %q, want recycle bin item 6", restored)
+	}
+	if m.err != nil || !strings.Contains(m.message, "Restored work item #6") {
+		t.Errorf("message = %q, err = %v, want restore confirmation", m.message, m.err)
+	}
+	if m.lastUndo != nil {
+		t.Error("restoring the last deleted item should clear its undo")
+	}
+	if cmd == nil {
+		t.Error("restore should refresh the recycle bin and work items")
+	}
+}
+
+func TestRecycleBinRestoreNotFound(t *testing.T) {
+	m := setupBoardModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("not found"))
+	})
+	m.view = ViewRecycleBin
+	m.recycleBin = []azdo.DeletedWorkItem{{ID: 5, Name: "Old bug"}}
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	if m.loading {
+		t.Error("loading should be cleared after a failed restore")
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "404") {
+		t.Errorf("err = %v, want 404 error", m.err)
+	}
+	if !strings.Contains(m.viewRecycleBin(), "Error") {
+		t.Error("recycle bin view should show the error")
+	}
+}
@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// saveBoardQuery saves the query behind the current board view to My Queries
+func (m Model) saveBoardQuery(name string) tea.Cmd {
+	wiql := m.client.SavedQueryWIQL(m.workItemFilter())
+	return func() tea.Msg {
+		query, err := m.client.CreateSavedQuery(name, wiql)
+		return savedQueryMsg{query: query, err: err}
+	}
+}
+
+// updateSaveQueryPrompt handles key presses while naming the board query to save
+func (m Model) updateSaveQueryPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.savingQuery = false
+		m.saveQueryInput = ""
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(m.saveQueryInput)
+		if name == "" {
+			return m, nil
+		}
+		m.savingQuery = false
+		m.saveQueryInput = ""
+		m.loading = true
+		return m.confirmWrite(fmt.Sprintf("Save query %q to My Queries?", name), m.saveBoardQuery(name))
+	case "backspace":
+		if len(m.saveQueryInput) > 0 {
+			runes := []rune(m.saveQueryInput)
+			m.saveQueryInput = string(runes[:len(runes)-1])
+		}
+		return m, nil
+	case " ":
+		m.saveQueryInput += " "
+		return m, nil
+	}
+	if msg.Type == tea.KeyRunes {
+		m.saveQueryInput += string(msg.Runes)
+	}
+	return m, nil
+}
@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// searchMatch returns which field of wi matches the search text: "ID" when the text is
+// its ID (optionally written as #123), otherwise "title" or "tags" when either contains
+// the text (case-insensitively). It returns "" when nothing matches.
+func searchMatch(wi azdo.WorkItem, text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	if id, err := strconv.Atoi(strings.TrimPrefix(text, "#")); err == nil && id == wi.ID {
+		return "ID"
+	}
+	lower := strings.ToLower(text)
+	if strings.Contains(strings.ToLower(wi.Fields.Title), lower) {
+		return "title"
+	}
+	if strings.Contains(strings.ToLower(wi.Fields.Tags), lower) {
+		return "tags"
+	}
+	return ""
+}
+
+// countSearchMatches returns how many of items match the search text
+func countSearchMatches(items []azdo.WorkItem, text string) int {
+	n := 0
+	for _, wi := range items {
+		if searchMatch(wi, text) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// searchTitle returns the title cell for wi, labelled with the field that matched the board search
+func (m Model) searchTitle(wi azdo.WorkItem) string {
+	if m.searchText == "" {
+		return wi.Fields.Title
+	}
+	if field := searchMatch(wi, m.searchText); field != "" {
+		return "[" + field + "] " + wi.Fields.Title
+	}
+	return wi.Fields.Title
+}
+
+// applySearch limits the board to items matching text and refetches it; empty text clears the search
+func (m Model) applySearch(text string) (Model, tea.Cmd) {
+	m.searchText = strings.TrimSpace(text)
+	m.loading = true
+	m.cursor = 0
+	return m, m.fetchWorkItems()
+}
+
+// updateSearchPrompt handles key presses while entering the board search
+func (m Model) updateSearchPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.searching = false
+		m.searchInput = ""
+		return m, nil
+	case "enter":
+		text := m.searchInput
+		m.searching = false
+		m.searchInput = ""
+		if strings.TrimSpace(text) == m.searchText {
+			return m, nil
+		}
+		return m.applySearch(text)
+	case "backspace":
+		if len(m.searchInput) > 0 {
+			runes := []rune(m.searchInput)
+			m.searchInput = string(runes[:len(runes)-1])
+		}
+		return m, nil
+	case " ":
+		m.searchInput += " "
+		return m, nil
+	}
+	if msg.Type == tea.KeyRunes {
+		m.searchInput += string(msg.Runes)
+	}
+	return m, nil
+}
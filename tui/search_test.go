@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/laupski/bored/azdo"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSearchMatch(t *testing.T) {
+	wi := azdo.WorkItem{ID: 42, Fields: azdo.WorkItemFields{Title: "Fix Login page", Tags: "frontend; urgent"}}
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"42", "ID"},
+		{"#42", "ID"},
+		{"4", ""},
+		{"login", "title"},
+		{"URGENT", "tags"},
+		{"page", "title"},
+		{"backend", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := searchMatch(wi, tt.text); got != tt.want {
+			t.Errorf("searchMatch(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestBoardSearch(t *testing.T) {
+	var wiql string
+	m := setupBoardModel()
+	m.client = mockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/wiql") {
+			var body struct {
+				Query string `json:"query"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			wiql = body.Query
+			_ = json.NewEncoder(w).Encode(azdo.WorkItemQueryResult{WorkItems: []azdo.WorkItemRef{{ID: 2}}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(azdo.WorkItemListResponse{
+			Count: 1,
+			Value: []azdo.WorkItem{{ID: 2, Fields: azdo.WorkItemFields{Title: "Second Item", Tags: "backend"}}},
+		})
+	})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m = updated.(Model)
+	if !m.searching {
+		t.Fatal("Expected / to open the search prompt")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("second")})
+	m = updated.(Model)
+	if !strings.Contains(m.View(), "1 of 2 loaded items match") {
+		t.Error("Expected the prompt to count matching loaded items")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.searching || m.searchText != "second" || cmd == nil {
+		t.Fatalf("Expected enter to apply the search, got %q", m.searchText)
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if !strings.Contains(wiql, "[System.Title] CONTAINS 'second' OR [System.Tags] CONTAINS 'second'") {
+		t.Errorf("Expected the query to search titles and tags, got %s", wiql)
+	}
+	view := m.View()
+	if !strings.Contains(view, `(search: "second")`) || !strings.Contains(view, "[title] Second Item") {
+		t.Error("Expected the search and the matched field to be shown")
+	}
+
+	// esc clears the search when nothing is selected
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.searchText != "" || cmd == nil {
+		t.Error("Expected esc to clear the search and refetch")
+	}
+}
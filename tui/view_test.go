@@ -137,6 +137,7 @@ func TestViewCreateContainsFields(t *testing.T) {
 }
 
 func TestViewDetailShowsWorkItemInfo(t *testing.T) {
+	priority := 1
 	m := NewModel()
 	m.view = ViewDetail
 	m.selectedItem = &azdo.WorkItem{
@@ -146,7 +147,7 @@ func TestViewDetailShowsWorkItemInfo(t *testing.T) {
 			State:        "Active",
 			WorkItemType: "Bug",
 			AreaPath:     "Project\\Team",
-			Priority:     1,
+			Priority:     &priority,
 			AssignedTo:   &azdo.IdentityRef{DisplayName: "John Doe", UniqueName: "john@example.com"},
 		},
 	}
@@ -292,3 +293,46 @@ func TestViewDetailHelp(t *testing.T) {
 		t.Error("Detail view should mention save shortcut")
 	}
 }
+
+func TestConnectionBannerOnAllScreens(t *testing.T) {
+	m := setupBoardModel()
+	m.client = azdo.NewClient("contoso", "Fabrikam", "Platform Team", "", "testpat")
+
+	if got := m.connectionLabel(); got != "contoso/Fabrikam (Platform Team)" {
+		t.Errorf("connectionLabel() = %q, want org/project (team)", got)
+	}
+
+	screens := map[string]string{"board": m.viewBoard(), "create": m.viewCreate()}
+	m.selectedItem = &m.workItems[0]
+	screens["detail"] = m.renderDetail()
+	for name, view := range screens {
+		if !strings.Contains(view, "contoso/Fabrikam (Platform Team)") {
+			t.Errorf("%s view should show the connection, got:\n%s", name, view)
+		}
+	}
+
+	m.client = azdo.NewClient("contoso", "Fabrikam", "", "", "testpat")
+	if got := m.connectionLabel(); got != "contoso/Fabrikam" {
+		t.Errorf("connectionLabel() = %q, want no team suffix without a team", got)
+	}
+
+	m.client = nil
+	if got := m.renderConnectionBanner(); got != "" {
+		t.Errorf("renderConnectionBanner() = %q, want empty before connecting", got)
+	}
+}
+
+func TestCharCountShownForFocusedTitle(t *testing.T) {
+	m := setupDetailModel()
+	m.detailFocus = 0
+	if !strings.Contains(m.View(), "10/255 characters") {
+		t.Error("detail view should show the title length while editing it")
+	}
+
+	m = NewModel()
+	m.view = ViewCreate
+	m.createInputs[0].SetValue("New title")
+	if !strings.Contains(m.View(), "9/255 characters") {
+		t.Error("create view should show the title length while editing it")
+	}
+}